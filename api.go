@@ -57,6 +57,11 @@ func setupServer(config *Config, handler *HandlerRegistry) *echo.Echo {
 	e.HTTPErrorHandler = func(err error, c echo.Context) {
 		NewAPIErr(c, err)
 	}
+	// without proxy the forwarded header is set by the client, it cannot be used for ip lockout
+	e.IPExtractor = echo.ExtractIPDirect()
+	if config.TrustProxy {
+		e.IPExtractor = echo.ExtractIPFromXFFHeader()
+	}
 	e.Use(middleware.Recover())
 	e.Use(middleware.RequestID())
 
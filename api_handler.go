@@ -11,21 +11,55 @@ type Response[T any] struct {
 }
 
 type HandlerRegistry struct {
-	User        *UserHandler
-	Movie       *MovieHandler
-	Room        *RoomHandler
-	Showtime    *ShowtimeHandler
-	Reservation *ReservationHandler
-	Cart        *CartHandler
+	User           *UserHandler
+	Movie          *MovieHandler
+	Room           *RoomHandler
+	Showtime       *ShowtimeHandler
+	Reservation    *ReservationHandler
+	Cart           *CartHandler
+	Planner        *PlannerHandler
+	Cinema         *CinemaHandler
+	Blackout       *BlackoutHandler
+	PricingRule    *PricingRuleHandler
+	TicketType     *TicketTypeHandler
+	Fee            *FeeHandler
+	PromoCode      *PromoCodeHandler
+	Point          *PointHandler
+	Wallet         *WalletHandler
+	Reconciliation *ReconciliationHandler
+	Ticket         *TicketHandler
+	Calendar       *CalendarHandler
+	MFA            *MFAHandler
+	LoginAttempt   *LoginAttemptHandler
+	OIDC           *OIDCHandler
+	Role           *RoleHandler
+	Profile        *ProfileHandler
 }
 
 func NewHandler(config *Config, trxProvider *TransactionProvider) *HandlerRegistry {
 	return &HandlerRegistry{
-		User:        NewUserHandler(config, trxProvider),
-		Movie:       NewMovieHandler(config, trxProvider),
-		Room:        NewRoomHandler(config, trxProvider),
-		Showtime:    NewShowtimeHandler(config, trxProvider),
-		Reservation: NewReservationHandler(config, trxProvider),
-		Cart:        NewCartHandler(config, trxProvider),
+		User:           NewUserHandler(config, trxProvider),
+		Movie:          NewMovieHandler(config, trxProvider),
+		Room:           NewRoomHandler(config, trxProvider),
+		Showtime:       NewShowtimeHandler(config, trxProvider),
+		Reservation:    NewReservationHandler(config, trxProvider),
+		Cart:           NewCartHandler(config, trxProvider),
+		Planner:        NewPlannerHandler(config, trxProvider),
+		Cinema:         NewCinemaHandler(config, trxProvider),
+		Blackout:       NewBlackoutHandler(config, trxProvider),
+		PricingRule:    NewPricingRuleHandler(config, trxProvider),
+		TicketType:     NewTicketTypeHandler(config, trxProvider),
+		Fee:            NewFeeHandler(config, trxProvider),
+		PromoCode:      NewPromoCodeHandler(config, trxProvider),
+		Point:          NewPointHandler(config, trxProvider),
+		Wallet:         NewWalletHandler(config, trxProvider),
+		Reconciliation: NewReconciliationHandler(config, trxProvider),
+		Ticket:         NewTicketHandler(config, trxProvider),
+		Calendar:       NewCalendarHandler(config, trxProvider),
+		MFA:            NewMFAHandler(config, trxProvider),
+		LoginAttempt:   NewLoginAttemptHandler(config, trxProvider),
+		OIDC:           NewOIDCHandler(config, trxProvider),
+		Role:           NewRoleHandler(config, trxProvider),
+		Profile:        NewProfileHandler(config, trxProvider),
 	}
 }
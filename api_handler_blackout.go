@@ -0,0 +1,209 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewBlackoutHandler(c *Config, trxProvider *TransactionProvider) *BlackoutHandler {
+	return &BlackoutHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type BlackoutHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Create
+//
+//	@Summary		Create Blackout
+//	@Description	admin create blackout
+//	@Tags			blackouts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			request			body		BlackoutInput	true	"body request"
+//	@Success		200				{object}	Response[Blackout]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/blackouts [post]
+func (h *BlackoutHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input BlackoutInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var blackout *Blackout
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		blackout, err = service.Blackout.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Blackout]{Message: "ok", Data: blackout})
+}
+
+// UpdateByID
+//
+//	@Summary		Update Blackout
+//	@Description	admin update blackout by id
+//	@Tags			blackouts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			id				path		int			true	"blackout id"
+//	@Param			request			body		BlackoutInput	true	"body request"
+//	@Success		200				{object}	Response[Blackout]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/blackouts/{id} [put]
+func (h *BlackoutHandler) UpdateByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input BlackoutInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var blackout *Blackout
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		blackout, err = service.Blackout.UpdateByID(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Blackout]{Message: "ok", Data: blackout})
+}
+
+// GetByID
+//
+//	@Summary		Get Blackout
+//	@Description	admin get blackout by id
+//	@Tags			blackouts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"blackout id"
+//	@Success		200				{object}	Response[Blackout]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/blackouts/{id} [get]
+func (h *BlackoutHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var blackout *Blackout
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		blackout, err = service.Blackout.GetByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Blackout]{Message: "ok", Data: blackout})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete Blackout
+//	@Description	admin delete blackout by id
+//	@Tags			blackouts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"blackout id"
+//	@Success		200				{object}	Response[any]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/blackouts/{id} [delete]
+func (h *BlackoutHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.Blackout.DeleteByID(ctx, int64(ID))
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// Pagination
+//
+//	@Summary		Filter Blackout
+//	@Description	admin filter blackouts
+//	@Tags			blackouts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			page			query		int				false	"pagination page"
+//	@Param			per_page		query		int				false	"pagination page size"
+//	@Param			request			body		BlackoutFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[Blackout]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/blackouts/filter [post]
+func (h *BlackoutHandler) Pagination(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter BlackoutFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[Blackout]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Blackout.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[Blackout]]{Message: "ok", Data: res})
+}
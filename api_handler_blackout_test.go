@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlackout(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	newGenre, rec := testCreateGenre(t, token, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newGenre)
+
+	newMovie, rec := testCreateMovie(t, token, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    60,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{newGenre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newMovie)
+
+	newCinema, rec := testCreateCinema(t, token, CinemaInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newCinema)
+
+	newRoom, rec := testCreateRoom(t, token, RoomInput{Name: randomString(5), CinemaID: newCinema.ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newRoom)
+
+	startAt := time.Now().Add(24 * time.Hour).Truncate(time.Hour)
+
+	t.Run("CreateFailNoTarget", func(t *testing.T) {
+		_, rec := testCreateBlackout(t, token, BlackoutInput{
+			StartAt: startAt,
+			EndAt:   startAt.Add(time.Hour),
+			Reason:  "maintenance",
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("RoomBlockShowtime", func(t *testing.T) {
+		blackout, rec := testCreateBlackout(t, token, BlackoutInput{
+			RoomID:  newRoom.ID,
+			StartAt: startAt,
+			EndAt:   startAt.Add(2 * time.Hour),
+			Reason:  "maintenance",
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, blackout)
+
+		_, rec = testCreateShowtime(t, token, ShowtimeInput{
+			MovieID: newMovie.ID,
+			RoomID:  newRoom.ID,
+			StartAt: startAt.Add(time.Hour),
+			EndAt:   startAt.Add(time.Hour).Add(newMovie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		showtime, rec := testCreateShowtime(t, token, ShowtimeInput{
+			MovieID: newMovie.ID,
+			RoomID:  newRoom.ID,
+			StartAt: startAt.Add(3 * time.Hour),
+			EndAt:   startAt.Add(3 * time.Hour).Add(newMovie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, showtime)
+
+		// move the showtime into blackout
+		_, rec = testUpdateShowtime(t, token, showtime.ID, ShowtimeInput{
+			MovieID: newMovie.ID,
+			RoomID:  newRoom.ID,
+			StartAt: startAt,
+			EndAt:   startAt.Add(newMovie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		schedule, rec := testGetRoomSchedule(t, newRoom.ID, startAt.Add(-time.Hour), startAt.Add(24*time.Hour))
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, schedule)
+		require.Len(t, schedule.Blackouts, 1)
+		require.Equal(t, blackout.ID, schedule.Blackouts[0].ID)
+		require.Len(t, schedule.Showtimes, 1)
+		require.Equal(t, showtime.ID, schedule.Showtimes[0].ID)
+	})
+
+	t.Run("CinemaBlockShowtime", func(t *testing.T) {
+		cinemaStartAt := startAt.Add(48 * time.Hour)
+		blackout, rec := testCreateBlackout(t, token, BlackoutInput{
+			CinemaID: newCinema.ID,
+			StartAt:  cinemaStartAt,
+			EndAt:    cinemaStartAt.Add(4 * time.Hour),
+			Reason:   "private event",
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, blackout)
+
+		_, rec = testCreateShowtime(t, token, ShowtimeInput{
+			MovieID: newMovie.ID,
+			RoomID:  newRoom.ID,
+			StartAt: cinemaStartAt.Add(time.Hour),
+			EndAt:   cinemaStartAt.Add(time.Hour).Add(newMovie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		rec = testDeleteBlackout(t, token, blackout.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		showtime, rec := testCreateShowtime(t, token, ShowtimeInput{
+			MovieID: newMovie.ID,
+			RoomID:  newRoom.ID,
+			StartAt: cinemaStartAt.Add(time.Hour),
+			EndAt:   cinemaStartAt.Add(time.Hour).Add(newMovie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, showtime)
+	})
+}
+
+func testCreateBlackout(t *testing.T, token string, input BlackoutInput) (*Blackout, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/blackouts", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Blackout]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testDeleteBlackout(t *testing.T, token string, ID int64) *httptest.ResponseRecorder {
+	uri := fmt.Sprintf("/api/admin/blackouts/%d", ID)
+	req := httptest.NewRequest(http.MethodDelete, uri, nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func testGetRoomSchedule(t *testing.T, ID int64, from, to time.Time) (*RoomSchedule, *httptest.ResponseRecorder) {
+	q := make(url.Values)
+	q.Set("from", from.UTC().Format(time.RFC3339))
+	q.Set("to", to.UTC().Format(time.RFC3339))
+	uri := fmt.Sprintf("/api/rooms/%d/schedule?%s", ID, q.Encode())
+
+	req := httptest.NewRequest(http.MethodGet, uri, nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*RoomSchedule]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const MIMETextCalendar = "text/calendar; charset=utf-8"
+
+func NewCalendarHandler(c *Config, trxProvider *TransactionProvider) *CalendarHandler {
+	return &CalendarHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type CalendarHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Reservation
+//
+//	@Summary		Reservation Calendar
+//	@Description	user get the reserved showtime as iCalendar event
+//	@Tags			calendars
+//	@Accept			json
+//	@Produce		text/calendar
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Success		200				{file}		binary
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/calendar.ics [get]
+func (h *CalendarHandler) Reservation(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var calendar []byte
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		calendar, err = service.Calendar.Reservation(ctx, userID, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="reservation-%d.ics"`, ID))
+	return c.Blob(http.StatusOK, MIMETextCalendar, calendar)
+}
+
+// Cinema
+//
+//	@Summary		Cinema Calendar
+//	@Description	feed of upcoming showtimes of the cinema as iCalendar
+//	@Tags			calendars
+//	@Accept			json
+//	@Produce		text/calendar
+//	@Param			id	path		int	true	"cinema id"
+//	@Success		200	{file}		binary
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/cinemas/{id}/showtimes.ics [get]
+func (h *CalendarHandler) Cinema(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var calendar []byte
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		calendar, err = service.Calendar.Cinema(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.Blob(http.StatusOK, MIMETextCalendar, calendar)
+}
+
+// Movie
+//
+//	@Summary		Movie Calendar
+//	@Description	feed of upcoming showtimes of the movie as iCalendar
+//	@Tags			calendars
+//	@Accept			json
+//	@Produce		text/calendar
+//	@Param			id	path		int	true	"movie id"
+//	@Success		200	{file}		binary
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/movies/{id}/showtimes.ics [get]
+func (h *CalendarHandler) Movie(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var calendar []byte
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		calendar, err = service.Calendar.Movie(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.Blob(http.StatusOK, MIMETextCalendar, calendar)
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCalendar(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	cinema, rec := testCreateCinema(t, tokenAdmin, CinemaInput{Name: randomString(5), Address: "Jl. Sudirman, Jakarta"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5), CinemaID: cinema.ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	showtimeUID := fmt.Sprintf("UID:showtime-%d@", showtime.ID)
+
+	rec = testGetCalendar("", fmt.Sprintf("/api/cinemas/%d/showtimes.ics", cinema.ID))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, strings.HasPrefix(rec.Header().Get(echo.HeaderContentType), "text/calendar"))
+	require.Contains(t, rec.Body.String(), showtimeUID)
+	require.Contains(t, rec.Body.String(), "DTSTART:"+startAt.Format(icsTimeLayout))
+
+	rec = testGetCalendar("", fmt.Sprintf("/api/movies/%d/showtimes.ics", movie.ID))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), showtimeUID)
+	require.Contains(t, rec.Body.String(), "LOCATION:"+room.Name+"\\, "+cinema.Name)
+
+	userInput := UserInput{
+		Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	path := fmt.Sprintf("/api/reservations/%d/calendar.ics", reservation.ID)
+	uid := fmt.Sprintf("UID:reservation-%d-showtime-%d@", reservation.ID, showtime.ID)
+
+	rec = testGetCalendar(token, path)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), uid)
+	require.Contains(t, rec.Body.String(), "STATUS:TENTATIVE")
+
+	rec = testGetCalendar(tokenAdmin, path)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// the same event is updated when paid then cancelled
+	_, rec = testPayReservation(t, token, reservation.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testGetCalendar(token, path)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), uid)
+	require.Contains(t, rec.Body.String(), "STATUS:CONFIRMED")
+
+	_, rec = testCancelReservation(t, token, reservation.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testGetCalendar(token, path)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), uid)
+	require.Contains(t, rec.Body.String(), "STATUS:CANCELLED")
+}
+
+func TestRenderCalendar(t *testing.T) {
+	now := time.Date(2024, 11, 27, 10, 0, 0, 0, time.UTC)
+	calendar := string(RenderCalendar("Feed", []CalendarEvent{{
+		UID:      "showtime-1@" + calendarDomain,
+		Summary:  "Movie; with, special\nchars",
+		Location: strings.Repeat("Ruang ü ", 20),
+		Start:    now,
+		End:      now.Add(2 * time.Hour),
+		Stamp:    now,
+		Status:   CalendarConfirmed,
+	}}))
+
+	require.Contains(t, calendar, "SUMMARY:Movie\\; with\\, special\\nchars\r\n")
+	require.Contains(t, calendar, "DTEND:20241127T120000Z\r\n")
+	for _, line := range strings.Split(strings.TrimSuffix(calendar, "\r\n"), "\r\n") {
+		require.LessOrEqual(t, len(line), 75)
+		require.True(t, utf8.ValidString(line))
+	}
+}
+
+func testGetCalendar(token string, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, token)
+	}
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
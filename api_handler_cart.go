@@ -219,3 +219,51 @@ func (h *CartHandler) UserGetPagination(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, Response[*Paginate[Cart]]{Message: "ok", Data: res})
 }
+
+// AdminGetPagination
+//
+//	@Summary		Filter User Cart
+//	@Description	admin filter carts of the user
+//	@Tags			carts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			id				path		int			true	"user id"
+//	@Param			page			query		int			false	"pagination page"
+//	@Param			per_page		query		int			false	"pagination page size"
+//	@Param			request			body		CartFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[Cart]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id}/carts/filter [post]
+func (h *CartHandler) AdminGetPagination(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	page := GetPage(c)
+
+	var filter CartFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	filter.UserIDs = []int64{int64(userID)}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[Cart]
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Cart.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[Cart]]{Message: "ok", Data: res})
+}
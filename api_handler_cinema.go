@@ -0,0 +1,207 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewCinemaHandler(c *Config, trxProvider *TransactionProvider) *CinemaHandler {
+	return &CinemaHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type CinemaHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Create
+//
+//	@Summary		Create Cinema
+//	@Description	admin create cinema
+//	@Tags			cinemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			request			body		CinemaInput	true	"body request"
+//	@Success		200				{object}	Response[Cinema]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/cinemas [post]
+func (h *CinemaHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input CinemaInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var cinema *Cinema
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		cinema, err = service.Cinema.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Cinema]{Message: "ok", Data: cinema})
+}
+
+// UpdateByID
+//
+//	@Summary		Update Cinema
+//	@Description	admin update cinema by id
+//	@Tags			cinemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			id				path		int			true	"cinema id"
+//	@Param			request			body		CinemaInput	true	"body request"
+//	@Success		200				{object}	Response[Cinema]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/cinemas/{id} [put]
+func (h *CinemaHandler) UpdateByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input CinemaInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var cinema *Cinema
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		cinema, err = service.Cinema.UpdateByID(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Cinema]{Message: "ok", Data: cinema})
+}
+
+// GetByID
+//
+//	@Summary		Get Cinema
+//	@Description	get cinema by id
+//	@Tags			cinemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"cinema id"
+//	@Success		200	{object}	Response[Cinema]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/cinemas/{id} [get]
+func (h *CinemaHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var cinema *Cinema
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		cinema, err = service.Cinema.GetByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Cinema]{Message: "ok", Data: cinema})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete Cinema
+//	@Description	admin delete cinema by id
+//	@Tags			cinemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"cinema id"
+//	@Success		200				{object}	Response[any]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/cinemas/{id} [delete]
+func (h *CinemaHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.Cinema.DeleteByID(ctx, int64(ID))
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// Pagination
+//
+//	@Summary		Filter Cinema
+//	@Description	filter cinemas
+//	@Tags			cinemas
+//	@Accept			json
+//	@Produce		json
+//	@Param			page		query		int			false	"pagination page"
+//	@Param			per_page	query		int			false	"pagination page size"
+//	@Param			request		body		CinemaFilter	false	"filter"
+//	@Success		200			{object}	Response[Paginate[Cinema]]
+//	@Failure		400			{object}	Response[any]
+//	@Failure		500			{object}	Response[any]
+//	@Router			/api/cinemas/filter [post]
+func (h *CinemaHandler) Pagination(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter CinemaFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[Cinema]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Cinema.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[Cinema]]{Message: "ok", Data: res})
+}
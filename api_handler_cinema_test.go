@@ -0,0 +1,201 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateCinemaOK(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	input := CinemaInput{
+		Name:    randomString(5),
+		Address: randomString(10),
+	}
+	newCinema, rec := testCreateCinema(t, token, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newCinema)
+	require.Equal(t, input.Name, newCinema.Name)
+	require.Equal(t, input.Address, newCinema.Address)
+}
+
+func TestCreateCinemaCurrency(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	cinema, rec := testCreateCinema(t, token, CinemaInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, DefaultCurrency, cinema.Currency)
+
+	cinema, rec = testCreateCinema(t, token, CinemaInput{Name: randomString(5), Currency: " usd "})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, Currency("USD"), cinema.Currency)
+
+	// fee of the cinema must be in its currency
+	_, rec = testCreateFee(t, token, FeeInput{Name: randomString(5), Kind: FeePerOrder, Amount: 100, CinemaID: cinema.ID})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testCreateFee(t, token, FeeInput{Name: randomString(5), Kind: FeePerOrder, Amount: 100, Currency: "USD", CinemaID: cinema.ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// currency is kept while the cinema has fees
+	_, rec = testUpdateCinema(t, token, cinema.ID, CinemaInput{Name: cinema.Name, Currency: DefaultCurrency})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testCreateCinema(t, token, CinemaInput{Name: randomString(5), Currency: "XYZ"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestMoney(t *testing.T) {
+	total, err := NewMoney(1_250, "USD").Add(NewMoney(50, "USD"))
+	require.NoError(t, err)
+	require.Equal(t, "USD 13.00", total.String())
+	require.Equal(t, "IDR 50000", NewMoney(50_000, "IDR").String())
+
+	_, err = NewMoney(1_000, "USD").Add(NewMoney(1_000, "IDR"))
+	require.Error(t, err)
+
+	_, err = NewMoney(math.MaxInt64, "IDR").Add(NewMoney(1, "IDR"))
+	require.Error(t, err)
+	_, err = NewMoney(math.MaxInt64/2+1, "IDR").Mul(2)
+	require.Error(t, err)
+
+	require.Equal(t, int64(11_770), Tax(107_000, 1_100))
+	require.Positive(t, Tax(math.MaxInt64, 1_100))
+
+	rule := PricingRule{IsActive: true, Percent: 50, MaxOccupancy: 100}
+	snapshot, err := ApplyPricingRules(100_000, "IDR", PricingContext{Now: time.Now()}, []PricingRule{rule})
+	require.NoError(t, err)
+	require.Equal(t, int64(150_000), snapshot.Price)
+	_, err = ApplyPricingRules(math.MaxInt64/2, "IDR", PricingContext{Now: time.Now()}, []PricingRule{rule})
+	require.Error(t, err)
+
+	// fixed amount only adjust price of its own currency, percent apply to any
+	rupiahRule := PricingRule{IsActive: true, Percent: -10, Amount: -5_000, Currency: "IDR", MaxOccupancy: 100}
+	snapshot, err = ApplyPricingRules(1_000, "USD", PricingContext{Now: time.Now()}, []PricingRule{rule, rupiahRule})
+	require.NoError(t, err)
+	require.Equal(t, int64(1_500), snapshot.Price)
+	require.Len(t, snapshot.Rules, 1)
+
+	snapshot = PriceSnapshot{Price: math.MaxInt64}
+	require.Error(t, snapshot.ApplyTicketType(TicketType{Amount: 1, Currency: "IDR"}, "IDR"))
+	snapshot = PriceSnapshot{Price: 1_000}
+	require.Error(t, snapshot.ApplyTicketType(TicketType{Amount: -500, Currency: "IDR"}, "USD"))
+	require.NoError(t, snapshot.ApplyTicketType(TicketType{Percent: -50, Currency: "IDR"}, "USD"))
+	require.Equal(t, int64(500), snapshot.Price)
+
+	promoCode := PromoCode{DiscountType: PromoDiscountAmount, DiscountValue: 5_000, Currency: "IDR"}
+	lines := []PromoLine{{Price: 10_000}}
+	_, err = promoCode.Discount("USD", 10_000, lines)
+	require.Error(t, err)
+	discount, err := promoCode.Discount("IDR", 10_000, lines)
+	require.NoError(t, err)
+	require.Equal(t, int64(5_000), discount)
+}
+
+func TestCreateCinemaFailDuplicate(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	input := CinemaInput{Name: randomString(5)}
+	newCinema, rec := testCreateCinema(t, token, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newCinema)
+
+	_, rec = testCreateCinema(t, token, input)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestUpdateCinemaOK(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	newCinema, rec := testCreateCinema(t, token, CinemaInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newCinema)
+
+	updated, rec := testUpdateCinema(t, token, newCinema.ID, CinemaInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, updated)
+	require.Equal(t, newCinema.ID, updated.ID)
+	require.NotEqual(t, newCinema.Name, updated.Name)
+}
+
+func TestGetCinemaOK(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	newCinema, rec := testCreateCinema(t, token, CinemaInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newCinema)
+
+	newRoom, rec := testCreateRoom(t, token, RoomInput{Name: randomString(5), CinemaID: newCinema.ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newRoom)
+	require.Equal(t, newCinema.ID, newRoom.CinemaID)
+
+	cur, rec := testGetCinema(t, newCinema.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, cur)
+	require.Equal(t, newCinema.Name, cur.Name)
+	require.Equal(t, int64(1), cur.TotalRoom)
+}
+
+func TestGetCinemaFailNotFound(t *testing.T) {
+	cur, rec := testGetCinema(t, -1)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+	require.Nil(t, cur)
+}
+
+func testCreateCinema(t *testing.T, token string, input CinemaInput) (*Cinema, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/cinemas", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Cinema]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testUpdateCinema(t *testing.T, token string, ID int64, input CinemaInput) (*Cinema, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	uri := fmt.Sprintf("/api/admin/cinemas/%d", ID)
+	req := httptest.NewRequest(http.MethodPut, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Cinema]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testGetCinema(t *testing.T, ID int64) (*Cinema, *httptest.ResponseRecorder) {
+	uri := fmt.Sprintf("/api/cinemas/%d", ID)
+	req := httptest.NewRequest(http.MethodGet, uri, nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Cinema]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
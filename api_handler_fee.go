@@ -0,0 +1,238 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewFeeHandler(c *Config, trxProvider *TransactionProvider) *FeeHandler {
+	return &FeeHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type FeeHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Create
+//
+//	@Summary		Create Fee
+//	@Description	admin create fee
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			request			body		FeeInput	true	"body request"
+//	@Success		200				{object}	Response[Fee]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/fees [post]
+func (h *FeeHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input FeeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var fee *Fee
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		fee, err = service.Fee.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Fee]{Message: "ok", Data: fee})
+}
+
+// UpdateByID
+//
+//	@Summary		Update Fee
+//	@Description	admin update fee by id
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			id				path		int			true	"fee id"
+//	@Param			request			body		FeeInput	true	"body request"
+//	@Success		200				{object}	Response[Fee]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/fees/{id} [put]
+func (h *FeeHandler) UpdateByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input FeeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var fee *Fee
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		fee, err = service.Fee.UpdateByID(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Fee]{Message: "ok", Data: fee})
+}
+
+// GetByID
+//
+//	@Summary		Get Fee
+//	@Description	get fee by id
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"fee id"
+//	@Success		200	{object}	Response[Fee]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/fees/{id} [get]
+func (h *FeeHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var fee *Fee
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		fee, err = service.Fee.GetByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Fee]{Message: "ok", Data: fee})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete Fee
+//	@Description	admin delete fee by id
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"fee id"
+//	@Success		200				{object}	Response[any]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/fees/{id} [delete]
+func (h *FeeHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.Fee.DeleteByID(ctx, int64(ID))
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// List
+//
+//	@Summary		List Fee
+//	@Description	list available fees
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	Response[[]Fee]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/fees [get]
+func (h *FeeHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	active := true
+	filter := FeeFilter{IsActive: &active}
+
+	var fees []Fee
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		fees, err = service.Fee.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]Fee]{Message: "ok", Data: fees})
+}
+
+// Filter
+//
+//	@Summary		Filter Fee
+//	@Description	admin filter fees, including inactive one
+//	@Tags			fees
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			request			body		FeeFilter	false	"filter"
+//	@Success		200				{object}	Response[[]Fee]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/fees/filter [post]
+func (h *FeeHandler) Filter(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var filter FeeFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var fees []Fee
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		fees, err = service.Fee.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]Fee]{Message: "ok", Data: fees})
+}
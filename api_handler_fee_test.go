@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFee(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	t.Run("CreateFailKindInvalid", func(t *testing.T) {
+		_, rec := testCreateFee(t, tokenAdmin, FeeInput{Name: randomString(5), Kind: "per_seat", Amount: 1000})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("CreateFailAmountInvalid", func(t *testing.T) {
+		_, rec := testCreateFee(t, tokenAdmin, FeeInput{Name: randomString(5), Kind: FeePerOrder})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Receipt", func(t *testing.T) {
+		cinema, rec := testCreateCinema(t, tokenAdmin, CinemaInput{Name: randomString(5), Address: randomString(8), TaxRate: 1100})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, int64(1100), cinema.TaxRate)
+
+		ticketFee, rec := testCreateFee(t, tokenAdmin, FeeInput{Name: "Booking fee", Kind: FeePerTicket, Amount: 2_000, CinemaID: cinema.ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.True(t, ticketFee.IsActive)
+		_, rec = testCreateFee(t, tokenAdmin, FeeInput{Name: "Service fee", Kind: FeePerOrder, Amount: 3_000, CinemaID: cinema.ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		inactive := false
+		_, rec = testCreateFee(t, tokenAdmin, FeeInput{Name: "Inactive fee", Kind: FeePerOrder, Amount: 9_000, CinemaID: cinema.ID, IsActive: &inactive})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+		require.Equal(t, http.StatusOK, rec.Code)
+		movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+			Title:       randomString(5),
+			ReleaseDate: time.Now(),
+			Director:    randomString(5),
+			Duration:    33,
+			PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+			Description: randomString(5),
+			GenreIDs:    []int64{genre.ID},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5), CinemaID: cinema.ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{
+			{Name: randomString(5)},
+			{Name: randomString(5)},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		seats, rec := testListRoomSeats(t, room.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		showStartAt := time.Now().Add(3 * 24 * time.Hour)
+		showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+			MovieID: movie.ID,
+			RoomID:  room.ID,
+			StartAt: showStartAt,
+			EndAt:   showStartAt.Add(movie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		userInput := UserInput{
+			Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+			Password: "12345678",
+		}
+		_, rec = testRegisterUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+		token, rec := testLoginUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		cartIDs := []int64{}
+		for _, seat := range seats {
+			cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seat.ID})
+			require.Equal(t, http.StatusOK, rec.Code)
+			cartIDs = append(cartIDs, cart.ID)
+		}
+
+		reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: cartIDs})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, cinema.ID, reservation.CinemaID)
+		require.Equal(t, int64(100_000), reservation.SubtotalPrice)
+		require.Len(t, reservation.FeeItems, 2)
+		require.Equal(t, int64(7_000), reservation.Fees)
+		require.Equal(t, int64(1100), reservation.TaxRate)
+		require.Equal(t, int64(11_770), reservation.Tax)
+		require.Equal(t, int64(118_770), reservation.TotalPrice)
+
+		// receipt is issued only once paid
+		_, rec = testGetReceipt(t, token, reservation.ID)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		_, rec = testPayReservation(t, token, reservation.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		receipt, rec := testGetReceipt(t, token, reservation.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.True(t, strings.HasPrefix(receipt.Number, "R"))
+		require.Equal(t, FormatReceiptNumber(cinema.ID, 1), receipt.Number)
+		require.Equal(t, userInput.Email, receipt.Customer)
+		require.Equal(t, cinema.Name, receipt.Cinema)
+		require.Len(t, receipt.Lines, 2)
+		require.Equal(t, int64(7_000), receipt.FeeTotal)
+		require.Equal(t, int64(11_770), receipt.Tax)
+		require.Equal(t, int64(118_770), receipt.Total)
+
+		rec = testGetReceiptAs(token, reservation.ID, ReceiptText)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Contains(t, rec.Body.String(), receipt.Number)
+		require.Contains(t, rec.Body.String(), "Booking fee")
+
+		rec = testGetReceiptAs(token, reservation.ID, ReceiptHTML)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Contains(t, rec.Header().Get(echo.HeaderContentType), echo.MIMETextHTML)
+		require.Contains(t, rec.Body.String(), receipt.Number)
+
+		// receipt of other user is not visible
+		otherToken := testLoginAdmin(t)
+		_, rec = testGetReceipt(t, otherToken, reservation.ID)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+
+		// next receipt of the cinema continue the count, refused payment take no number
+		nextShowtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+			MovieID: movie.ID,
+			RoomID:  room.ID,
+			StartAt: showtime.EndAt,
+			EndAt:   showtime.EndAt.Add(movie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: nextShowtime.ID, SeatID: seats[0].ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		nextReservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		_, rec = testPayReservation(t, otherToken, nextReservation.ID)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		_, rec = testPayReservation(t, token, nextReservation.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		receipt, rec = testGetReceipt(t, token, nextReservation.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, FormatReceiptNumber(cinema.ID, 2), receipt.Number)
+	})
+}
+
+func testCreateFee(t *testing.T, token string, input FeeInput) (*Fee, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/fees", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Fee]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testGetReceipt(t *testing.T, token string, ID int64) (*Receipt, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/reservations/%d/receipt", ID), nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Receipt]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testGetReceiptAs(token string, ID int64, format ReceiptFormat) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/reservations/%d/receipt?format=%s", ID, format), nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
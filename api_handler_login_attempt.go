@@ -0,0 +1,98 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewLoginAttemptHandler(c *Config, trxProvider *TransactionProvider) *LoginAttemptHandler {
+	return &LoginAttemptHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type LoginAttemptHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Pagination
+//
+//	@Summary		Filter Login Attempt
+//	@Description	admin audit login attempts, including unknown email
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			page			query		int					false	"pagination page"
+//	@Param			per_page		query		int					false	"pagination page size"
+//	@Param			request			body		LoginAttemptFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[LoginAttempt]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/login-attempts/filter [post]
+func (h *LoginAttemptHandler) Pagination(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter LoginAttemptFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[LoginAttempt]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.LoginAttempt.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[LoginAttempt]]{Message: "ok", Data: res})
+}
+
+// Unlock
+//
+//	@Summary		Unlock User
+//	@Description	admin clear failed login of the user so the account is no longer locked, lock of the ip is kept
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"user id"
+//	@Success		200				{object}	Response[User]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id}/unlock [post]
+func (h *LoginAttemptHandler) Unlock(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var user *User
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		user, err = service.LoginAttempt.Unlock(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*User]{Message: "ok", Data: user})
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoginLockout(t *testing.T) {
+	ip := "198.51.100.46"
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	user, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	wrong := UserInput{Email: strings.ToUpper(input.Email), Password: "wrong-password"}
+	for range 5 {
+		rec = testLoginFrom(t, wrong, ip)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+
+	// locked even with correct password
+	rec = testLoginFrom(t, input, ip)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	retryAfter, err := strconv.Atoi(rec.Header().Get(echo.HeaderRetryAfter))
+	require.NoError(t, err)
+	require.True(t, retryAfter > 0 && retryAfter <= 30)
+
+	// other account from the same ip is not locked
+	_, rec = testLoginUser(t, UserInput{Email: "admin@gmail.com", Password: "12345678"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	adminToken := testLoginAdmin(t)
+	failed := false
+	attempts, rec := testFilterLoginAttempt(t, adminToken, LoginAttemptFilter{Emails: []string{input.Email}, Success: &failed})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(5), attempts.TotalItems)
+	for _, attempt := range attempts.Items {
+		require.Equal(t, user.ID, *attempt.UserID)
+		require.Equal(t, ip, attempt.IP)
+		require.Equal(t, LoginStepPassword, attempt.Step)
+	}
+
+	_, rec = testUnlockUser(t, adminToken, user.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testLoginFrom(t, input, ip)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// success reset the failures
+	rec = testLoginFrom(t, wrong, ip)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = testLoginFrom(t, input, ip)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLoginIPLockout(t *testing.T) {
+	ip := "198.51.100.47"
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// spread over unknown emails so no single account is locked
+	for range 20 {
+		rec = testLoginFrom(t, UserInput{Email: fmt.Sprintf("%s@mail.com", randomString(8)), Password: "12345678"}, ip)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	rec = testLoginFrom(t, input, ip)
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	rec = testLoginFrom(t, input, "198.51.100.48")
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLoginLockoutCorrectPassword(t *testing.T) {
+	ip := "198.51.100.51"
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	enrollment, rec := testMFAEnroll(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	now := time.Now()
+	code, err := totp.GenerateCode(enrollment.Secret, now)
+	require.NoError(t, err)
+	_, rec = testMFACode(t, token, http.MethodPost, "/api/user/mfa/confirm", code)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// one failure below the limit, the correct password must not lock the second step
+	wrong := UserInput{Email: input.Email, Password: "wrong-password"}
+	for range 4 {
+		rec = testLoginFrom(t, wrong, ip)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	rec = testLoginFrom(t, input, ip)
+	require.Equal(t, http.StatusOK, rec.Code)
+	var res Response[*AuthToken]
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+	require.True(t, res.Data.MFARequired)
+
+	nextCode, err := totp.GenerateCode(enrollment.Secret, now.Add(mfaPeriod*time.Second))
+	require.NoError(t, err)
+	session, rec := testLoginMFA(t, MFALoginInput{MFAToken: res.Data.MFAToken, Code: nextCode})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, session.Token)
+
+	// same for ip one failure below its limit
+	otherIP := "198.51.100.52"
+	for range 19 {
+		rec = testLoginFrom(t, UserInput{Email: fmt.Sprintf("%s@mail.com", randomString(8)), Password: "12345678"}, otherIP)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+	admin := UserInput{Email: "admin@gmail.com", Password: "12345678"}
+	rec = testLoginFrom(t, admin, otherIP)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testLoginFrom(t, admin, otherIP)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestLoginLockoutParallel(t *testing.T) {
+	ip := "198.51.100.50"
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// guesses sent at once are counted one by one, only the allowed failures reach the password check
+	wrong, err := json.Marshal(UserInput{Email: input.Email, Password: "wrong-password"})
+	require.NoError(t, err)
+	codes := make(chan int, 20)
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(wrong))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			req.RemoteAddr = ip + ":1234"
+			rec := httptest.NewRecorder()
+			testServer.ServeHTTP(rec, req)
+			codes <- rec.Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	checked := 0
+	for code := range codes {
+		if code == http.StatusBadRequest {
+			checked++
+			continue
+		}
+		require.Equal(t, http.StatusTooManyRequests, code)
+	}
+	require.Equal(t, 5, checked)
+}
+
+func TestLoginBackoff(t *testing.T) {
+	base, limit := 30*time.Second, 15*time.Minute
+	require.Equal(t, time.Duration(0), LoginBackoff(4, 5, base, limit))
+	require.Equal(t, 30*time.Second, LoginBackoff(5, 5, base, limit))
+	require.Equal(t, time.Minute, LoginBackoff(6, 5, base, limit))
+	require.Equal(t, 8*time.Minute, LoginBackoff(9, 5, base, limit))
+	require.Equal(t, limit, LoginBackoff(10, 5, base, limit))
+	require.Equal(t, limit, LoginBackoff(1_000, 5, base, limit))
+}
+
+func testLoginFrom(t *testing.T, input UserInput, ip string) *httptest.ResponseRecorder {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.RemoteAddr = ip + ":1234"
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func testFilterLoginAttempt(t *testing.T, token string, filter LoginAttemptFilter) (*Paginate[LoginAttempt], *httptest.ResponseRecorder) {
+	p, err := json.Marshal(filter)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/login-attempts/filter", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Paginate[LoginAttempt]]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testUnlockUser(t *testing.T, token string, ID int64) (*User, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, fmt.Sprintf("/api/admin/user/%d/unlock", ID), nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*User]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewMFAHandler(c *Config, trxProvider *TransactionProvider) *MFAHandler {
+	return &MFAHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type MFAHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Status
+//
+//	@Summary		Two-Factor Status
+//	@Description	two-factor status of current user and unused backup codes left
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string	true	"bearer token"
+//	@Produce		json
+//	@Success		200	{object}	Response[MFAStatus]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/user/mfa [get]
+func (h *MFAHandler) Status(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var status *MFAStatus
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		status, err = service.MFA.Status(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*MFAStatus]{Message: "ok", Data: status})
+}
+
+// Enroll
+//
+//	@Summary		Enroll Two-Factor
+//	@Description	create TOTP secret with provisioning URI and QR code, confirm with a code from the app to enable it
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string	true	"bearer token"
+//	@Produce		json
+//	@Success		200	{object}	Response[MFAEnrollment]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/user/mfa/enroll [post]
+func (h *MFAHandler) Enroll(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var enrollment *MFAEnrollment
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		enrollment, err = service.MFA.Enroll(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*MFAEnrollment]{Message: "ok", Data: enrollment})
+}
+
+// Confirm
+//
+//	@Summary		Confirm Two-Factor
+//	@Description	enable two-factor using a code from the app, backup codes are only shown in this response
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string			true	"bearer token"
+//	@Param			request			body	MFACodeInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[MFABackupCodes]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/user/mfa/confirm [post]
+func (h *MFAHandler) Confirm(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var input MFACodeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var codes *MFABackupCodes
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		codes, err = service.MFA.Confirm(ctx, userID, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*MFABackupCodes]{Message: "ok", Data: codes})
+}
+
+// RegenerateBackupCodes
+//
+//	@Summary		Regenerate Backup Codes
+//	@Description	replace every backup code using a code from the app
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string			true	"bearer token"
+//	@Param			request			body	MFACodeInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[MFABackupCodes]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/user/mfa/backup-codes [post]
+func (h *MFAHandler) RegenerateBackupCodes(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var input MFACodeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var codes *MFABackupCodes
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		codes, err = service.MFA.RegenerateBackupCodes(ctx, userID, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*MFABackupCodes]{Message: "ok", Data: codes})
+}
+
+// Disable
+//
+//	@Summary		Disable Two-Factor
+//	@Description	disable two-factor using a code from the app or a backup code
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string			true	"bearer token"
+//	@Param			request			body	MFACodeInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[any]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/user/mfa [delete]
+func (h *MFAHandler) Disable(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var input MFACodeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	err := h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.MFA.Disable(ctx, userID, input)
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
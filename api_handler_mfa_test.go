@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/pquerna/otp/totp"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMFA(t *testing.T) {
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	enrollment, rec := testMFAEnroll(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, strings.HasPrefix(enrollment.URI, "otpauth://totp/"))
+	require.True(t, strings.HasPrefix(enrollment.QRCode, "data:image/png;base64,"))
+
+	// not enabled until confirmed
+	status, rec := testMFAStatus(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.False(t, status.Enabled)
+
+	_, rec = testMFACode(t, token, http.MethodPost, "/api/user/mfa/confirm", "000000")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	now := time.Now()
+	code, err := totp.GenerateCode(enrollment.Secret, now)
+	require.NoError(t, err)
+	backup, rec := testMFACode(t, token, http.MethodPost, "/api/user/mfa/confirm", code)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, backup.Codes, 10)
+
+	// login become two steps
+	login, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, login.MFARequired)
+	require.NotEmpty(t, login.MFAToken)
+	require.Empty(t, login.Token)
+
+	// code cannot be replayed
+	_, rec = testLoginMFA(t, MFALoginInput{MFAToken: login.MFAToken, Code: code})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	nextCode, err := totp.GenerateCode(enrollment.Secret, now.Add(mfaPeriod*time.Second))
+	require.NoError(t, err)
+	session, rec := testLoginMFA(t, MFALoginInput{MFAToken: login.MFAToken, Code: nextCode})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, session.Token)
+	claims := new(JwtCustomClaims)
+	_, _, err = jwt.NewParser().ParseUnverified(session.Token, claims)
+	require.NoError(t, err)
+	require.True(t, claims.MFA)
+
+	// challenge is single-use
+	_, rec = testLoginMFA(t, MFALoginInput{MFAToken: login.MFAToken, Code: backup.Codes[0]})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// backup code is single-use
+	login, rec = testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testLoginMFA(t, MFALoginInput{MFAToken: login.MFAToken, Code: strings.ToUpper(backup.Codes[0])})
+	require.Equal(t, http.StatusOK, rec.Code)
+	login, rec = testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testLoginMFA(t, MFALoginInput{MFAToken: login.MFAToken, Code: backup.Codes[0]})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	status, rec = testMFAStatus(t, "Bearer "+session.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, status.Enabled)
+	require.Equal(t, int64(9), status.BackupCodes)
+
+	_, rec = testMFACode(t, "Bearer "+session.Token, http.MethodDelete, "/api/user/mfa", backup.Codes[1])
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAdminMFAMiddleware(t *testing.T) {
+	config := NewConfig()
+	next := func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	}
+
+	for _, tc := range []struct {
+		require bool
+		mfa     bool
+		code    int
+	}{
+		{false, false, http.StatusOK},
+		{true, false, http.StatusForbidden},
+		{true, true, http.StatusOK},
+	} {
+		config.RequireAdminMFA = tc.require
+		rec := httptest.NewRecorder()
+		c := echo.New().NewContext(httptest.NewRequest(http.MethodGet, "/", nil), rec)
+		c.Set("user", &jwt.Token{Claims: &JwtCustomClaims{Role: UserAdmin, MFA: tc.mfa}})
+		err := adminMFAMiddleware(config)(next)(c)
+		require.NoError(t, err)
+		require.Equal(t, tc.code, rec.Code)
+	}
+}
+
+func testMFAStatus(t *testing.T, token string) (*MFAStatus, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/mfa", nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*MFAStatus]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testMFAEnroll(t *testing.T, token string) (*MFAEnrollment, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/api/user/mfa/enroll", nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*MFAEnrollment]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testMFACode(t *testing.T, token, method, uri, code string) (*MFABackupCodes, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(MFACodeInput{Code: code})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(method, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*MFABackupCodes]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testLoginMFA(t *testing.T, input MFALoginInput) (*AuthToken, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login/mfa", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*AuthToken]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
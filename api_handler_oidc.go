@@ -0,0 +1,167 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewOIDCHandler(c *Config, trxProvider *TransactionProvider) *OIDCHandler {
+	return &OIDCHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type OIDCHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Providers
+//
+//	@Summary		List Identity Provider
+//	@Description	name of identity providers available to sign in
+//	@Tags			accounts
+//	@Produce		json
+//	@Success		200	{object}	Response[[]string]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/oidc/providers [get]
+func (h *OIDCHandler) Providers(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var providers []string
+	err := h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		providers = service.OIDC.Providers()
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]string]{Message: "ok", Data: providers})
+}
+
+// Authorize
+//
+//	@Summary		Authorize Identity Provider
+//	@Description	start sign in with the identity provider, redirect the user to the url.
+//	@Description	The state is also set in a short lived cookie, the callback has to be called from the same browser.
+//	@Description	The provider redirect back to the redirect url with code and state, send them to the callback
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			provider	path		string	true	"provider name"
+//	@Success		200			{object}	Response[OIDCAuthorization]
+//	@Failure		400			{object}	Response[any]
+//	@Failure		404			{object}	Response[any]
+//	@Failure		500			{object}	Response[any]
+//	@Router			/api/oidc/{provider}/authorize [get]
+func (h *OIDCHandler) Authorize(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var authorization *OIDCAuthorization
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		authorization, err = service.OIDC.Authorize(ctx, c.Param("provider"))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.SetCookie(h.stateCookie(authorization.State, int(oidcStateTTL.Seconds())))
+
+	return c.JSON(http.StatusOK, Response[*OIDCAuthorization]{Message: "ok", Data: authorization})
+}
+
+// Callback
+//
+//	@Summary		Callback Identity Provider
+//	@Description	complete sign in with code and state from the identity provider.
+//	@Description	The account is linked by verified email or created on first sign in
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			provider	path		string	true	"provider name"
+//	@Param			code		query		string	true	"authorization code"
+//	@Param			state		query		string	true	"state from authorize"
+//	@Success		200			{object}	Response[LoginUserRes]
+//	@Failure		400			{object}	Response[any]
+//	@Failure		404			{object}	Response[any]
+//	@Failure		500			{object}	Response[any]
+//	@Router			/api/oidc/{provider}/callback [get]
+func (h *OIDCHandler) Callback(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input OIDCCallbackInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var browserState string
+	if cookie, err := c.Cookie(oidcStateCookie); err == nil {
+		browserState = cookie.Value
+	}
+	// state is single use, the cookie is no longer needed whatever the result
+	c.SetCookie(h.stateCookie("", -1))
+
+	var token *AuthToken
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		token, err = service.OIDC.Callback(ctx, c.Param("provider"), input, browserState, c.Request().UserAgent())
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*LoginUserRes]{Message: "ok", Data: token})
+}
+
+// stateCookie bind the sign in to the browser, lax so it is sent on the redirect back from the provider
+func (h *OIDCHandler) stateCookie(state string, maxAge int) *http.Cookie {
+	return &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/oidc",
+		MaxAge:   maxAge,
+		HttpOnly: true,
+		Secure:   !h.config.DevMode,
+		SameSite: http.SameSiteLaxMode,
+	}
+}
+
+// Identities
+//
+//	@Summary		List Linked Identity
+//	@Description	identity provider accounts linked to the current user
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	Response[[]UserIdentity]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/identities [get]
+func (h *OIDCHandler) Identities(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var identities []UserIdentity
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		identities, err = service.OIDC.Identities(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]UserIdentity]{Message: "ok", Data: identities})
+}
@@ -0,0 +1,317 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const testOIDCClientID = "movie-reservation"
+
+var testOIDC *testOIDCProvider
+
+func TestOIDC(t *testing.T) {
+	providers, rec := testOIDCProviders(t)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{"mock"}, providers)
+
+	_, rec = testOIDCAuthorize(t, "unknown")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// first sign in create the user
+	email := fmt.Sprintf("%s@mail.com", randomString(5))
+	subject := randomString(10)
+	authorization, rec := testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+	require.Equal(t, oidcStateCookie, cookies[0].Name)
+	require.Equal(t, authorization.State, cookies[0].Value)
+	require.True(t, cookies[0].HttpOnly)
+	code := testOIDC.Approve(t, authorization, subject, strings.ToUpper(email), true)
+	token, rec := testOIDCCallback(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, token.Token)
+	require.NotEmpty(t, token.RefreshToken)
+
+	user, rec := testCurrentUser(t, "Bearer "+token.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, email, user.Email)
+	require.True(t, user.IsEmailVerified())
+
+	identities, rec := testOIDCIdentities(t, "Bearer "+token.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, identities, 1)
+	require.Equal(t, subject, identities[0].Subject)
+
+	// callback cannot be replayed
+	_, rec = testOIDCCallback(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// next sign in use the linked identity even when the email changed at the provider
+	authorization, rec = testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	code = testOIDC.Approve(t, authorization, subject, "changed-"+email, true)
+	token, rec = testOIDCCallback(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State})
+	require.Equal(t, http.StatusOK, rec.Code)
+	current, rec := testCurrentUser(t, "Bearer "+token.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, user.ID, current.ID)
+
+	// code is bound to the PKCE verifier of its own authorization
+	first, rec := testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	second, rec := testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	code = testOIDC.Approve(t, first, subject, email, true)
+	_, rec = testOIDCCallback(t, "mock", OIDCCallbackInput{Code: code, State: second.State})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// login CSRF, code and state of the attacker sent from the browser of the victim
+	authorization, rec = testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	victim, rec := testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	code = testOIDC.Approve(t, authorization, subject, email, true)
+	_, rec = testOIDCCallbackFrom(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State}, nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testOIDCCallbackFrom(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State}, &http.Cookie{Name: oidcStateCookie, Value: victim.State})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "browser")
+
+	// provider did not verify the email
+	authorization, rec = testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	code = testOIDC.Approve(t, authorization, randomString(10), fmt.Sprintf("%s@mail.com", randomString(5)), false)
+	_, rec = testOIDCCallback(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testOIDCCallback(t, "mock", OIDCCallbackInput{Error: "access_denied"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestOIDCLinkExistingUser(t *testing.T) {
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	user, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	authorization, rec := testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	code := testOIDC.Approve(t, authorization, randomString(10), input.Email, true)
+	token, rec := testOIDCCallback(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State})
+	require.Equal(t, http.StatusOK, rec.Code)
+	current, rec := testCurrentUser(t, "Bearer "+token.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, user.ID, current.ID)
+
+	// password login still work
+	_, rec = testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// unverified account may be registered by someone else, it is not linked
+	unverified := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUnverifiedUser(t, unverified)
+	require.Equal(t, http.StatusOK, rec.Code)
+	authorization, rec = testOIDCAuthorize(t, "mock")
+	require.Equal(t, http.StatusOK, rec.Code)
+	code = testOIDC.Approve(t, authorization, randomString(10), unverified.Email, true)
+	_, rec = testOIDCCallback(t, "mock", OIDCCallbackInput{Code: code, State: authorization.State})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+// testOIDCProvider is a minimal OpenID provider, authorization is approved by the test instead of a login page
+type testOIDCProvider struct {
+	*httptest.Server
+	keys *JWTKeySet
+
+	mu    sync.Mutex
+	codes map[string]testOIDCCode
+}
+
+type testOIDCCode struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newTestOIDCProvider() (*testOIDCProvider, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := NewJWTKeySet("", key)
+	if err != nil {
+		return nil, err
+	}
+	p := testOIDCProvider{keys: keys, codes: map[string]testOIDCCode{}}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                                p.URL,
+			"authorization_endpoint":                p.URL + "/authorize",
+			"token_endpoint":                        p.URL + "/token",
+			"jwks_uri":                              p.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(p.keys.JWKS())
+	})
+	mux.HandleFunc("/token", p.token)
+	p.Server = httptest.NewServer(mux)
+	return &p, nil
+}
+
+// Approve sign in the user at the provider and return the code sent to the redirect url
+func (p *testOIDCProvider) Approve(t *testing.T, authorization *OIDCAuthorization, subject, email string, emailVerified bool) string {
+	u, err := url.Parse(authorization.URL)
+	require.NoError(t, err)
+	query := u.Query()
+	require.Equal(t, p.URL+"/authorize", fmt.Sprintf("%s://%s%s", u.Scheme, u.Host, u.Path))
+	require.Equal(t, testOIDCClientID, query.Get("client_id"))
+	require.Equal(t, "code", query.Get("response_type"))
+	require.Equal(t, authorization.State, query.Get("state"))
+	require.Equal(t, "S256", query.Get("code_challenge_method"))
+	require.NotEmpty(t, query.Get("code_challenge"))
+	require.NotEmpty(t, query.Get("nonce"))
+
+	code := randomString(20)
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.codes[code] = testOIDCCode{
+		challenge: query.Get("code_challenge"),
+		claims: jwt.MapClaims{
+			"iss":            p.URL,
+			"sub":            subject,
+			"aud":            testOIDCClientID,
+			"iat":            time.Now().Unix(),
+			"exp":            time.Now().Add(time.Minute).Unix(),
+			"nonce":          query.Get("nonce"),
+			"email":          email,
+			"email_verified": emailVerified,
+		},
+	}
+	return code
+}
+
+func (p *testOIDCProvider) token(w http.ResponseWriter, r *http.Request) {
+	tokenErr := func(msg string) {
+		w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant", "error_description": msg})
+	}
+	if err := r.ParseForm(); err != nil {
+		tokenErr(err.Error())
+		return
+	}
+
+	p.mu.Lock()
+	code, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+	if !ok {
+		tokenErr("code invalid")
+		return
+	}
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if base64.RawURLEncoding.EncodeToString(sum[:]) != code.challenge {
+		tokenErr("code verifier invalid")
+		return
+	}
+
+	idToken, err := p.keys.Sign(code.claims)
+	if err != nil {
+		tokenErr(err.Error())
+		return
+	}
+	w.Header().Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	json.NewEncoder(w).Encode(map[string]any{
+		"access_token": randomString(20),
+		"token_type":   "Bearer",
+		"expires_in":   60,
+		"id_token":     idToken,
+	})
+}
+
+func testOIDCProviders(t *testing.T) ([]string, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/oidc/providers", nil)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]string]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testOIDCAuthorize(t *testing.T, provider string) (*OIDCAuthorization, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/oidc/%s/authorize", provider), nil)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*OIDCAuthorization]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+// testOIDCCallback call the callback from the browser that started the sign in
+func testOIDCCallback(t *testing.T, provider string, input OIDCCallbackInput) (*AuthToken, *httptest.ResponseRecorder) {
+	return testOIDCCallbackFrom(t, provider, input, &http.Cookie{Name: oidcStateCookie, Value: input.State})
+}
+
+func testOIDCCallbackFrom(t *testing.T, provider string, input OIDCCallbackInput, cookie *http.Cookie) (*AuthToken, *httptest.ResponseRecorder) {
+	query := url.Values{}
+	query.Set("code", input.Code)
+	query.Set("state", input.State)
+	if input.Error != "" {
+		query.Set("error", input.Error)
+	}
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/oidc/%s/callback?%s", provider, query.Encode()), nil)
+	if cookie != nil {
+		req.AddCookie(cookie)
+	}
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*AuthToken]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testOIDCIdentities(t *testing.T, token string) ([]UserIdentity, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/identities", nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]UserIdentity]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewPlannerHandler(c *Config, trxProvider *TransactionProvider) *PlannerHandler {
+	return &PlannerHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type PlannerHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Propose
+//
+//	@Summary		Propose Schedule
+//	@Description	admin generate conflict-free showtime proposal, nothing is saved
+//	@Tags			schedules
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			request			body		PlannerInput	true	"body request"
+//	@Success		200				{object}	Response[PlannerProposal]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/planner/propose [post]
+func (h *PlannerHandler) Propose(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input PlannerInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var proposal *PlannerProposal
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		proposal, err = service.Planner.Propose(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PlannerProposal]{Message: "ok", Data: proposal})
+}
+
+// Commit
+//
+//	@Summary		Commit Schedule
+//	@Description	admin create all reviewed proposal showtimes, nothing is saved when one of them fail
+//	@Tags			schedules
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			request			body		PlannerCommitInput	true	"body request"
+//	@Success		200				{object}	Response[[]Showtime]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/planner/commit [post]
+func (h *PlannerHandler) Commit(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input PlannerCommitInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var showtimes []Showtime
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		showtimes, err = service.Planner.Commit(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]Showtime]{Message: "ok", Data: showtimes})
+}
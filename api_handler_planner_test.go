@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanner(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, token, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	movies := []*Movie{}
+	for _, duration := range []int64{150, 95} {
+		movie, rec := testCreateMovie(t, token, MovieInput{
+			Title:       randomString(5),
+			ReleaseDate: time.Now(),
+			Director:    randomString(5),
+			Duration:    duration,
+			PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+			Description: randomString(5),
+			GenreIDs:    []int64{genre.ID},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		movies = append(movies, movie)
+	}
+
+	room, rec := testCreateRoom(t, token, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startDate := time.Now().AddDate(0, 0, 30)
+	input := PlannerInput{
+		StartDate: startDate,
+		Days:      2,
+		RoomIDs:   []int64{room.ID},
+		Movies: []PlannerMovieInput{
+			{MovieID: movies[0].ID, ShowCount: 2, Price: 50_000, PrimeTime: true},
+			{MovieID: movies[1].ID, ShowCount: 4, Price: 40_000},
+		},
+	}
+
+	t.Run("ProposeOK", func(t *testing.T) {
+		proposal, rec := testProposeSchedule(t, token, input)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, proposal)
+		require.Len(t, proposal.Showtimes, 6)
+		require.Empty(t, proposal.Unscheduled)
+
+		for i, a := range proposal.Showtimes {
+			for j, b := range proposal.Showtimes {
+				if i == j {
+					continue
+				}
+				require.False(t, isTimeOverlapping(a.StartAt, a.EndAt, b.StartAt, b.EndAt))
+			}
+			if a.MovieID == movies[0].ID {
+				require.True(t, a.IsPrimeTime)
+			}
+		}
+
+		// deterministic
+		again, rec := testProposeSchedule(t, token, input)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, proposal, again)
+	})
+
+	t.Run("ProposeReportUnscheduled", func(t *testing.T) {
+		overbooked := input
+		overbooked.Movies = []PlannerMovieInput{{MovieID: movies[0].ID, ShowCount: 100, Price: 50_000}}
+		proposal, rec := testProposeSchedule(t, token, overbooked)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, proposal)
+		require.Len(t, proposal.Unscheduled, 1)
+		require.Equal(t, int64(len(proposal.Showtimes)), proposal.Unscheduled[0].Scheduled)
+	})
+
+	t.Run("ProposeFailMovieNotFound", func(t *testing.T) {
+		invalid := input
+		invalid.Movies = []PlannerMovieInput{{MovieID: 999_999_999, ShowCount: 1, Price: 50_000}}
+		_, rec := testProposeSchedule(t, token, invalid)
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+
+	t.Run("ProposeBuffer", func(t *testing.T) {
+		noBuffer := input
+		noBuffer.BufferMinutes = new(int64)
+		proposal, rec := testProposeSchedule(t, token, noBuffer)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, proposal.Showtimes, 6)
+
+		negative := int64(-1)
+		noBuffer.BufferMinutes = &negative
+		_, rec = testProposeSchedule(t, token, noBuffer)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		// back to back showtime only touch, they are not overlapping
+		at := startDate.Truncate(time.Hour)
+		require.False(t, isTimeOverlapping(at, at.Add(time.Hour), at.Add(time.Hour), at.Add(2*time.Hour)))
+		require.True(t, isTimeOverlapping(at, at.Add(time.Hour), at.Add(time.Hour-time.Minute), at.Add(2*time.Hour)))
+	})
+
+	t.Run("ProposeNotBeforeRelease", func(t *testing.T) {
+		y, m, d := startDate.Date()
+		releaseDate := time.Date(y, m, d+1, 12, 0, 0, 0, startDate.Location())
+		movie, rec := testCreateMovie(t, token, MovieInput{
+			Title:       randomString(5),
+			ReleaseDate: releaseDate,
+			Director:    randomString(5),
+			Duration:    95,
+			PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+			Description: randomString(5),
+			GenreIDs:    []int64{genre.ID},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		// released on the second day, the first day is free but cannot be used
+		released := input
+		released.Movies = []PlannerMovieInput{{MovieID: movie.ID, ShowCount: 3, Price: 40_000}}
+		proposal, rec := testProposeSchedule(t, token, released)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, proposal.Showtimes, 3)
+		for _, showtime := range proposal.Showtimes {
+			require.False(t, showtime.StartAt.Before(releaseDate))
+		}
+	})
+
+	t.Run("CommitOK", func(t *testing.T) {
+		proposal, rec := testProposeSchedule(t, token, input)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		commit := PlannerCommitInput{}
+		for _, item := range proposal.Showtimes {
+			commit.Showtimes = append(commit.Showtimes, item.ShowtimeInput)
+		}
+		showtimes, rec := testCommitSchedule(t, token, commit)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, showtimes, len(commit.Showtimes))
+
+		// the room is now full, commit same proposal again must fail without partial insert
+		_, rec = testCommitSchedule(t, token, commit)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		p, rec := testPaginateShowtime(t, ShowtimeFilter{RoomIDs: []int64{room.ID}}, PaginateInput{1, 50})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, int64(len(commit.Showtimes)), p.TotalItems)
+	})
+}
+
+func testProposeSchedule(t *testing.T, token string, input PlannerInput) (*PlannerProposal, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/planner/propose", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*PlannerProposal]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testCommitSchedule(t *testing.T, token string, input PlannerCommitInput) ([]Showtime, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/planner/commit", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]Showtime]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
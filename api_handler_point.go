@@ -0,0 +1,139 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewPointHandler(c *Config, trxProvider *TransactionProvider) *PointHandler {
+	return &PointHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type PointHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Summary
+//
+//	@Summary		Points Summary
+//	@Description	logged in user points balance and history
+//	@Tags			points
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			page			query		int		false	"pagination page"
+//	@Param			per_page		query		int		false	"pagination page size"
+//	@Success		200				{object}	Response[PointSummary]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/points [get]
+func (h *PointHandler) Summary(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var summary *PointSummary
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		summary, err = service.Point.Summary(ctx, userID, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PointSummary]{Message: "ok", Data: summary})
+}
+
+// Redeem
+//
+//	@Summary		Redeem Points
+//	@Description	user redeem points as discount or free tickets on unpaid reservation
+//	@Tags			points
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			id				path		int					true	"reservation id"
+//	@Param			request			body		RedeemPointInput	true	"body request"
+//	@Success		200				{object}	Response[Reservation]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/points [put]
+func (h *PointHandler) Redeem(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input RedeemPointInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var reservation *Reservation
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		reservation, err = service.Point.Redeem(ctx, userID, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Reservation]{Message: "ok", Data: reservation})
+}
+
+// Unredeem
+//
+//	@Summary		Unredeem Points
+//	@Description	user take back redeemed points from unpaid reservation
+//	@Tags			points
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Success		200				{object}	Response[Reservation]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/points [delete]
+func (h *PointHandler) Unredeem(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var reservation *Reservation
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		reservation, err = service.Point.Unredeem(ctx, userID, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Reservation]{Message: "ok", Data: reservation})
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPoint(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+	config := NewConfig()
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}, {Name: "A2"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   100_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	userInput := UserInput{
+		Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	summary, rec := testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(0), summary.Balance)
+
+	// earn on paid reservation
+	cart1, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation1, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart1.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation1, rec = testPayReservation(t, token, reservation1.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	earned := reservation1.TotalPrice / config.PointEarnUnit
+	require.Greater(t, earned, int64(0))
+	summary, rec = testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, earned, summary.Balance)
+	require.Len(t, summary.Transactions.Items, 1)
+	require.Equal(t, PointEarn, summary.Transactions.Items[0].Kind)
+	require.Equal(t, reservation1.ID, summary.Transactions.Items[0].ReservationID)
+
+	// redeem on unpaid reservation
+	cart2, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[1].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation2, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart2.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	_, rec = testRedeemPoint(t, token, reservation2.ID, RedeemPointInput{Points: earned + 1})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testRedeemPoint(t, token, reservation2.ID, RedeemPointInput{FreeTickets: 2})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	reservation2, rec = testRedeemPoint(t, token, reservation2.ID, RedeemPointInput{Points: earned})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, earned, reservation2.PointsRedeemed)
+	require.Equal(t, earned*config.PointRedeemValue, reservation2.PointsDiscount)
+	require.Equal(t, reservation2.SubtotalPrice-reservation2.PointsDiscount, reservation2.TotalPrice)
+
+	summary, rec = testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(0), summary.Balance)
+
+	// cancel give back redeemed points
+	_, rec = testCancelReservation(t, token, reservation2.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	summary, rec = testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, earned, summary.Balance)
+
+	// cancel take back earned points
+	_, rec = testCancelReservation(t, token, reservation1.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	summary, rec = testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(0), summary.Balance)
+	require.Len(t, summary.Transactions.Items, 4)
+	require.Equal(t, PointReverse, summary.Transactions.Items[0].Kind)
+	require.Equal(t, -earned, summary.Transactions.Items[0].Points)
+}
+
+func TestPointReverseSpent(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+	config := NewConfig()
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}, {Name: "A2"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   100_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	userInput := UserInput{
+		Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// earn
+	cart1, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation1, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart1.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation1, rec = testPayReservation(t, token, reservation1.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	earned1 := reservation1.TotalPrice / config.PointEarnUnit
+
+	// redeem every earned point on a paid reservation
+	cart2, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[1].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation2, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart2.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testRedeemPoint(t, token, reservation2.ID, RedeemPointInput{Points: earned1})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation2, rec = testPayReservation(t, token, reservation2.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	earned2 := reservation2.TotalPrice / config.PointEarnUnit
+	require.Less(t, earned2, earned1)
+
+	summary, rec := testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, earned2, summary.Balance)
+
+	// cancel take back the spent earning, what the other earning cannot cover is owed
+	_, rec = testCancelReservation(t, token, reservation1.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	summary, rec = testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, earned2-earned1, summary.Balance)
+	require.Equal(t, PointReverse, summary.Transactions.Items[0].Kind)
+	require.Equal(t, -earned1, summary.Transactions.Items[0].Points)
+
+	cart3, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation3, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart3.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testRedeemPoint(t, token, reservation3.ID, RedeemPointInput{Points: 1})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// cancel of the reservation paid with the points settle the rest
+	_, rec = testCancelReservation(t, token, reservation2.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	summary, rec = testGetPointSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(0), summary.Balance)
+	require.Len(t, summary.Transactions.Items, 6)
+}
+
+func testGetPointSummary(t *testing.T, token string) (*PointSummary, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/points", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*PointSummary]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testRedeemPoint(t *testing.T, token string, ID int64, input RedeemPointInput) (*Reservation, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	uri := fmt.Sprintf("/api/reservations/%d/points", ID)
+	req := httptest.NewRequest(http.MethodPut, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Reservation]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
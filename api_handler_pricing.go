@@ -0,0 +1,209 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewPricingRuleHandler(c *Config, trxProvider *TransactionProvider) *PricingRuleHandler {
+	return &PricingRuleHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type PricingRuleHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Create
+//
+//	@Summary		Create Pricing Rule
+//	@Description	admin create pricing rule
+//	@Tags			pricing
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			request			body		PricingRuleInput	true	"body request"
+//	@Success		200				{object}	Response[PricingRule]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/pricing-rules [post]
+func (h *PricingRuleHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input PricingRuleInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var rule *PricingRule
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		rule, err = service.PricingRule.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PricingRule]{Message: "ok", Data: rule})
+}
+
+// UpdateByID
+//
+//	@Summary		Update Pricing Rule
+//	@Description	admin update pricing rule by id
+//	@Tags			pricing
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			id				path		int			true	"pricing rule id"
+//	@Param			request			body		PricingRuleInput	true	"body request"
+//	@Success		200				{object}	Response[PricingRule]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/pricing-rules/{id} [put]
+func (h *PricingRuleHandler) UpdateByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input PricingRuleInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var rule *PricingRule
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		rule, err = service.PricingRule.UpdateByID(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PricingRule]{Message: "ok", Data: rule})
+}
+
+// GetByID
+//
+//	@Summary		Get Pricing Rule
+//	@Description	admin get pricing rule by id
+//	@Tags			pricing
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"pricing rule id"
+//	@Success		200				{object}	Response[PricingRule]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/pricing-rules/{id} [get]
+func (h *PricingRuleHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var rule *PricingRule
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		rule, err = service.PricingRule.GetByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PricingRule]{Message: "ok", Data: rule})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete Pricing Rule
+//	@Description	admin delete pricing rule by id
+//	@Tags			pricing
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"pricing rule id"
+//	@Success		200				{object}	Response[any]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/pricing-rules/{id} [delete]
+func (h *PricingRuleHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.PricingRule.DeleteByID(ctx, int64(ID))
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// Pagination
+//
+//	@Summary		Filter Pricing Rule
+//	@Description	admin filter pricing rules
+//	@Tags			pricing
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			page			query		int					false	"pagination page"
+//	@Param			per_page		query		int					false	"pagination page size"
+//	@Param			request			body		PricingRuleFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[PricingRule]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/pricing-rules/filter [post]
+func (h *PricingRuleHandler) Pagination(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter PricingRuleFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[PricingRule]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.PricingRule.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[PricingRule]]{Message: "ok", Data: res})
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricingRule(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	// far in the future so the rules does not affect other tests
+	showStartAt := time.Now().AddDate(0, 0, 400).UTC().Truncate(time.Hour)
+
+	t.Run("CreateFailNoCondition", func(t *testing.T) {
+		_, rec := testCreatePricingRule(t, tokenAdmin, PricingRuleInput{
+			Name:    randomString(8),
+			Kind:    PricingRuleHoliday,
+			Percent: 20,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		_, rec = testCreatePricingRule(t, tokenAdmin, PricingRuleInput{
+			Name:    randomString(8),
+			Kind:    PricingRuleMatinee,
+			Percent: -20,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ApplyOnCartAndReservation", func(t *testing.T) {
+		holiday, rec := testCreatePricingRule(t, tokenAdmin, PricingRuleInput{
+			Name:     randomString(8),
+			Kind:     PricingRuleHoliday,
+			Percent:  20,
+			Priority: 10,
+			Dates:    []string{showStartAt.Format(time.DateOnly)},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, holiday)
+		defer testDeletePricingRule(t, tokenAdmin, holiday.ID)
+
+		earlyBird, rec := testCreatePricingRule(t, tokenAdmin, PricingRuleInput{
+			Name:          randomString(8),
+			Kind:          PricingRuleEarlyBird,
+			Amount:        -5_000,
+			MinDaysBefore: 300,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, earlyBird)
+		defer testDeletePricingRule(t, tokenAdmin, earlyBird.ID)
+
+		genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+			Title:       randomString(5),
+			ReleaseDate: time.Now(),
+			Director:    randomString(5),
+			Duration:    33,
+			PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+			Description: randomString(5),
+			GenreIDs:    []int64{genre.ID},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: randomString(5), AdditionalPrice: 10_000}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		seats, rec := testListRoomSeats(t, room.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+			MovieID: movie.ID,
+			RoomID:  room.ID,
+			StartAt: showStartAt,
+			EndAt:   showStartAt.Add(movie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		userInput := UserInput{
+			Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+			Password: "12345678",
+		}
+		_, rec = testRegisterUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+		token, rec := testLoginUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, int64(60_000), cart.BasePrice)
+		require.Equal(t, int64(60_000+12_000-5_000), cart.Price)
+		require.NotNil(t, cart.Pricing)
+		require.Len(t, cart.Pricing.Rules, 2)
+		require.Equal(t, holiday.ID, cart.Pricing.Rules[0].RuleID)
+		require.Equal(t, earlyBird.ID, cart.Pricing.Rules[1].RuleID)
+
+		reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, cart.Price, reservation.TotalPrice)
+
+		reservation, rec = testGetReservation(t, token, reservation.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, reservation.Items, 1)
+		require.Equal(t, cart.Price, reservation.Items[0].TotalPrice)
+		require.NotNil(t, reservation.Items[0].Pricing)
+		require.Equal(t, cart.BasePrice, reservation.Items[0].Pricing.BasePrice)
+		require.Len(t, reservation.Items[0].Pricing.Rules, 2)
+	})
+}
+
+func testCreatePricingRule(t *testing.T, token string, input PricingRuleInput) (*PricingRule, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/pricing-rules", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*PricingRule]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testDeletePricingRule(t *testing.T, token string, ID int64) *httptest.ResponseRecorder {
+	uri := fmt.Sprintf("/api/admin/pricing-rules/%d", ID)
+	req := httptest.NewRequest(http.MethodDelete, uri, nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	return rec
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewProfileHandler(c *Config, trxProvider *TransactionProvider) *ProfileHandler {
+	return &ProfileHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type ProfileHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Get
+//
+//	@Summary		Get Profile
+//	@Description	get profile of current user, empty when never saved
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	Response[UserProfile]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/profile [get]
+func (h *ProfileHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var profile *UserProfile
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		profile, err = service.Profile.Get(ctx, userID)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*UserProfile]{Message: "ok", Data: profile})
+}
+
+// Update
+//
+//	@Summary		Update Profile
+//	@Description	replace profile of current user, field not sent is cleared and every change is kept in history.
+//	@Description	Birth date is required to reserve age rated movie, preferred cinema is the default of showtime listing
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			request			body		UserProfileInput	true	"req"
+//	@Success		200				{object}	Response[UserProfile]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/profile [put]
+func (h *ProfileHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	var input UserProfileInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var profile *UserProfile
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		profile, err = service.Profile.Update(ctx, userID, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*UserProfile]{Message: "ok", Data: profile})
+}
+
+// History
+//
+//	@Summary		Profile History
+//	@Description	changes of profile of current user, latest first
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			page			query		int		false	"pagination page"
+//	@Param			per_page		query		int		false	"pagination page size"
+//	@Success		200				{object}	Response[Paginate[UserProfileChange]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/profile/history [get]
+func (h *ProfileHandler) History(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+	page := GetPage(c)
+
+	var res *Paginate[UserProfileChange]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Profile.History(ctx, userID, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[UserProfileChange]]{Message: "ok", Data: res})
+}
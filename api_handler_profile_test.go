@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfile(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+	cinema, rec := testCreateCinema(t, tokenAdmin, CinemaInput{Name: randomString(8)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// never saved
+	profile, rec := testGetProfile(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, profile.DisplayName)
+	require.Nil(t, profile.PreferredCinemaID)
+
+	invalids := []UserProfileInput{
+		{Phone: "0812"},
+		{BirthDate: "31-01-2000"},
+		{BirthDate: time.Now().AddDate(0, 0, 1).Format(birthDateLayout)},
+		{Language: "english"},
+		{PreferredCinemaID: 999_999_999},
+	}
+	for _, invalid := range invalids {
+		_, rec = testUpdateProfile(t, token, invalid)
+		require.Equal(t, http.StatusBadRequest, rec.Code, "%+v", invalid)
+	}
+
+	profile, rec = testUpdateProfile(t, token, UserProfileInput{
+		DisplayName:       " Jane ",
+		Phone:             "+62 812-3456-7890",
+		BirthDate:         "2000-01-31",
+		PreferredCinemaID: cinema.ID,
+		Language:          "EN",
+		MarketingConsent:  true,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "Jane", profile.DisplayName)
+	require.Equal(t, "+6281234567890", profile.Phone)
+	require.Equal(t, "2000-01-31", profile.BirthDate)
+	require.Equal(t, cinema.ID, *profile.PreferredCinemaID)
+	require.Equal(t, "en", profile.Language)
+	require.NotNil(t, profile.MarketingConsentAt)
+	consentAt := *profile.MarketingConsentAt
+
+	// consent time is kept while consent is not withdrawn
+	profile, rec = testUpdateProfile(t, token, UserProfileInput{
+		DisplayName:       "Jane Doe",
+		Phone:             "+6281234567890",
+		BirthDate:         "2000-01-31",
+		PreferredCinemaID: cinema.ID,
+		Language:          "en",
+		MarketingConsent:  true,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, consentAt.Equal(*profile.MarketingConsentAt))
+
+	// field not sent is cleared
+	profile, rec = testUpdateProfile(t, token, UserProfileInput{DisplayName: "Jane Doe"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, profile.Phone)
+	require.Nil(t, profile.PreferredCinemaID)
+	require.Nil(t, profile.MarketingConsentAt)
+
+	history, rec := testProfileHistory(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(6+1+5), history.TotalItems)
+	require.Equal(t, "display_name", history.Items[len(history.Items)-1].Field)
+}
+
+func TestProfilePreferredCinema(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var cinemaIDs []int64
+	for range 2 {
+		cinema, rec := testCreateCinema(t, tokenAdmin, CinemaInput{Name: randomString(8)})
+		require.Equal(t, http.StatusOK, rec.Code)
+		room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5), CinemaID: cinema.ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		startAt := time.Now().Add(24 * time.Hour)
+		_, rec = testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+			MovieID: movie.ID,
+			RoomID:  room.ID,
+			StartAt: startAt,
+			EndAt:   startAt.Add(movie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		cinemaIDs = append(cinemaIDs, cinema.ID)
+	}
+
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testUpdateProfile(t, token, UserProfileInput{PreferredCinemaID: cinemaIDs[1]})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	filter := ShowtimeFilter{MovieIDs: []int64{movie.ID}}
+	showtimes, rec := testPaginateShowtimeAs(t, token, filter)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, showtimes.Items, 1)
+
+	showtimes, rec = testPaginateShowtimeAs(t, token, ShowtimeFilter{MovieIDs: []int64{movie.ID}, AllCinemas: true})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, showtimes.Items, 2)
+
+	showtimes, rec = testPaginateShowtimeAs(t, token, ShowtimeFilter{MovieIDs: []int64{movie.ID}, CinemaIDs: []int64{cinemaIDs[0]}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, showtimes.Items, 1)
+
+	// anonymous
+	showtimes, rec = testPaginateShowtimeAs(t, "", filter)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, showtimes.Items, 2)
+}
+
+func TestProfileAgeRestriction(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+		MinAge:      17,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(17), movie.MinAge)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: randomString(5)}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	startAt := time.Now().Add(24 * time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// birth date is required
+	_, rec = testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "birth date")
+
+	_, rec = testUpdateProfile(t, token, UserProfileInput{BirthDate: time.Now().AddDate(-16, 0, 0).Format(birthDateLayout)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testUpdateProfile(t, token, UserProfileInput{BirthDate: time.Now().AddDate(-17, 0, -7).Format(birthDateLayout)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestUserProfileAge(t *testing.T) {
+	profile := UserProfile{}
+	_, ok := profile.Age(time.Now())
+	require.False(t, ok)
+
+	profile.BirthDate = "2008-02-29"
+	cases := []struct {
+		at  string
+		age int64
+	}{
+		{"2025-02-28", 16},
+		{"2025-03-01", 17},
+		{"2026-02-28", 17},
+		{"2028-02-29", 20},
+	}
+	for _, c := range cases {
+		at, err := time.Parse(birthDateLayout, c.at)
+		require.NoError(t, err)
+		age, ok := profile.Age(at)
+		require.True(t, ok)
+		require.Equal(t, c.age, age, c.at)
+	}
+
+	movie := &Movie{Title: "rated", MinAge: 17}
+	at, _ := time.Parse(birthDateLayout, "2025-02-28")
+	require.Error(t, profile.CheckAge(movie, at))
+	at, _ = time.Parse(birthDateLayout, "2025-03-01")
+	require.NoError(t, profile.CheckAge(movie, at))
+	require.NoError(t, (&UserProfile{}).CheckAge(&Movie{}, at))
+}
+
+func testGetProfile(t *testing.T, token string) (*UserProfile, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/profile", nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*UserProfile]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testUpdateProfile(t *testing.T, token string, input UserProfileInput) (*UserProfile, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/user/profile", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*UserProfile]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testProfileHistory(t *testing.T, token string) (*Paginate[UserProfileChange], *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/profile/history?per_page=50", nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Paginate[UserProfileChange]]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testPaginateShowtimeAs(t *testing.T, token string, filter ShowtimeFilter) (*Paginate[Showtime], *httptest.ResponseRecorder) {
+	p, err := json.Marshal(filter)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/showtimes/filter", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	if token != "" {
+		req.Header.Set(echo.HeaderAuthorization, token)
+	}
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Paginate[Showtime]]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
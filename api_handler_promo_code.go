@@ -0,0 +1,209 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewPromoCodeHandler(c *Config, trxProvider *TransactionProvider) *PromoCodeHandler {
+	return &PromoCodeHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type PromoCodeHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Create
+//
+//	@Summary		Create Promo Code
+//	@Description	admin create promo code
+//	@Tags			promo codes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			request			body		PromoCodeInput	true	"body request"
+//	@Success		200				{object}	Response[PromoCode]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/promo-codes [post]
+func (h *PromoCodeHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input PromoCodeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var promoCode *PromoCode
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		promoCode, err = service.PromoCode.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PromoCode]{Message: "ok", Data: promoCode})
+}
+
+// UpdateByID
+//
+//	@Summary		Update Promo Code
+//	@Description	admin update promo code by id
+//	@Tags			promo codes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			id				path		int				true	"promo code id"
+//	@Param			request			body		PromoCodeInput	true	"body request"
+//	@Success		200				{object}	Response[PromoCode]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/promo-codes/{id} [put]
+func (h *PromoCodeHandler) UpdateByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input PromoCodeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var promoCode *PromoCode
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		promoCode, err = service.PromoCode.UpdateByID(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PromoCode]{Message: "ok", Data: promoCode})
+}
+
+// GetByID
+//
+//	@Summary		Get Promo Code
+//	@Description	admin get promo code by id
+//	@Tags			promo codes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"promo code id"
+//	@Success		200				{object}	Response[PromoCode]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/promo-codes/{id} [get]
+func (h *PromoCodeHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var promoCode *PromoCode
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		promoCode, err = service.PromoCode.GetByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*PromoCode]{Message: "ok", Data: promoCode})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete Promo Code
+//	@Description	admin delete promo code by id
+//	@Tags			promo codes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"promo code id"
+//	@Success		200				{object}	Response[any]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/promo-codes/{id} [delete]
+func (h *PromoCodeHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.PromoCode.DeleteByID(ctx, int64(ID))
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// Pagination
+//
+//	@Summary		Filter Promo Code
+//	@Description	admin filter promo codes
+//	@Tags			promo codes
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			page			query		int				false	"pagination page"
+//	@Param			per_page		query		int				false	"pagination page size"
+//	@Param			request			body		PromoCodeFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[PromoCode]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/promo-codes/filter [post]
+func (h *PromoCodeHandler) Pagination(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter PromoCodeFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[PromoCode]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.PromoCode.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[PromoCode]]{Message: "ok", Data: res})
+}
@@ -0,0 +1,301 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPromoCode(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{
+		{Name: "A1", AdditionalPrice: 20_000, Category: "VIP"},
+		{Name: "B1"},
+		{Name: "C1", AdditionalPrice: 20_000, Category: "vip"},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, seats, 3)
+	require.Equal(t, "vip", seats[0].Category)
+	require.Equal(t, SeatCategoryStandard, seats[1].Category)
+
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   40_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	newUser := func() string {
+		userInput := UserInput{
+			Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+			Password: "12345678",
+		}
+		_, rec := testRegisterUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+		token, rec := testLoginUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+		return token
+	}
+
+	t.Run("CreateFailInvalid", func(t *testing.T) {
+		_, rec := testCreatePromoCode(t, tokenAdmin, PromoCodeInput{
+			Code:          randomString(8),
+			DiscountType:  PromoDiscountPercent,
+			DiscountValue: 120,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		startAt := time.Now()
+		endAt := startAt.Add(-time.Hour)
+		_, rec = testCreatePromoCode(t, tokenAdmin, PromoCodeInput{
+			Code:          randomString(8),
+			DiscountType:  PromoDiscountAmount,
+			DiscountValue: 5_000,
+			StartAt:       &startAt,
+			EndAt:         &endAt,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ApplyWithRestrictionAndLimit", func(t *testing.T) {
+		promoCode, rec := testCreatePromoCode(t, tokenAdmin, PromoCodeInput{
+			Code:           randomString(8),
+			DiscountType:   PromoDiscountPercent,
+			DiscountValue:  50,
+			UsageLimit:     1,
+			PerUserLimit:   1,
+			GenreIDs:       []int64{genre.ID},
+			SeatCategories: []string{"vip"},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, promoCode)
+
+		token1 := newUser()
+		vipCart, rec := testCreateCart(t, token1, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		standardCart, rec := testCreateCart(t, token1, CartInput{ShowtimeID: showtime.ID, SeatID: seats[1].ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		reservation1, rec := testCreateReservation(t, token1, ReservationInput{CartIDs: []int64{vipCart.ID, standardCart.ID}})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		// code is case insensitive and only vip seat is discounted
+		reservation1, rec = testApplyPromoCode(t, token1, reservation1.ID, ApplyPromoCodeInput{Code: " " + promoCode.Code + " "})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, promoCode.Code, reservation1.PromoCode)
+		require.Equal(t, vipCart.Price+standardCart.Price, reservation1.SubtotalPrice)
+		require.Equal(t, vipCart.Price*50/100, reservation1.Discount)
+		require.Equal(t, reservation1.SubtotalPrice-reservation1.Discount, reservation1.TotalPrice)
+
+		// applying again replace previous usage, not consume another slot
+		reservation1, rec = testApplyPromoCode(t, token1, reservation1.ID, ApplyPromoCodeInput{Code: promoCode.Code})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, promoCode.ID, reservation1.PromoCodeID)
+
+		token2 := newUser()
+		cart2, rec := testCreateCart(t, token2, CartInput{ShowtimeID: showtime.ID, SeatID: seats[2].ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		reservation2, rec := testCreateReservation(t, token2, ReservationInput{CartIDs: []int64{cart2.ID}})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		_, rec = testApplyPromoCode(t, token2, reservation2.ID, ApplyPromoCodeInput{Code: promoCode.Code})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		// cancel give back the usage slot
+		_, rec = testCancelReservation(t, token1, reservation1.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		reservation2, rec = testApplyPromoCode(t, token2, reservation2.ID, ApplyPromoCodeInput{Code: promoCode.Code})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, cart2.Price*50/100, reservation2.Discount)
+
+		reservation2, rec = testRemovePromoCode(t, token2, reservation2.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Empty(t, reservation2.PromoCode)
+		require.Equal(t, reservation2.SubtotalPrice, reservation2.TotalPrice)
+
+		_, rec = testPayReservation(t, token2, reservation2.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		_, rec = testApplyPromoCode(t, token2, reservation2.ID, ApplyPromoCodeInput{Code: promoCode.Code})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("ApplyPerUserLimitParallel", func(t *testing.T) {
+		room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+		require.Equal(t, http.StatusOK, rec.Code)
+		rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}, {Name: "A2"}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		seats, rec := testListRoomSeats(t, room.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+			MovieID: movie.ID,
+			RoomID:  room.ID,
+			StartAt: startAt,
+			EndAt:   startAt.Add(movie.GetDuration()),
+			Price:   40_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		promoCode, rec := testCreatePromoCode(t, tokenAdmin, PromoCodeInput{
+			Code:          randomString(8),
+			DiscountType:  PromoDiscountAmount,
+			DiscountValue: 5_000,
+			PerUserLimit:  1,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		token := newUser()
+		var reservationIDs []int64
+		for _, seat := range seats {
+			cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seat.ID})
+			require.Equal(t, http.StatusOK, rec.Code)
+			reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+			require.Equal(t, http.StatusOK, rec.Code)
+			reservationIDs = append(reservationIDs, reservation.ID)
+		}
+
+		// both reservation of the user apply the code at once, only one get it
+		p, err := json.Marshal(ApplyPromoCodeInput{Code: promoCode.Code})
+		require.NoError(t, err)
+		codes := make(chan int, len(reservationIDs))
+		var wg sync.WaitGroup
+		for _, ID := range reservationIDs {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/reservations/%d/promo", ID), bytes.NewReader(p))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				req.Header.Set(echo.HeaderAuthorization, token)
+				rec := httptest.NewRecorder()
+				testServer.ServeHTTP(rec, req)
+				codes <- rec.Code
+			}()
+		}
+		wg.Wait()
+		close(codes)
+
+		applied := 0
+		for code := range codes {
+			if code == http.StatusOK {
+				applied++
+				continue
+			}
+			require.Equal(t, http.StatusBadRequest, code)
+		}
+		require.Equal(t, 1, applied)
+	})
+
+	t.Run("ApplyFailNotApplicable", func(t *testing.T) {
+		otherMovie, rec := testCreatePromoCode(t, tokenAdmin, PromoCodeInput{
+			Code:          randomString(8),
+			DiscountType:  PromoDiscountAmount,
+			DiscountValue: 5_000,
+			MovieIDs:      []int64{movie.ID + 1_000_000},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		endAt := time.Now().Add(-time.Hour)
+		expired, rec := testCreatePromoCode(t, tokenAdmin, PromoCodeInput{
+			Code:          randomString(8),
+			DiscountType:  PromoDiscountAmount,
+			DiscountValue: 5_000,
+			EndAt:         &endAt,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		token := newUser()
+		cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[1].ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		_, rec = testApplyPromoCode(t, token, reservation.ID, ApplyPromoCodeInput{Code: otherMovie.Code})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		_, rec = testApplyPromoCode(t, token, reservation.ID, ApplyPromoCodeInput{Code: expired.Code})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		_, rec = testApplyPromoCode(t, token, reservation.ID, ApplyPromoCodeInput{Code: randomString(10)})
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func testCreatePromoCode(t *testing.T, token string, input PromoCodeInput) (*PromoCode, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/promo-codes", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*PromoCode]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testApplyPromoCode(t *testing.T, token string, ID int64, input ApplyPromoCodeInput) (*Reservation, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	uri := fmt.Sprintf("/api/reservations/%d/promo", ID)
+	req := httptest.NewRequest(http.MethodPut, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Reservation]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testRemovePromoCode(t *testing.T, token string, ID int64) (*Reservation, *httptest.ResponseRecorder) {
+	uri := fmt.Sprintf("/api/reservations/%d/promo", ID)
+	req := httptest.NewRequest(http.MethodDelete, uri, nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Reservation]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewReconciliationHandler(c *Config, trxProvider *TransactionProvider) *ReconciliationHandler {
+	return &ReconciliationHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type ReconciliationHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Run
+//
+//	@Summary		Run Reconciliation
+//	@Description	admin reconcile reservations with ledger now, return the issues found
+//	@Tags			reconciliations
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	Response[[]ReconciliationIssue]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/reconciliations [post]
+func (h *ReconciliationHandler) Run(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var issues []ReconciliationIssue
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		issues, err = service.Reconciliation.Run(ctx, time.Now())
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]ReconciliationIssue]{Message: "ok", Data: issues})
+}
+
+// Pagination
+//
+//	@Summary		Filter Reconciliation Issue
+//	@Description	admin filter reconciliation issues
+//	@Tags			reconciliations
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string						true	"bearer token"
+//	@Param			page			query		int							false	"pagination page"
+//	@Param			per_page		query		int							false	"pagination page size"
+//	@Param			request			body		ReconciliationIssueFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[ReconciliationIssue]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/reconciliations/filter [post]
+func (h *ReconciliationHandler) Pagination(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter ReconciliationIssueFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[ReconciliationIssue]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Reconciliation.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[ReconciliationIssue]]{Message: "ok", Data: res})
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconciliation(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}, {Name: "A2"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   100_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	userInput := UserInput{
+		Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	giftCard, rec := testIssueGiftCard(t, tokenAdmin, GiftCardInput{Amount: 30_000})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testTopUpWallet(t, token, WalletTopUpInput{Code: giftCard.Code})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cart1, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	paid, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart1.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	paid, rec = testPayReservationWith(t, token, paid.ID, PayReservationInput{WalletAmount: 20_000})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cart2, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[1].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	cancelled, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart2.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testPayReservationWith(t, token, cancelled.ID, PayReservationInput{WalletAmount: 10_000})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testDeleteReservation(token, cancelled.ID)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testCancelReservation(t, token, cancelled.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testCancelReservation(t, token, cancelled.ID)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// wallet part of cancelled reservation is refunded to wallet
+	summary, rec := testGetWalletSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(10_000), summary.Wallet.Balance)
+	require.Equal(t, JournalRefund, summary.Entries.Items[0].Kind)
+	require.Equal(t, int64(-10_000), summary.Entries.Items[0].Amount)
+
+	// ledger of paid and cancelled reservation agree with their status
+	_, rec = testRunReconciliation(t, tokenAdmin)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	issues, rec := testFilterReconciliationIssue(t, tokenAdmin, ReconciliationIssueFilter{
+		ReservationIDs: []int64{paid.ID, cancelled.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, issues.Items, 0)
+
+	// non admin cannot run reconciliation
+	_, rec = testRunReconciliation(t, token)
+	require.NotEqual(t, http.StatusOK, rec.Code)
+}
+
+func TestReservationLedgerIssues(t *testing.T) {
+	ledger := ReservationLedger{Status: ReservationPaid, TotalPrice: 100}
+	require.Equal(t, []string{"recorded sale differs from total price", "paid reservation has no captured payment"}, ledger.Issues())
+
+	ledger = ReservationLedger{Status: ReservationPaid, TotalPrice: 100, Sold: 100, Collected: 100}
+	require.Empty(t, ledger.Issues())
+
+	ledger = ReservationLedger{Status: ReservationCancelled, TotalPrice: 100, Collected: 100, Refunded: 40}
+	require.Equal(t, []string{"cancelled reservation payment is not fully refunded"}, ledger.Issues())
+
+	ledger = ReservationLedger{Status: ReservationUnpaid, TotalPrice: 100}
+	require.Empty(t, ledger.Issues())
+}
+
+func testRunReconciliation(t *testing.T, token string) ([]ReconciliationIssue, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/reconciliations", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]ReconciliationIssue]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testFilterReconciliationIssue(t *testing.T, token string, filter ReconciliationIssueFilter) (*Paginate[ReconciliationIssue], *httptest.ResponseRecorder) {
+	p, err := json.Marshal(filter)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/reconciliations/filter", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Paginate[ReconciliationIssue]]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -72,8 +72,9 @@ func (h *ReservationHandler) UserCreate(c echo.Context) error {
 //	@Tags			reservations
 //	@Accept			json
 //	@Produce		json
-//	@Param			Authorization	header		string	true	"bearer token"
-//	@Param			id				path		int		true	"reservation id"
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			id				path		int					true	"reservation id"
+//	@Param			request			body		PayReservationInput	false	"body request"
 //	@Success		200				{object}	Response[Reservation]
 //	@Failure		400				{object}	Response[any]
 //	@Failure		500				{object}	Response[any]
@@ -88,9 +89,102 @@ func (h *ReservationHandler) Pay(c echo.Context) error {
 		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
 	}
 
+	var input PayReservationInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var reservation *Reservation
+	err = NewPaymentProcessor(h.config, h.trxProvider).Charge(
+		ctx,
+		func(service *ServiceRegistry) (*Payment, error) {
+			return service.Reservation.StartPay(ctx, userID, int64(ID), input)
+		},
+		func(service *ServiceRegistry, receipt *PaymentReceipt) error {
+			reservation, err = service.Reservation.Pay(ctx, userID, int64(ID), input, receipt)
+			return err
+		},
+	)
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Reservation]{Message: "ok", Data: reservation})
+}
+
+// ApplyPromoCode
+//
+//	@Summary		Apply Promo Code
+//	@Description	user apply promo code to unpaid reservation
+//	@Tags			reservations
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			id				path		int					true	"reservation id"
+//	@Param			request			body		ApplyPromoCodeInput	true	"body request"
+//	@Success		200				{object}	Response[Reservation]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/promo [put]
+func (h *ReservationHandler) ApplyPromoCode(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input ApplyPromoCodeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	// read committed, waiting on the promo code row lock has to see the usage committed meanwhile
+	var reservation *Reservation
+	err = h.trxProvider.TransactReadCommitted(ctx, func(service *ServiceRegistry) error {
+		reservation, err = service.PromoCode.Apply(ctx, userID, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Reservation]{Message: "ok", Data: reservation})
+}
+
+// RemovePromoCode
+//
+//	@Summary		Remove Promo Code
+//	@Description	user remove promo code from unpaid reservation
+//	@Tags			reservations
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Success		200				{object}	Response[Reservation]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/promo [delete]
+func (h *ReservationHandler) RemovePromoCode(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
 	var reservation *Reservation
 	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
-		reservation, err = service.Reservation.Pay(ctx, userID, int64(ID))
+		reservation, err = service.PromoCode.Remove(ctx, userID, int64(ID))
 		if err != nil {
 			return err
 		}
@@ -127,8 +221,9 @@ func (h *ReservationHandler) Cancel(c echo.Context) error {
 	}
 
 	var reservation *Reservation
+	var refund *Payment
 	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
-		reservation, err = service.Reservation.Cancel(ctx, userID, int64(ID))
+		reservation, refund, err = service.Reservation.Cancel(ctx, userID, int64(ID))
 		if err != nil {
 			return err
 		}
@@ -138,6 +233,9 @@ func (h *ReservationHandler) Cancel(c echo.Context) error {
 		return NewAPIErr(c, err)
 	}
 
+	// the cancellation stand even when the gateway fail, the refund stay pending to be retried
+	_ = NewPaymentProcessor(h.config, h.trxProvider).Refund(ctx, refund)
+
 	return c.JSON(http.StatusOK, Response[*Reservation]{Message: "ok", Data: reservation})
 }
 
@@ -179,6 +277,56 @@ func (h *ReservationHandler) UserGetByID(c echo.Context) error {
 	return c.JSON(http.StatusOK, Response[*Reservation]{Message: "ok", Data: reservation})
 }
 
+// Receipt
+//
+//	@Summary		Reservation Receipt
+//	@Description	user get receipt of paid reservation as json, text or html
+//	@Tags			reservations
+//	@Accept			json
+//	@Produce		json,plain,html
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Param			format			query		string	false	"json (default), text or html"
+//	@Success		200				{object}	Response[Receipt]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/receipt [get]
+func (h *ReservationHandler) Receipt(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var receipt *Receipt
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		receipt, err = service.Reservation.Receipt(ctx, userID, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	format := ReceiptFormat(c.QueryParam("format"))
+	if format == "" || format == ReceiptJSON {
+		return c.JSON(http.StatusOK, Response[*Receipt]{Message: "ok", Data: receipt})
+	}
+	content, err := RenderReceipt(receipt, format)
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+	if format == ReceiptHTML {
+		return c.HTML(http.StatusOK, content)
+	}
+	return c.String(http.StatusOK, content)
+}
+
 // UserDeleteByID
 //
 //	@Summary		Delete Reservation
@@ -254,3 +402,51 @@ func (h *ReservationHandler) UserGetPagination(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, Response[*Paginate[Reservation]]{Message: "ok", Data: res})
 }
+
+// AdminGetPagination
+//
+//	@Summary		Filter User Reservation
+//	@Description	admin filter reservations of the user
+//	@Tags			reservations
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			id				path		int					true	"user id"
+//	@Param			page			query		int					false	"pagination page"
+//	@Param			per_page		query		int					false	"pagination page size"
+//	@Param			request			body		ReservationFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[Reservation]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id}/reservations/filter [post]
+func (h *ReservationHandler) AdminGetPagination(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	page := GetPage(c)
+
+	var filter ReservationFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	filter.UserIDs = []int64{int64(userID)}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[Reservation]
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Reservation.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[Reservation]]{Message: "ok", Data: res})
+}
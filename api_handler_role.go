@@ -0,0 +1,201 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewRoleHandler(c *Config, trxProvider *TransactionProvider) *RoleHandler {
+	return &RoleHandler{
+		config:      c,
+		trxProvider: trxProvider,
+		cache:       NewPermissionCache(time.Duration(c.PermissionCacheSeconds) * time.Second),
+	}
+}
+
+type RoleHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+	cache       *PermissionCache
+}
+
+// Authorize check the role of the token has the permission, the permissions of the role are cached
+func (h *RoleHandler) Authorize(c echo.Context, permission Permission) error {
+	ctx := c.Request().Context()
+	_, _, role := GetTokenInfo(c)
+
+	permissions, ok := h.cache.Get(role)
+	if !ok {
+		var err error
+		err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+			permissions, err = service.Role.PermissionsOfRole(ctx, role)
+			if err != nil {
+				return err
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		h.cache.Set(role, permissions)
+	}
+
+	if !slices.Contains(permissions, string(permission)) {
+		return NewErr(ErrInput, nil, "unauthorized")
+	}
+	return nil
+}
+
+// Permissions
+//
+//	@Summary		List Permission
+//	@Description	admin list every permission that can be granted to role
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Success		200				{object}	Response[[]PermissionInfo]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/permissions [get]
+func (h *RoleHandler) Permissions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var permissions []PermissionInfo
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		permissions, err = service.Role.Permissions(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]PermissionInfo]{Message: "ok", Data: permissions})
+}
+
+// Create
+//
+//	@Summary		Create Role
+//	@Description	admin create role with permissions
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			request			body		RoleInput	true	"request body"
+//	@Success		200				{object}	Response[Role]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/roles [post]
+func (h *RoleHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input RoleInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var role *Role
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		role, err = service.Role.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+	h.cache.Invalidate()
+
+	return c.JSON(http.StatusOK, Response[*Role]{Message: "ok", Data: role})
+}
+
+// SetPermissions
+//
+//	@Summary		Set Role Permission
+//	@Description	admin replace the permissions of the role, permissions of admin cannot be changed
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			id				path		int					true	"role id"
+//	@Param			request			body		RolePermissionInput	true	"request body"
+//	@Success		200				{object}	Response[Role]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/roles/{id}/permissions [put]
+func (h *RoleHandler) SetPermissions(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input RolePermissionInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var role *Role
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		role, err = service.Role.SetPermissions(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+	h.cache.Invalidate()
+
+	return c.JSON(http.StatusOK, Response[*Role]{Message: "ok", Data: role})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete Role
+//	@Description	admin delete role that is not assigned to any user, user and admin role cannot be deleted
+//	@Tags			accounts
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"role id"
+//	@Success		200				{object}	Response[Role]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/roles/{id} [delete]
+func (h *RoleHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var role *Role
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		role, err = service.Role.DeleteByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+	h.cache.Invalidate()
+
+	return c.JSON(http.StatusOK, Response[*Role]{Message: "ok", Data: role})
+}
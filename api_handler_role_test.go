@@ -0,0 +1,166 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRolePermission(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	permissions, rec := testListPermission(t, tokenAdmin)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, permissions, 10)
+
+	pRoles, rec := testPaginationRole(t, tokenAdmin, RoleFilter{Names: []string{"content_manager"}}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, pRoles.Items, 1)
+	require.Contains(t, pRoles.Items[0].Permissions, string(PermissionMovieManage))
+
+	_, rec = testCreateRole(t, tokenAdmin, RoleInput{Name: "Bad Name!"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testCreateRole(t, tokenAdmin, RoleInput{Name: "scheduler_" + randomString(5), Permissions: []string{"unknown.manage"}})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	role, rec := testCreateRole(t, tokenAdmin, RoleInput{Name: "scheduler_" + randomString(5), Permissions: []string{string(PermissionMovieManage)}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{string(PermissionMovieManage)}, role.Permissions)
+
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	user, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testChangeUserRole(t, tokenAdmin, user.ID, role.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// granted only movie.manage
+	_, rec = testCreateGenre(t, token, GenreInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testPaginationRole(t, token, RoleFilter{}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// change apply without login again
+	role, rec = testSetRolePermission(t, tokenAdmin, role.ID, RolePermissionInput{Permissions: []string{string(PermissionRoleManage)}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, []string{string(PermissionRoleManage)}, role.Permissions)
+	_, rec = testCreateGenre(t, token, GenreInput{Name: randomString(5)})
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	_, rec = testPaginationRole(t, token, RoleFilter{}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	pRoles, rec = testPaginationRole(t, tokenAdmin, RoleFilter{Names: []string{UserAdmin, UserRegular}}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, pRoles.Items, 2)
+	for _, builtin := range pRoles.Items {
+		_, rec = testDeleteRole(t, tokenAdmin, builtin.ID)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+		if builtin.Name == UserAdmin {
+			require.Len(t, builtin.Permissions, len(permissions))
+			_, rec = testSetRolePermission(t, tokenAdmin, builtin.ID, RolePermissionInput{})
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+		} else {
+			// role assigned to user cannot be deleted
+			_, rec = testDeleteRole(t, tokenAdmin, role.ID)
+			require.Equal(t, http.StatusBadRequest, rec.Code)
+			_, rec = testChangeUserRole(t, tokenAdmin, user.ID, builtin.ID)
+			require.Equal(t, http.StatusOK, rec.Code)
+		}
+	}
+
+	_, rec = testDeleteRole(t, tokenAdmin, role.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testGetRole(t, tokenAdmin, role.ID)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPermissionCache(t *testing.T) {
+	cache := NewPermissionCache(time.Minute)
+	_, ok := cache.Get(UserStaff)
+	require.False(t, ok)
+
+	cache.Set(UserStaff, []string{string(PermissionTicketCheckIn)})
+	permissions, ok := cache.Get(UserStaff)
+	require.True(t, ok)
+	require.Equal(t, []string{string(PermissionTicketCheckIn)}, permissions)
+
+	cache.Invalidate()
+	_, ok = cache.Get(UserStaff)
+	require.False(t, ok)
+
+	expired := NewPermissionCache(-time.Second)
+	expired.Set(UserStaff, []string{string(PermissionTicketCheckIn)})
+	_, ok = expired.Get(UserStaff)
+	require.False(t, ok)
+}
+
+func testListPermission(t *testing.T, token string) ([]PermissionInfo, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/admin/permissions", nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]PermissionInfo]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testCreateRole(t *testing.T, token string, input RoleInput) (*Role, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/roles", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Role]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testSetRolePermission(t *testing.T, token string, ID int64, input RolePermissionInput) (*Role, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, fmt.Sprintf("/api/admin/roles/%d/permissions", ID), bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Role]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testDeleteRole(t *testing.T, token string, ID int64) (*Role, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodDelete, fmt.Sprintf("/api/admin/roles/%d", ID), nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Role]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -284,3 +284,46 @@ func (h *RoomHandler) ListSeats(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, Response[[]Seat]{Message: "ok", Data: seats})
 }
+
+// Schedule
+//
+//	@Summary		Room Schedule
+//	@Description	list showtimes and blackout periods of the room
+//	@Tags			rooms
+//	@Accept			json
+//	@Produce		json
+//	@Param			id		path		int		true	"room id"
+//	@Param			from	query		string	false	"start of range (RFC3339), default now"
+//	@Param			to		query		string	false	"end of range (RFC3339), default 7 days after from"
+//	@Success		200		{object}	Response[RoomSchedule]
+//	@Failure		400		{object}	Response[any]
+//	@Failure		500		{object}	Response[any]
+//	@Router			/api/rooms/{id}/schedule [get]
+func (h *RoomHandler) Schedule(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input RoomScheduleInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "time invalid"))
+	}
+	c.Set(KeyInput, input)
+
+	var schedule *RoomSchedule
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		schedule, err = service.Room.GetSchedule(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*RoomSchedule]{Message: "ok", Data: schedule})
+}
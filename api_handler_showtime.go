@@ -3,6 +3,7 @@ package main
 import (
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/labstack/echo/v4"
 )
@@ -22,7 +23,7 @@ type ShowtimeHandler struct {
 // Create
 //
 //	@Summary		Create Showtime
-//	@Description	admin create showtime
+//	@Description	admin create showtime, showtime in the same room cannot overlap but can start when the previous one end
 //	@Tags			schedules
 //	@Accept			json
 //	@Produce		json
@@ -60,7 +61,7 @@ func (h *ShowtimeHandler) Create(c echo.Context) error {
 // UpdateByID
 //
 //	@Summary		Update Showtime
-//	@Description	admin update showtime by id
+//	@Description	admin update showtime by id, showtime in the same room cannot overlap but can start when the previous one end
 //	@Tags			schedules
 //	@Accept			json
 //	@Produce		json
@@ -169,19 +170,21 @@ func (h *ShowtimeHandler) DeleteByID(c echo.Context) error {
 // Pagination
 //
 //	@Summary		Filter Showtime
-//	@Description	filter showtimes
+//	@Description	filter showtimes, when bearer token is sent the preferred cinema of the user is the default
 //	@Tags			schedules
 //	@Accept			json
 //	@Produce		json
-//	@Param			page		query		int				false	"pagination page"
-//	@Param			per_page	query		int				false	"pagination page size"
-//	@Param			request		body		ShowtimeFilter	false	"filter"
-//	@Success		200			{object}	Response[Paginate[Showtime]]
-//	@Failure		400			{object}	Response[any]
-//	@Failure		500			{object}	Response[any]
+//	@Param			Authorization	header		string			false	"bearer token"
+//	@Param			page			query		int				false	"pagination page"
+//	@Param			per_page		query		int				false	"pagination page size"
+//	@Param			request			body		ShowtimeFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[Showtime]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
 //	@Router			/api/showtimes/filter [post]
 func (h *ShowtimeHandler) Pagination(c echo.Context) error {
 	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
 	page := GetPage(c)
 
 	var filter ShowtimeFilter
@@ -193,6 +196,10 @@ func (h *ShowtimeHandler) Pagination(c echo.Context) error {
 	var res *Paginate[Showtime]
 	var err error
 	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		err = service.Profile.DefaultShowtimeFilter(ctx, userID, &filter)
+		if err != nil {
+			return err
+		}
 		res, err = service.Showtime.Pagination(ctx, filter, page)
 		if err != nil {
 			return err
@@ -240,3 +247,74 @@ func (h *ShowtimeHandler) GetShowtimeSeatByID(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, Response[[]Seat]{Message: "ok", Data: seats})
 }
+
+// ListFormatSurcharges
+//
+//	@Summary		List Format Surcharges
+//	@Description	list price surcharge of each showtime format
+//	@Tags			schedules
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	Response[[]FormatSurcharge]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/showtimes/formats [get]
+func (h *ShowtimeHandler) ListFormatSurcharges(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var surcharges []FormatSurcharge
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		surcharges, err = service.Showtime.ListFormatSurcharges(ctx)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]FormatSurcharge]{Message: "ok", Data: surcharges})
+}
+
+// SetFormatSurcharge
+//
+//	@Summary		Set Format Surcharge
+//	@Description	admin set price surcharge of a showtime format
+//	@Tags			schedules
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string					true	"bearer token"
+//	@Param			format			path		string					true	"showtime format"	Enums(2D, 3D, IMAX, 4DX)
+//	@Param			request			body		FormatSurchargeInput	true	"body request"
+//	@Success		200				{object}	Response[[]FormatSurcharge]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/showtimes/formats/{format} [put]
+func (h *ShowtimeHandler) SetFormatSurcharge(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	format := ShowtimeFormat(strings.ToUpper(c.Param("format")))
+
+	var input FormatSurchargeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var surcharges []FormatSurcharge
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		surcharges, err = service.Showtime.SetFormatSurcharge(ctx, format, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]FormatSurcharge]{Message: "ok", Data: surcharges})
+}
@@ -133,6 +133,68 @@ func TestCreateShowtimeFailOverlappingSameRoom(t *testing.T) {
 	require.Equal(t, http.StatusBadRequest, rec.Code)
 }
 
+func TestCreateShowtimeBackToBackSameRoom(t *testing.T) {
+	token := testLoginAdmin(t)
+	newGenre, rec := testCreateGenre(t, token, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	newMovie, rec := testCreateMovie(t, token, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{newGenre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	newRoom, rec := testCreateRoom(t, token, RoomInput{
+		Name: randomString(5),
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startAt := time.Now().Add(24 * time.Hour).Truncate(time.Minute)
+	first, rec := testCreateShowtime(t, token, ShowtimeInput{
+		MovieID: newMovie.ID,
+		RoomID:  newRoom.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(newMovie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// a minute before the first one end is overlapping
+	_, rec = testCreateShowtime(t, token, ShowtimeInput{
+		MovieID: newMovie.ID,
+		RoomID:  newRoom.ID,
+		StartAt: first.EndAt.Add(-time.Minute),
+		EndAt:   first.EndAt.Add(-time.Minute).Add(newMovie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// starting right when the first one end is allowed
+	second, rec := testCreateShowtime(t, token, ShowtimeInput{
+		MovieID: newMovie.ID,
+		RoomID:  newRoom.ID,
+		StartAt: first.EndAt,
+		EndAt:   first.EndAt.Add(newMovie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// moving the first one right after the second one touch from the other side, also allowed
+	_, rec = testUpdateShowtime(t, token, first.ID, ShowtimeInput{
+		MovieID: newMovie.ID,
+		RoomID:  newRoom.ID,
+		StartAt: second.EndAt,
+		EndAt:   second.EndAt.Add(newMovie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
 func TestCreateShowtimeFailNoRequiredData(t *testing.T) {
 	token := testLoginAdmin(t)
 
@@ -433,6 +495,158 @@ func TestGetShowtimeSeatOK(t *testing.T) {
 	require.Equal(t, len(inputSeats), len(seats))
 }
 
+func TestFilterShowtimeAttributeOK(t *testing.T) {
+	token := testLoginAdmin(t)
+	newGenre, rec := testCreateGenre(t, token, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newGenre)
+
+	newMovie, rec := testCreateMovie(t, token, MovieInput{
+		Title:            randomString(5),
+		ReleaseDate:      time.Now(),
+		Director:         randomString(5),
+		Duration:         33,
+		PosterURL:        fmt.Sprintf("http://%s.com", randomString(5)),
+		Description:      randomString(5),
+		GenreIDs:         []int64{newGenre.ID},
+		OriginalLanguage: "EN",
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, newMovie)
+	require.Equal(t, "en", newMovie.OriginalLanguage)
+
+	inputs := []ShowtimeInput{
+		{Format: ShowtimeFormat3D, SubtitleLanguage: "id"},
+		{Format: ShowtimeFormat3D, AudioLanguage: "id"},
+		{Format: ShowtimeFormat2D, SubtitleLanguage: "id", OpenCaptions: true},
+		{Format: ShowtimeFormatIMAX, AudioDescription: true},
+	}
+	showtimeIDs := []int64{}
+	for _, input := range inputs {
+		newRoom, rec := testCreateRoom(t, token, RoomInput{Name: randomString(5)})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, newRoom)
+
+		input.MovieID = newMovie.ID
+		input.RoomID = newRoom.ID
+		input.StartAt = time.Now()
+		input.EndAt = time.Now().Add(newMovie.GetDuration())
+		input.Price = 50_000
+
+		newShowtime, rec := testCreateShowtime(t, token, input)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, newShowtime)
+		require.Equal(t, input.Format, newShowtime.Format)
+		showtimeIDs = append(showtimeIDs, newShowtime.ID)
+	}
+
+	yes := true
+
+	// 3D, original language, subtitled
+	p, rec := testPaginateShowtime(t, ShowtimeFilter{
+		IDs:                showtimeIDs,
+		Formats:            []ShowtimeFormat{"3d"},
+		IsOriginalLanguage: &yes,
+		HasSubtitle:        &yes,
+	}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, p)
+	require.Len(t, p.Items, 1)
+	require.Equal(t, showtimeIDs[0], p.Items[0].ID)
+	require.Equal(t, "en", p.Items[0].AudioLanguage)
+
+	p, rec = testPaginateShowtime(t, ShowtimeFilter{
+		IDs:            showtimeIDs,
+		AudioLanguages: []string{"id"},
+	}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, p.Items, 1)
+	require.Equal(t, showtimeIDs[1], p.Items[0].ID)
+
+	p, rec = testPaginateShowtime(t, ShowtimeFilter{
+		IDs:          showtimeIDs,
+		OpenCaptions: &yes,
+	}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, p.Items, 1)
+	require.Equal(t, showtimeIDs[2], p.Items[0].ID)
+
+	p, rec = testPaginateShowtime(t, ShowtimeFilter{
+		IDs:              showtimeIDs,
+		AudioDescription: &yes,
+	}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, p.Items, 1)
+	require.Equal(t, showtimeIDs[3], p.Items[0].ID)
+
+	_, rec = testPaginateShowtime(t, ShowtimeFilter{Formats: []ShowtimeFormat{"5D"}}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestShowtimeFormatSurchargeOK(t *testing.T) {
+	token := testLoginAdmin(t)
+
+	surcharges, rec := testSetFormatSurcharge(t, token, ShowtimeFormat4DX, FormatSurchargeInput{Amount: 25_000})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, surcharges, 4)
+
+	_, rec = testSetFormatSurcharge(t, token, ShowtimeFormat4DX, FormatSurchargeInput{Amount: -1})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testSetFormatSurcharge(t, token, "5D", FormatSurchargeInput{Amount: 1})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	newGenre, rec := testCreateGenre(t, token, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	newMovie, rec := testCreateMovie(t, token, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{newGenre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	newRoom, rec := testCreateRoom(t, token, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testSetRoomSeats(t, token, newRoom.ID, []SeatInput{{Name: randomString(5), AdditionalPrice: 1000}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, newRoom.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, seats, 1)
+
+	newShowtime, rec := testCreateShowtime(t, token, ShowtimeInput{
+		MovieID: newMovie.ID,
+		RoomID:  newRoom.ID,
+		StartAt: time.Now(),
+		EndAt:   time.Now().Add(newMovie.GetDuration()),
+		Price:   50_000,
+		Format:  ShowtimeFormat4DX,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(25_000), newShowtime.Surcharge)
+
+	userInput := UserInput{
+		Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+	userToken, rec := testLoginUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cart, rec := testCreateCart(t, userToken, CartInput{ShowtimeID: newShowtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(50_000+1000+25_000), cart.Price)
+
+	_, rec = testSetFormatSurcharge(t, token, ShowtimeFormat4DX, FormatSurchargeInput{Amount: 0})
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
 func testCreateShowtime(t *testing.T, token string, input ShowtimeInput) (*Showtime, *httptest.ResponseRecorder) {
 	p, err := json.Marshal(input)
 	require.NoError(t, err)
@@ -533,3 +747,21 @@ func testGetShowtimeSeat(t *testing.T, ID int64) ([]Seat, *httptest.ResponseReco
 
 	return res.Data, rec
 }
+
+func testSetFormatSurcharge(t *testing.T, token string, format ShowtimeFormat, input FormatSurchargeInput) ([]FormatSurcharge, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	uri := fmt.Sprintf("/api/admin/showtimes/formats/%s", format)
+	req := httptest.NewRequest(http.MethodPut, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]FormatSurcharge]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -2,7 +2,11 @@ package main
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
 	"log"
+	"os"
 	"testing"
 	"time"
 
@@ -18,6 +22,27 @@ func TestMain(m *testing.M) {
 
 	ctx := context.Background()
 
+	// mail is written to files so test can read the sent token
+	mailDir, err := os.MkdirTemp("", "mail")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(mailDir)
+	os.Setenv("MAILER", "file")
+	os.Setenv("MAIL_DIR", mailDir)
+
+	// sign in with identity provider against a local mock provider
+	testOIDC, err = newTestOIDCProvider()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer testOIDC.Close()
+	os.Setenv("OIDC_PROVIDERS", "mock")
+	os.Setenv("OIDC_MOCK_ISSUER", testOIDC.URL)
+	os.Setenv("OIDC_MOCK_CLIENT_ID", testOIDCClientID)
+	os.Setenv("OIDC_MOCK_CLIENT_SECRET", "mock-secret")
+	os.Setenv("OIDC_MOCK_REDIRECT_URL", "http://localhost:3000/oidc/callback")
+
 	config := NewConfig()
 
 	container, err := postgres.Run(
@@ -53,6 +78,20 @@ func TestMain(m *testing.M) {
 		log.Fatal(err)
 	}
 
+	// sign with Ed25519, the RSA key stand for the previous key still being rotated out
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		log.Fatal(err)
+	}
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		log.Fatal(err)
+	}
+	config.JWTKeys, err = NewJWTKeySet(config.JWTSecret, edKey, rsaKey)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	trxProvider := NewTransactionProvider(config, pool)
 	handler := NewHandler(config, trxProvider)
 	testServer = setupServer(config, handler)
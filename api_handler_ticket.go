@@ -0,0 +1,299 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewTicketHandler(c *Config, trxProvider *TransactionProvider) *TicketHandler {
+	return &TicketHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type TicketHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// List
+//
+//	@Summary		List Ticket
+//	@Description	user get e-tickets of paid reservation, one per seat
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Success		200				{object}	Response[[]Ticket]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/tickets [get]
+func (h *TicketHandler) List(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var tickets []Ticket
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		tickets, err = service.Ticket.List(ctx, userID, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]Ticket]{Message: "ok", Data: tickets})
+}
+
+// QRCode
+//
+//	@Summary		Ticket QR Code
+//	@Description	user get qr code image of a ticket
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		png,svg
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Param			item_id			path		int		true	"reservation item id"
+//	@Param			format			query		string	false	"png (default) or svg"
+//	@Success		200				{file}		binary
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/tickets/{item_id}/qr [get]
+func (h *TicketHandler) QRCode(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+	itemID, err := strconv.Atoi(c.Param("item_id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "item id invalid"))
+	}
+	format := TicketQRFormat(c.QueryParam("format"))
+
+	var image []byte
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		image, err = service.Ticket.QRCode(ctx, userID, int64(ID), int64(itemID), format)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	if format == TicketQRSVG {
+		return c.Blob(http.StatusOK, "image/svg+xml", image)
+	}
+	return c.Blob(http.StatusOK, "image/png", image)
+}
+
+// CheckIn
+//
+//	@Summary		Check In
+//	@Description	staff admit the holder of scanned ticket at the door of the showtime
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			request			body		CheckInInput	true	"body request"
+//	@Success		200				{object}	Response[Admission]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/staff/check-in [post]
+func (h *TicketHandler) CheckIn(c echo.Context) error {
+	staffID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	var input CheckInInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var admission *Admission
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		admission, err = service.Ticket.CheckIn(ctx, staffID, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Admission]{Message: "ok", Data: admission})
+}
+
+// SyncCheckIn
+//
+//	@Summary		Sync Check In
+//	@Description	staff scanner upload scans collected while offline, each scan get its own result
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			request			body		CheckInSyncInput	true	"body request"
+//	@Success		200				{object}	Response[[]CheckInResult]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/staff/check-in/sync [post]
+func (h *TicketHandler) SyncCheckIn(c echo.Context) error {
+	staffID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	var input CheckInSyncInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var results []CheckInResult
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		results, err = service.Ticket.SyncCheckIn(ctx, staffID, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]CheckInResult]{Message: "ok", Data: results})
+}
+
+// Attendance
+//
+//	@Summary		Showtime Attendance
+//	@Description	staff get admitted and no-show count of a showtime
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"showtime id"
+//	@Success		200				{object}	Response[Attendance]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/staff/showtimes/{id}/attendance [get]
+func (h *TicketHandler) Attendance(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var attendance *Attendance
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		attendance, err = service.Ticket.Attendance(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Attendance]{Message: "ok", Data: attendance})
+}
+
+// PDF
+//
+//	@Summary		Ticket PDF
+//	@Description	user get printable tickets of paid reservation with booking confirmation
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		application/pdf
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Success		200				{file}		binary
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/reservations/{id}/tickets.pdf [get]
+func (h *TicketHandler) PDF(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var pdf []byte
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		pdf, err = service.Ticket.UserPDF(ctx, userID, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="tickets-%d.pdf"`, ID))
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
+
+// AdminPDF
+//
+//	@Summary		Reprint Ticket PDF
+//	@Description	admin reprint tickets of any paid reservation, marked as reprint
+//	@Tags			tickets
+//	@Accept			json
+//	@Produce		application/pdf
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"reservation id"
+//	@Success		200				{file}		binary
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/reservations/{id}/tickets.pdf [get]
+func (h *TicketHandler) AdminPDF(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var pdf []byte
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		pdf, err = service.Ticket.AdminPDF(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf(`inline; filename="tickets-%d-reprint.pdf"`, ID))
+	return c.Blob(http.StatusOK, "application/pdf", pdf)
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicket(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}, {Name: "A2"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	userInput := UserInput{
+		Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cartIDs := []int64{}
+	for _, seat := range seats {
+		cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seat.ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		cartIDs = append(cartIDs, cart.ID)
+	}
+	reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: cartIDs})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// unpaid reservation has no ticket
+	_, rec = testListTicket(t, token, reservation.ID)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testPayReservation(t, token, reservation.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	tickets, rec := testListTicket(t, token, reservation.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, tickets, 2)
+	for _, ticket := range tickets {
+		require.Equal(t, showtime.ID, ticket.ShowtimeID)
+		require.True(t, strings.HasPrefix(ticket.QRCode, "data:image/png;base64,"))
+
+		claims, err := ParseTicketToken(NewConfig().TicketSecret, ticket.Token)
+		require.NoError(t, err)
+		require.Equal(t, reservation.ID, claims.ReservationID)
+		require.Equal(t, ticket.ReservationItemID, claims.ReservationItemID)
+		require.Equal(t, ticket.SeatID, claims.SeatID)
+	}
+
+	rec = testGetTicketQRCode(token, reservation.ID, tickets[0].ReservationItemID, TicketQRPNG)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "image/png", rec.Header().Get(echo.HeaderContentType))
+	require.True(t, bytes.HasPrefix(rec.Body.Bytes(), []byte("\x89PNG")))
+
+	rec = testGetTicketQRCode(token, reservation.ID, tickets[0].ReservationItemID, TicketQRSVG)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "image/svg+xml", rec.Header().Get(echo.HeaderContentType))
+	require.Contains(t, rec.Body.String(), "<svg")
+
+	rec = testGetTicketQRCode(token, reservation.ID, -1, TicketQRPNG)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// check in at the door
+	_, rec = testCheckIn(t, token, CheckInInput{Token: tickets[0].Token, ShowtimeID: showtime.ID})
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	_, rec = testCheckIn(t, tokenAdmin, CheckInInput{Token: tickets[0].Token, ShowtimeID: showtime.ID + 1})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testCheckIn(t, tokenAdmin, CheckInInput{Token: tickets[0].Token, ShowtimeID: showtime.ID, RoomID: room.ID + 1})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	admission, rec := testCheckIn(t, tokenAdmin, CheckInInput{Token: tickets[0].Token, ShowtimeID: showtime.ID, RoomID: room.ID, DeviceID: "door-1"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, tickets[0].ReservationItemID, admission.ReservationItemID)
+	require.Equal(t, "door-1", admission.DeviceID)
+
+	_, rec = testCheckIn(t, tokenAdmin, CheckInInput{Token: tickets[0].Token, ShowtimeID: showtime.ID})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "already admitted at")
+
+	attendance, rec := testGetAttendance(t, tokenAdmin, showtime.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(2), attendance.Sold)
+	require.Equal(t, int64(1), attendance.Admitted)
+	require.Equal(t, int64(1), attendance.NotYet)
+	require.Equal(t, int64(0), attendance.NoShow)
+
+	// scanner sync the scans made while offline, the same ticket scanned twice
+	scannedAt := time.Now().Add(-5 * time.Minute).UTC().Truncate(time.Second)
+	results, rec := testSyncCheckIn(t, tokenAdmin, CheckInSyncInput{Scans: []CheckInInput{
+		{Token: tickets[1].Token, ShowtimeID: showtime.ID, ScannedAt: scannedAt.Add(time.Minute)},
+		{Token: tickets[1].Token, ShowtimeID: showtime.ID, ScannedAt: scannedAt},
+		{Token: tickets[0].Token, ShowtimeID: showtime.ID, ScannedAt: scannedAt},
+		{Token: "T1.forged.token", ShowtimeID: showtime.ID, ScannedAt: scannedAt},
+	}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, results, 4)
+	statuses := map[CheckInStatus]int{}
+	for _, result := range results {
+		statuses[result.Status]++
+		if result.Status == CheckInAdmitted {
+			require.True(t, scannedAt.Equal(result.Admission.ScannedAt))
+		}
+	}
+	require.Equal(t, map[CheckInStatus]int{CheckInAdmitted: 1, CheckInDuplicate: 2, CheckInRejected: 1}, statuses)
+
+	attendance, rec = testGetAttendance(t, tokenAdmin, showtime.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(2), attendance.Admitted)
+	require.Equal(t, int64(0), attendance.NotYet)
+
+	// printable tickets, admin reprint any reservation
+	rec = testGetTicketPDF(token, fmt.Sprintf("/api/reservations/%d/tickets.pdf", reservation.ID))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, "application/pdf", rec.Header().Get(echo.HeaderContentType))
+	require.True(t, bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")))
+
+	rec = testGetTicketPDF(tokenAdmin, fmt.Sprintf("/api/reservations/%d/tickets.pdf", reservation.ID))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = testGetTicketPDF(tokenAdmin, fmt.Sprintf("/api/admin/reservations/%d/tickets.pdf", reservation.ID))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.True(t, bytes.HasPrefix(rec.Body.Bytes(), []byte("%PDF-")))
+
+	rec = testGetTicketPDF(token, fmt.Sprintf("/api/admin/reservations/%d/tickets.pdf", reservation.ID))
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// tickets are void once cancelled
+	_, rec = testCancelReservation(t, token, reservation.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testListTicket(t, token, reservation.ID)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testCheckIn(t, tokenAdmin, CheckInInput{Token: tickets[0].Token, ShowtimeID: showtime.ID})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "void")
+	rec = testGetTicketPDF(token, fmt.Sprintf("/api/reservations/%d/tickets.pdf", reservation.ID))
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestTicketToken(t *testing.T) {
+	claims := TicketClaims{ReservationID: 1, ReservationItemID: 2, ShowtimeID: 3, SeatID: 4}
+	token := SignTicketToken("secret", claims)
+
+	parsed, err := ParseTicketToken("secret", token)
+	require.NoError(t, err)
+	require.Equal(t, claims, *parsed)
+
+	_, err = ParseTicketToken("other", token)
+	require.Error(t, err)
+
+	// swap the payload with another seat keeping the signature
+	parts := strings.Split(token, ".")
+	forged := strings.Split(SignTicketToken("other", TicketClaims{ReservationID: 1, ReservationItemID: 2, ShowtimeID: 3, SeatID: 5}), ".")
+	_, err = ParseTicketToken("secret", strings.Join([]string{parts[0], forged[1], parts[2]}, "."))
+	require.Error(t, err)
+
+	_, err = ParseTicketToken("secret", "garbage")
+	require.Error(t, err)
+}
+
+func testListTicket(t *testing.T, token string, ID int64) ([]Ticket, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/reservations/%d/tickets", ID), nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]Ticket]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testGetTicketQRCode(token string, ID, itemID int64, format TicketQRFormat) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/reservations/%d/tickets/%d/qr?format=%s", ID, itemID, format), nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+func testCheckIn(t *testing.T, token string, input CheckInInput) (*Admission, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/staff/check-in", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Admission]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testSyncCheckIn(t *testing.T, token string, input CheckInSyncInput) ([]CheckInResult, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/staff/check-in/sync", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]CheckInResult]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testGetAttendance(t *testing.T, token string, showtimeID int64) (*Attendance, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("/api/staff/showtimes/%d/attendance", showtimeID), nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Attendance]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testGetTicketPDF(token string, path string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
@@ -0,0 +1,238 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewTicketTypeHandler(c *Config, trxProvider *TransactionProvider) *TicketTypeHandler {
+	return &TicketTypeHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type TicketTypeHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Create
+//
+//	@Summary		Create Ticket Type
+//	@Description	admin create ticket type
+//	@Tags			ticket types
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			request			body		TicketTypeInput	true	"body request"
+//	@Success		200				{object}	Response[TicketType]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/ticket-types [post]
+func (h *TicketTypeHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input TicketTypeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var ticketType *TicketType
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		ticketType, err = service.TicketType.Create(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*TicketType]{Message: "ok", Data: ticketType})
+}
+
+// UpdateByID
+//
+//	@Summary		Update Ticket Type
+//	@Description	admin update ticket type by id
+//	@Tags			ticket types
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			id				path		int				true	"ticket type id"
+//	@Param			request			body		TicketTypeInput	true	"body request"
+//	@Success		200				{object}	Response[TicketType]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/ticket-types/{id} [put]
+func (h *TicketTypeHandler) UpdateByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input TicketTypeInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var ticketType *TicketType
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		ticketType, err = service.TicketType.UpdateByID(ctx, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*TicketType]{Message: "ok", Data: ticketType})
+}
+
+// GetByID
+//
+//	@Summary		Get Ticket Type
+//	@Description	get ticket type by id
+//	@Tags			ticket types
+//	@Accept			json
+//	@Produce		json
+//	@Param			id	path		int	true	"ticket type id"
+//	@Success		200	{object}	Response[TicketType]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/ticket-types/{id} [get]
+func (h *TicketTypeHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var ticketType *TicketType
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		ticketType, err = service.TicketType.GetByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*TicketType]{Message: "ok", Data: ticketType})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete Ticket Type
+//	@Description	admin delete ticket type by id
+//	@Tags			ticket types
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"ticket type id"
+//	@Success		200				{object}	Response[any]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/ticket-types/{id} [delete]
+func (h *TicketTypeHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.TicketType.DeleteByID(ctx, int64(ID))
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// List
+//
+//	@Summary		List Ticket Type
+//	@Description	list available ticket types
+//	@Tags			ticket types
+//	@Accept			json
+//	@Produce		json
+//	@Success		200	{object}	Response[[]TicketType]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/ticket-types [get]
+func (h *TicketTypeHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	active := true
+	filter := TicketTypeFilter{IsActive: &active}
+
+	var ticketTypes []TicketType
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		ticketTypes, err = service.TicketType.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]TicketType]{Message: "ok", Data: ticketTypes})
+}
+
+// Filter
+//
+//	@Summary		Filter Ticket Type
+//	@Description	admin filter ticket types, including inactive one
+//	@Tags			ticket types
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			request			body		TicketTypeFilter	false	"filter"
+//	@Success		200				{object}	Response[[]TicketType]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/ticket-types/filter [post]
+func (h *TicketTypeHandler) Filter(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var filter TicketTypeFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var ticketTypes []TicketType
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		ticketTypes, err = service.TicketType.List(ctx, filter)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[[]TicketType]{Message: "ok", Data: ticketTypes})
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketType(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	ticketTypes, rec := testListTicketType(t)
+	require.Equal(t, http.StatusOK, rec.Code)
+	ticketTypeMap := map[string]TicketType{}
+	for _, ticketType := range ticketTypes {
+		ticketTypeMap[ticketType.Code] = ticketType
+	}
+	adult, child := ticketTypeMap["adult"], ticketTypeMap["child"]
+	require.True(t, adult.IsDefault)
+	require.Equal(t, adult.ID, child.RequireTicketTypeID)
+
+	t.Run("CreateOK", func(t *testing.T) {
+		ticketType, rec := testCreateTicketType(t, tokenAdmin, TicketTypeInput{
+			Code:    randomString(6),
+			Name:    randomString(6),
+			Percent: -15,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.NotNil(t, ticketType)
+		require.True(t, ticketType.IsActive)
+		require.False(t, ticketType.IsDefault)
+	})
+
+	t.Run("CreateFailRequireInvalid", func(t *testing.T) {
+		_, rec := testCreateTicketType(t, tokenAdmin, TicketTypeInput{
+			Code:                randomString(6),
+			Name:                randomString(6),
+			RequireTicketTypeID: -1,
+		})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("Reservation", func(t *testing.T) {
+		genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+			Title:       randomString(5),
+			ReleaseDate: time.Now(),
+			Director:    randomString(5),
+			Duration:    33,
+			PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+			Description: randomString(5),
+			GenreIDs:    []int64{genre.ID},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{
+			{Name: randomString(5)},
+			{Name: randomString(5)},
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+		seats, rec := testListRoomSeats(t, room.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		showStartAt := time.Now().Add(3 * 24 * time.Hour)
+		showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+			MovieID: movie.ID,
+			RoomID:  room.ID,
+			StartAt: showStartAt,
+			EndAt:   showStartAt.Add(movie.GetDuration()),
+			Price:   50_000,
+		})
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		userInput := UserInput{
+			Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+			Password: "12345678",
+		}
+		_, rec = testRegisterUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+		token, rec := testLoginUser(t, userInput)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		childCart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID, TicketTypeID: child.ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, child.ID, childCart.TicketTypeID)
+		require.Equal(t, int64(35_000), childCart.Price)
+
+		// child alone is not eligible
+		_, rec = testCreateReservation(t, token, ReservationInput{CartIDs: []int64{childCart.ID}})
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+
+		adultCart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[1].ID})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, adult.ID, adultCart.TicketTypeID)
+		require.Equal(t, int64(50_000), adultCart.Price)
+
+		reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{childCart.ID, adultCart.ID}})
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, int64(85_000), reservation.TotalPrice)
+
+		reservation, rec = testGetReservation(t, token, reservation.ID)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Len(t, reservation.Items, 2)
+		for _, item := range reservation.Items {
+			require.NotNil(t, item.Pricing)
+			require.NotNil(t, item.Pricing.TicketType)
+			require.Equal(t, item.TicketTypeID, item.Pricing.TicketType.TicketTypeID)
+			if item.TicketTypeID == child.ID {
+				require.Equal(t, int64(-15_000), item.Pricing.TicketType.Adjustment)
+			}
+		}
+	})
+}
+
+func testListTicketType(t *testing.T) ([]TicketType, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/ticket-types", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[[]TicketType]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testCreateTicketType(t *testing.T, token string, input TicketTypeInput) (*TicketType, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/ticket-types", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*TicketType]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
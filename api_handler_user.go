@@ -1,8 +1,10 @@
 package main
 
 import (
+	"math"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/labstack/echo/v4"
 )
@@ -27,7 +29,7 @@ type RegisterUserReq struct {
 // Register
 //
 //	@Summary		Register New User
-//	@Description	register using email and password
+//	@Description	register using email and password, verification token is sent to the email
 //	@Tags			accounts
 //	@Accept			json
 //	@Param			request	body	RegisterUserReq	true	"req"
@@ -63,20 +65,21 @@ func (h *UserHandler) Register(c echo.Context) error {
 
 type LoginUserReq = RegisterUserReq
 
-type LoginUserRes struct {
-	Token string `json:"token"`
-}
+type LoginUserRes = AuthToken
 
 // Login
 //
 //	@Summary		Login User
-//	@Description	login using email and password
+//	@Description	login using email and password, access token is short-lived, use refresh token to get a new one.
+//	@Description	When two-factor is enabled, only mfa token is returned, send it with the code to /api/login/mfa.
+//	@Description	Repeated failure lock the account and the ip for a while, see Retry-After header
 //	@Tags			accounts
 //	@Accept			json
 //	@Param			request	body	LoginUserReq	true	"req"
 //	@Produce		json
 //	@Success		200	{object}	Response[LoginUserRes]
 //	@Failure		400	{object}	Response[any]
+//	@Failure		429	{object}	Response[any]
 //	@Failure		500	{object}	Response[any]
 //	@Router			/api/login [post]
 func (h *UserHandler) Login(c echo.Context) error {
@@ -88,10 +91,125 @@ func (h *UserHandler) Login(c echo.Context) error {
 	}
 	c.Set(KeyInput, map[string]any{"email": input.Email})
 
-	var token string
+	err := h.checkLogin(c, input.Email)
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var token *AuthToken
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		token, err = service.User.Login(ctx, input, c.Request().UserAgent())
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil && !ErrIs(err, ErrInput) {
+		return NewAPIErr(c, err)
+	}
+
+	attempt := NewLoginAttempt(input.Email, c.RealIP(), c.Request().UserAgent(), LoginStepPassword, err)
+	recordErr := h.recordLogin(c, attempt, err == nil && !token.MFARequired)
+	if recordErr != nil {
+		return NewAPIErr(c, recordErr)
+	}
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*LoginUserRes]{Message: "ok", Data: token})
+}
+
+// LoginMFA
+//
+//	@Summary		Login Two-Factor
+//	@Description	second step of login using mfa token from login and TOTP code or backup code
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			request	body	MFALoginInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[LoginUserRes]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		429	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/login/mfa [post]
+func (h *UserHandler) LoginMFA(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input MFALoginInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	// failed code count against the account of the challenge, so the code cannot be guessed with new challenges
+	var email string
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		email, err = service.User.MFAChallengeEmail(ctx, input.MFAToken)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+	err = h.checkLogin(c, email)
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var token *AuthToken
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		token, err = service.User.LoginMFA(ctx, input, c.Request().UserAgent())
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil && !ErrIs(err, ErrInput) {
+		return NewAPIErr(c, err)
+	}
+
+	attempt := NewLoginAttempt(email, c.RealIP(), c.Request().UserAgent(), LoginStepMFA, err)
+	recordErr := h.recordLogin(c, attempt, err == nil)
+	if recordErr != nil {
+		return NewAPIErr(c, recordErr)
+	}
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*LoginUserRes]{Message: "ok", Data: token})
+}
+
+// Refresh
+//
+//	@Summary		Refresh Token
+//	@Description	exchange refresh token with new access token, the refresh token is rotated and cannot be used again
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			request	body	RefreshTokenInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[LoginUserRes]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		401	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/refresh [post]
+func (h *UserHandler) Refresh(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input RefreshTokenInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	input.UserAgent = c.Request().UserAgent()
+
+	// revocation is committed on its own, the refresh below fail anyway
+	var reused bool
 	var err error
 	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
-		token, err = service.User.Login(ctx, input)
+		reused, err = service.User.RevokeReusedRefreshToken(ctx, input)
 		if err != nil {
 			return err
 		}
@@ -100,8 +218,262 @@ func (h *UserHandler) Login(c echo.Context) error {
 	if err != nil {
 		return NewAPIErr(c, err)
 	}
+	if reused {
+		return c.JSON(http.StatusUnauthorized, Response[any]{Message: "refresh token already used, session revoked"})
+	}
 
-	return c.JSON(http.StatusOK, Response[any]{Message: "ok", Data: LoginUserRes{Token: token}})
+	var token *AuthToken
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		token, err = service.User.Refresh(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*LoginUserRes]{Message: "ok", Data: token})
+}
+
+// Logout
+//
+//	@Summary		Logout
+//	@Description	revoke current session, the access token and refresh token stop working
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string	true	"bearer token"
+//	@Produce		json
+//	@Success		200	{object}	Response[any]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/logout [post]
+func (h *UserHandler) Logout(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+	sessionID, _ := GetTokenSession(c)
+
+	err := h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.User.Logout(ctx, userID, sessionID)
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// LogoutAll
+//
+//	@Summary		Logout All Sessions
+//	@Description	revoke every session of current user on all devices
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string	true	"bearer token"
+//	@Produce		json
+//	@Success		200	{object}	Response[any]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/logout/all [post]
+func (h *UserHandler) LogoutAll(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	err := h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.User.LogoutAll(ctx, userID)
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// VerifyEmail
+//
+//	@Summary		Verify Email
+//	@Description	confirm email using token sent on registration
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			request	body	VerifyEmailInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[User]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/email/verify [post]
+func (h *UserHandler) VerifyEmail(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input VerifyEmailInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var user *User
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		user, err = service.User.VerifyEmail(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*User]{Message: "ok", Data: user})
+}
+
+// ResendEmailVerification
+//
+//	@Summary		Resend Email Verification
+//	@Description	send a new verification token to email of current user, limited per hour
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string	true	"bearer token"
+//	@Produce		json
+//	@Success		200	{object}	Response[any]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/email/verify/resend [post]
+func (h *UserHandler) ResendEmailVerification(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+
+	err := h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.User.ResendEmailVerification(ctx, userID)
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// ForgotPassword
+//
+//	@Summary		Forgot Password
+//	@Description	send password reset token to the email, response is the same whether the email is registered or not
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			request	body	ForgotPasswordInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[any]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/password/forgot [post]
+func (h *UserHandler) ForgotPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input ForgotPasswordInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	err := h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.User.ForgotPassword(ctx, input)
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// ResetPassword
+//
+//	@Summary		Reset Password
+//	@Description	set new password using token from forgot password email, every session is logged out
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			request	body	ResetPasswordInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[any]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/password/reset [post]
+func (h *UserHandler) ResetPassword(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input ResetPasswordInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	err := h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.User.ResetPassword(ctx, input)
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// ChangePassword
+//
+//	@Summary		Change Password
+//	@Description	change password of current user, other sessions are logged out and current session get a new token
+//	@Tags			accounts
+//	@Accept			json
+//	@Param			Authorization	header	string				true	"bearer token"
+//	@Param			request			body	ChangePasswordInput	true	"req"
+//	@Produce		json
+//	@Success		200	{object}	Response[LoginUserRes]
+//	@Failure		400	{object}	Response[any]
+//	@Failure		500	{object}	Response[any]
+//	@Router			/api/user/password [put]
+func (h *UserHandler) ChangePassword(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+	sessionID, _ := GetTokenSession(c)
+
+	var input ChangePasswordInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	var token *AuthToken
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		token, err = service.User.ChangePassword(ctx, userID, sessionID, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*LoginUserRes]{Message: "ok", Data: token})
+}
+
+// JWKS
+//
+//	@Summary		JSON Web Key Set
+//	@Description	public keys to verify access token, keys are identified by kid header of the token
+//	@Tags			accounts
+//	@Produce		json
+//	@Success		200	{object}	JWKS
+//	@Router			/.well-known/jwks.json [get]
+func (h *UserHandler) JWKS(c echo.Context) error {
+	c.Response().Header().Set(echo.HeaderCacheControl, "public, max-age=300")
+	return c.JSON(http.StatusOK, h.config.JWTKeys.JWKS())
+}
+
+// VerifySession check the parsed token still belong to an active session, used by jwtMiddleware
+func (h *UserHandler) VerifySession(c echo.Context) error {
+	ctx := c.Request().Context()
+	userID, _, _ := GetTokenInfo(c)
+	sessionID, tokenVersion := GetTokenSession(c)
+
+	return h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.User.VerifySession(ctx, userID, sessionID, tokenVersion)
+	})
 }
 
 // LoggedIn get current user using token
@@ -259,3 +631,268 @@ func (h *UserHandler) PaginationRole(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, Response[*Paginate[Role]]{Message: "ok", Data: res})
 }
+
+// Pagination
+//
+//	@Summary		Filter User
+//	@Description	admin filter users by id, email, role, part of email or disabled
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string		true	"bearer token"
+//	@Param			page			query		int			false	"pagination page"
+//	@Param			per_page		query		int			false	"pagination page size"
+//	@Param			request			body		UserFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[User]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/users/filter [post]
+func (h *UserHandler) Pagination(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter UserFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[User]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.User.Pagination(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[User]]{Message: "ok", Data: res})
+}
+
+// GetByID
+//
+//	@Summary		Get User
+//	@Description	admin get user by id
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"user id"
+//	@Success		200				{object}	Response[User]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id} [get]
+func (h *UserHandler) GetByID(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var user *User
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		user, err = service.User.GetByID(ctx, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*User]{Message: "ok", Data: user})
+}
+
+// DisableByID
+//
+//	@Summary		Disable User
+//	@Description	admin disable user, login is refused and every session is revoked, admin and own account cannot be disabled
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			id				path		int					true	"user id"
+//	@Param			request			body		DisableUserInput	false	"req"
+//	@Success		200				{object}	Response[User]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id}/disable [put]
+func (h *UserHandler) DisableByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	actorID, _, _ := GetTokenInfo(c)
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var input DisableUserInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var user *User
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		user, err = service.User.DisableByID(ctx, actorID, int64(ID), input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*User]{Message: "ok", Data: user})
+}
+
+// EnableByID
+//
+//	@Summary		Enable User
+//	@Description	admin enable disabled user, the user can login again
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"user id"
+//	@Success		200				{object}	Response[User]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id}/enable [put]
+func (h *UserHandler) EnableByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	actorID, _, _ := GetTokenInfo(c)
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var user *User
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		user, err = service.User.EnableByID(ctx, actorID, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*User]{Message: "ok", Data: user})
+}
+
+// ForcePasswordReset
+//
+//	@Summary		Force Password Reset
+//	@Description	admin invalidate password of the user and send reset token to the email, every session is revoked
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"user id"
+//	@Success		200				{object}	Response[User]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id}/password/reset [post]
+func (h *UserHandler) ForcePasswordReset(c echo.Context) error {
+	ctx := c.Request().Context()
+	actorID, _, _ := GetTokenInfo(c)
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	var user *User
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		user, err = service.User.ForcePasswordReset(ctx, actorID, int64(ID))
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*User]{Message: "ok", Data: user})
+}
+
+// DeleteByID
+//
+//	@Summary		Delete User
+//	@Description	admin delete user, personal data, carts and sessions are erased and past reservations are kept. User with wallet balance, unpaid or upcoming reservation, admin and own account cannot be deleted
+//	@Tags			accounts
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			id				path		int		true	"user id"
+//	@Success		200				{object}	Response[any]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		404				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/user/{id} [delete]
+func (h *UserHandler) DeleteByID(c echo.Context) error {
+	ctx := c.Request().Context()
+	actorID, _, _ := GetTokenInfo(c)
+
+	ID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return NewAPIErr(c, NewErr(ErrInput, err, "id invalid"))
+	}
+
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.User.DeleteByID(ctx, actorID, int64(ID))
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[any]{Message: "ok"})
+}
+
+// checkLogin refuse login while the account or the ip is locked after failed attempts,
+// otherwise the attempt is counted as failed until recordLogin, see LoginAttemptService.Attempt
+func (h *UserHandler) checkLogin(c echo.Context, email string) error {
+	ctx := c.Request().Context()
+
+	var wait time.Duration
+	var err error
+	err = h.trxProvider.TransactReadCommitted(ctx, func(service *ServiceRegistry) error {
+		wait, err = service.LoginAttempt.Attempt(ctx, email, c.RealIP())
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if wait > 0 {
+		seconds := int64(math.Ceil(wait.Seconds()))
+		c.Response().Header().Set(echo.HeaderRetryAfter, strconv.FormatInt(seconds, 10))
+		return NewErr(ErrTooMany, nil, "too many failed login, try again in %d seconds", seconds)
+	}
+	return nil
+}
+
+// recordLogin save the attempt in its own transaction, a failed login is rolled back but the attempt must be kept
+func (h *UserHandler) recordLogin(c echo.Context, attempt *LoginAttempt, signedIn bool) error {
+	ctx := c.Request().Context()
+	return h.trxProvider.TransactReadCommitted(ctx, func(service *ServiceRegistry) error {
+		return service.LoginAttempt.Record(ctx, attempt, signedIn)
+	})
+}
@@ -2,14 +2,26 @@ package main
 
 import (
 	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
 	"encoding/json"
+	"encoding/pem"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/labstack/echo/v4"
 	"github.com/stretchr/testify/require"
 )
@@ -108,7 +120,512 @@ func TestChangeUserRoleOK(t *testing.T) {
 
 }
 
+func TestSession(t *testing.T) {
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	login, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, login.RefreshToken)
+	require.True(t, login.ExpiresAt.Before(time.Now().Add(time.Hour)))
+
+	// refresh token is rotated
+	refreshed, rec := testRefreshToken(t, login.RefreshToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+	_, rec = testCurrentUser(t, "Bearer "+refreshed.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// reusing rotated refresh token revoke the session
+	_, rec = testRefreshToken(t, login.RefreshToken)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	_, rec = testRefreshToken(t, refreshed.RefreshToken)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testCurrentUser(t, "Bearer "+refreshed.Token)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// logout only revoke current session
+	first, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	second, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testLogout(t, "Bearer "+first.Token, "/api/logout")
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testCurrentUser(t, "Bearer "+first.Token)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	_, rec = testRefreshToken(t, first.RefreshToken)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testCurrentUser(t, "Bearer "+second.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// logout all revoke every session
+	third, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testLogout(t, "Bearer "+second.Token, "/api/logout/all")
+	require.Equal(t, http.StatusOK, rec.Code)
+	for _, token := range []*AuthToken{second, third} {
+		_, rec = testCurrentUser(t, "Bearer "+token.Token)
+		require.Equal(t, http.StatusUnauthorized, rec.Code)
+		_, rec = testRefreshToken(t, token.RefreshToken)
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	}
+}
+
+func TestSessionRoleChange(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	newUser, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	login, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	pRoles, rec := testPaginationRole(t, tokenAdmin, RoleFilter{Names: []string{UserStaff}}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotEmpty(t, pRoles.Items)
+	_, rec = testChangeUserRole(t, tokenAdmin, newUser.ID, pRoles.Items[0].ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// old token is rejected immediately, refreshed token carry the new role
+	_, rec = testCurrentUser(t, "Bearer "+login.Token)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	refreshed, rec := testRefreshToken(t, login.RefreshToken)
+	require.Equal(t, http.StatusOK, rec.Code)
+	cur, rec := testCurrentUser(t, "Bearer "+refreshed.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, UserStaff, cur.Role)
+}
+
+func TestJWKS(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var jwks JWKS
+	err := json.Unmarshal(rec.Body.Bytes(), &jwks)
+	require.NoError(t, err)
+	require.Len(t, jwks.Keys, 2)
+	require.Equal(t, "EdDSA", jwks.Keys[0].Algorithm)
+	require.Equal(t, "RS256", jwks.Keys[1].Algorithm)
+
+	// token is verifiable with the published key only
+	token := strings.TrimPrefix(testLoginAdmin(t), "Bearer ")
+	parsed, err := jwt.ParseWithClaims(token, new(JwtCustomClaims), func(token *jwt.Token) (any, error) {
+		require.Equal(t, jwks.Keys[0].KeyID, token.Header["kid"])
+		x, err := base64.RawURLEncoding.DecodeString(jwks.Keys[0].X)
+		require.NoError(t, err)
+		return ed25519.PublicKey(x), nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, UserAdmin, parsed.Claims.(*JwtCustomClaims).Role)
+}
+
+func TestJWTKeySet(t *testing.T) {
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	old, err := NewJWTKeySet("", rsaKey)
+	require.NoError(t, err)
+	rotated, err := NewJWTKeySet("", edKey, rsaKey)
+	require.NoError(t, err)
+	retired, err := NewJWTKeySet("", edKey)
+	require.NoError(t, err)
+	require.Equal(t, old.JWKS().Keys[0].KeyID, rotated.JWKS().Keys[1].KeyID)
+
+	claims := &JwtCustomClaims{ID: 1, RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute))}}
+	token, err := old.Sign(claims)
+	require.NoError(t, err)
+
+	// old token is valid while the key is still in the set
+	_, err = jwt.ParseWithClaims(token, new(JwtCustomClaims), rotated.Keyfunc)
+	require.NoError(t, err)
+	_, err = jwt.ParseWithClaims(token, new(JwtCustomClaims), retired.Keyfunc)
+	require.Error(t, err)
+
+	// public key cannot be used as HMAC secret
+	jwk := rotated.JWKS().Keys[0]
+	x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+	require.NoError(t, err)
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	forged.Header["kid"] = jwk.KeyID
+	forgedToken, err := forged.SignedString(x)
+	require.NoError(t, err)
+	_, err = jwt.ParseWithClaims(forgedToken, new(JwtCustomClaims), rotated.Keyfunc)
+	require.Error(t, err)
+}
+
+func TestConfigLoad(t *testing.T) {
+	config := NewConfig()
+	config.JWTSecret = DefaultJWTSecret
+	config.JWTKeyFiles = nil
+	config.TicketSecret = randomString(32)
+
+	config.DevMode = false
+	require.Error(t, config.Load())
+
+	config.DevMode = true
+	require.NoError(t, config.Load())
+
+	// default ticket secret is refused even with secure jwt key
+	config.JWTSecret = randomString(32)
+	config.TicketSecret = DefaultTicketSecret
+	config.DevMode = false
+	require.ErrorContains(t, config.Load(), "ticket secret")
+	config.DevMode = true
+	require.NoError(t, config.Load())
+	config.TicketSecret = randomString(32)
+
+	// PKCS#8 Ed25519 key from file
+	_, edKey, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(edKey)
+	require.NoError(t, err)
+	file := filepath.Join(t.TempDir(), "jwt.pem")
+	err = os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0600)
+	require.NoError(t, err)
+
+	config.DevMode = false
+	config.JWTKeyFiles = []string{file}
+	require.NoError(t, config.Load())
+	require.Len(t, config.JWTKeys.JWKS().Keys, 1)
+}
+
+func TestPasswordReset(t *testing.T) {
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	login, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// unknown email look the same
+	rec = testForgotPassword(t, fmt.Sprintf("%s@mail.com", randomString(6)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// only the latest token is usable
+	rec = testForgotPassword(t, input.Email)
+	require.Equal(t, http.StatusOK, rec.Code)
+	oldToken := testLastMailToken(t, input.Email)
+	rec = testForgotPassword(t, input.Email)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token := testLastMailToken(t, input.Email)
+	require.NotEqual(t, oldToken, token)
+
+	rec = testResetPassword(t, ResetPasswordInput{Token: oldToken, Password: "87654321"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = testResetPassword(t, ResetPasswordInput{Token: token, Password: "short"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = testResetPassword(t, ResetPasswordInput{Token: token, Password: "87654321"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// single-use
+	rec = testResetPassword(t, ResetPasswordInput{Token: token, Password: "11223344"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// existing sessions are revoked
+	_, rec = testCurrentUser(t, "Bearer "+login.Token)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	_, rec = testRefreshToken(t, login.RefreshToken)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testLoginUser(t, input)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testLoginUser(t, UserInput{Email: input.Email, Password: "87654321"})
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestChangePassword(t *testing.T) {
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	current, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	other, rec := testLoginSession(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	_, rec = testChangePassword(t, "Bearer "+current.Token, ChangePasswordInput{CurrentPassword: "wrong-password", NewPassword: "87654321"})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testChangePassword(t, "Bearer "+current.Token, ChangePasswordInput{CurrentPassword: input.Password, NewPassword: input.Password})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	renewed, rec := testChangePassword(t, "Bearer "+current.Token, ChangePasswordInput{CurrentPassword: input.Password, NewPassword: "87654321"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// current session continue with the new token, other session is logged out
+	_, rec = testCurrentUser(t, "Bearer "+renewed.Token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testCurrentUser(t, "Bearer "+current.Token)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	_, rec = testCurrentUser(t, "Bearer "+other.Token)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	_, rec = testRefreshToken(t, other.RefreshToken)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testLoginUser(t, UserInput{Email: input.Email, Password: "87654321"})
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAdminUserManagement(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+	admin, rec := testCurrentUser(t, tokenAdmin)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	prefix := strings.ToLower(randomString(8))
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", prefix),
+		Password: "12345678",
+	}
+	user, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	tokenUser, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// search by part of email
+	pUsers, rec := testPaginationUser(t, tokenAdmin, UserFilter{Search: strings.ToUpper(prefix)}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, pUsers.Items, 1)
+	require.Equal(t, user.ID, pUsers.Items[0].ID)
+	_, rec = testPaginationUser(t, tokenUser, UserFilter{}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	pReservations, rec := testAdminUserList[Reservation](t, tokenAdmin, user.ID, "reservations")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, pReservations.Items)
+	pCarts, rec := testAdminUserList[Cart](t, tokenAdmin, user.ID, "carts")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Empty(t, pCarts.Items)
+
+	// admin and own account cannot be managed
+	_, rec = testAdminUserAction(t, tokenAdmin, http.MethodPut, admin.ID, "/disable", nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testAdminUserAction(t, tokenAdmin, http.MethodDelete, admin.ID, "", nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	disabled, rec := testAdminUserAction(t, tokenAdmin, http.MethodPut, user.ID, "/disable", DisableUserInput{Reason: "fraud"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, disabled.DisabledAt)
+	require.Equal(t, "fraud", disabled.DisabledReason)
+	_, rec = testAdminUserAction(t, tokenAdmin, http.MethodPut, user.ID, "/disable", nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// existing token and login are refused
+	_, rec = testCurrentUser(t, tokenUser)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = testLoginFrom(t, input, "198.51.100.49")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "disabled")
+
+	disabledOnly := true
+	pUsers, rec = testPaginationUser(t, tokenAdmin, UserFilter{IDs: []int64{user.ID}, IsDisabled: &disabledOnly}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, pUsers.Items, 1)
+
+	enabled, rec := testAdminUserAction(t, tokenAdmin, http.MethodPut, user.ID, "/enable", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Nil(t, enabled.DisabledAt)
+	tokenUser, rec = testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// old password stop working, user set a new one with the mailed token
+	_, rec = testAdminUserAction(t, tokenAdmin, http.MethodPost, user.ID, "/password/reset", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testCurrentUser(t, tokenUser)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	rec = testLoginFrom(t, input, "198.51.100.49")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = testResetPassword(t, ResetPasswordInput{Token: testLastMailToken(t, input.Email), Password: "87654321"})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testLoginUser(t, UserInput{Email: input.Email, Password: "87654321"})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	_, rec = testAdminUserAction(t, tokenAdmin, http.MethodDelete, user.ID, "", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testAdminUserAction(t, tokenAdmin, http.MethodGet, user.ID, "", nil)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+
+	// email is erased, it can be registered again
+	rec = testLoginFrom(t, UserInput{Email: input.Email, Password: "87654321"}, "198.51.100.49")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAdminUserManagementPrivilege(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	pRoles, rec := testPaginationRole(t, tokenAdmin, RoleFilter{Names: []string{"support", "finance"}}, PaginateInput{1, 10})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, pRoles.Items, 2)
+	roles := map[string]int64{}
+	for _, role := range pRoles.Items {
+		roles[role.Name] = role.ID
+	}
+
+	newUser := func(roleID int64) (*User, UserInput) {
+		input := UserInput{
+			Email:    fmt.Sprintf("%s@mail.com", randomString(8)),
+			Password: "12345678",
+		}
+		user, rec := testRegisterUser(t, input)
+		require.Equal(t, http.StatusOK, rec.Code)
+		if roleID > 0 {
+			_, rec = testChangeUserRole(t, tokenAdmin, user.ID, roleID)
+			require.Equal(t, http.StatusOK, rec.Code)
+		}
+		return user, input
+	}
+	_, supportInput := newUser(roles["support"])
+	otherSupport, _ := newUser(roles["support"])
+	finance, _ := newUser(roles["finance"])
+	user, _ := newUser(0)
+
+	tokenSupport, rec := testLoginUser(t, supportInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// support cannot take over user holding permission support lack
+	_, rec = testAdminUserAction(t, tokenSupport, http.MethodPut, finance.ID, "/disable", nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "permission")
+	_, rec = testAdminUserAction(t, tokenSupport, http.MethodPost, finance.ID, "/password/reset", nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	_, rec = testAdminUserAction(t, tokenSupport, http.MethodDelete, finance.ID, "", nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// same or fewer permission can be managed
+	_, rec = testAdminUserAction(t, tokenSupport, http.MethodPut, otherSupport.ID, "/disable", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testAdminUserAction(t, tokenSupport, http.MethodPut, user.ID, "/disable", nil)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAdminUserDeleteKeepReservation(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   50_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(8)),
+		Password: "12345678",
+	}
+	user, rec := testRegisterUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testPayReservation(t, token, reservation.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// paid booking of upcoming showtime cannot be thrown away with the account
+	_, rec = testAdminUserAction(t, tokenAdmin, http.MethodDelete, user.ID, "", nil)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "reservation")
+
+	paid, rec := testGetReservation(t, token, reservation.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, ReservationPaid, paid.Status)
+	_, rec = testCurrentUser(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestEmailVerification(t *testing.T) {
+	input := UserInput{
+		Email:    fmt.Sprintf("%s@mail.com", randomString(5)),
+		Password: "12345678",
+	}
+	newUser, rec := testRegisterUnverifiedUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Nil(t, newUser.EmailVerifiedAt)
+	token := testLastMailToken(t, input.Email)
+
+	// unverified user can login but cannot make reservation
+	tokenUser, rec := testLoginUser(t, input)
+	require.Equal(t, http.StatusOK, rec.Code)
+	_, rec = testCreateReservation(t, tokenUser, ReservationInput{CartIDs: []int64{1}})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Contains(t, rec.Body.String(), "verify your email")
+
+	// registration mail was just sent
+	rec = testResendEmailVerification(t, tokenUser)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Equal(t, token, testLastMailToken(t, input.Email))
+
+	user, rec := testVerifyEmail(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, user.EmailVerifiedAt)
+
+	_, rec = testVerifyEmail(t, token)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	rec = testResendEmailVerification(t, tokenUser)
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	_, rec = testCreateReservation(t, tokenUser, ReservationInput{CartIDs: []int64{1}})
+	require.NotContains(t, rec.Body.String(), "verify your email")
+
+	// seeded user exist before verification is verified
+	cur, rec := testCurrentUser(t, testLoginAdmin(t))
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotNil(t, cur.EmailVerifiedAt)
+}
+
+// testRegisterUser register then verify the email so the user can make reservation
 func testRegisterUser(t *testing.T, input UserInput) (*User, *httptest.ResponseRecorder) {
+	user, rec := testRegisterUnverifiedUser(t, input)
+	if rec.Code != http.StatusOK {
+		return user, rec
+	}
+	verified, verifyRec := testVerifyEmail(t, testLastMailToken(t, user.Email))
+	require.Equal(t, http.StatusOK, verifyRec.Code)
+	return verified, rec
+}
+
+func testRegisterUnverifiedUser(t *testing.T, input UserInput) (*User, *httptest.ResponseRecorder) {
 	p, err := json.Marshal(input)
 	require.NoError(t, err)
 
@@ -227,3 +744,182 @@ func testPaginationRole(t *testing.T, token string, filter RoleFilter, page Pagi
 
 	return res.Data, rec
 }
+
+func testLoginSession(t *testing.T, input UserInput) (*AuthToken, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/login", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*AuthToken]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testRefreshToken(t *testing.T, refreshToken string) (*AuthToken, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(RefreshTokenInput{RefreshToken: refreshToken})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/refresh", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*AuthToken]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testLogout(t *testing.T, token string, uri string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, uri, nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+func testForgotPassword(t *testing.T, email string) *httptest.ResponseRecorder {
+	p, err := json.Marshal(ForgotPasswordInput{Email: email})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/password/forgot", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+func testResetPassword(t *testing.T, input ResetPasswordInput) *httptest.ResponseRecorder {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/password/reset", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+func testChangePassword(t *testing.T, token string, input ChangePasswordInput) (*AuthToken, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPut, "/api/user/password", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*AuthToken]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+// testLastMailToken read the token in the latest mail sent to the email by the file mailer
+func testLastMailToken(t *testing.T, email string) string {
+	dir := NewConfig().MailDir
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+
+	var last string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), "-"+email+".eml") {
+			last = entry.Name() // sorted by name, the name start with send time
+		}
+	}
+	require.NotEmpty(t, last, "no mail sent to %s", email)
+
+	data, err := os.ReadFile(filepath.Join(dir, last))
+	require.NoError(t, err)
+	token := regexp.MustCompile(`[0-9a-f]{64}`).Find(data)
+	require.NotNil(t, token)
+	return string(token)
+}
+
+func testVerifyEmail(t *testing.T, token string) (*User, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(VerifyEmailInput{Token: token})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/email/verify", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*User]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testResendEmailVerification(t *testing.T, token string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/email/verify/resend", nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+	return rec
+}
+
+func testPaginationUser(t *testing.T, token string, filter UserFilter, page PaginateInput) (*Paginate[User], *httptest.ResponseRecorder) {
+	p, err := json.Marshal(filter)
+	require.NoError(t, err)
+
+	q := make(url.Values)
+	q.Set("page", strconv.Itoa(int(page.Page)))
+	q.Set("per_page", strconv.Itoa(int(page.Size)))
+
+	uri := "/api/admin/users/filter?" + q.Encode()
+	req := httptest.NewRequest(http.MethodPost, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Paginate[User]]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+// testAdminUserAction call admin endpoint of the user, path is appended to /api/admin/user/{id}
+func testAdminUserAction(t *testing.T, token, method string, ID int64, path string, input any) (*User, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	uri := fmt.Sprintf("/api/admin/user/%d%s", ID, path)
+	req := httptest.NewRequest(method, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*User]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testAdminUserList[T any](t *testing.T, token string, ID int64, resource string) (*Paginate[T], *httptest.ResponseRecorder) {
+	uri := fmt.Sprintf("/api/admin/user/%d/%s", ID, resource)
+	req := httptest.NewRequest(http.MethodGet, uri, nil)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Paginate[T]]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
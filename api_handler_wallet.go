@@ -0,0 +1,253 @@
+package main
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+func NewWalletHandler(c *Config, trxProvider *TransactionProvider) *WalletHandler {
+	return &WalletHandler{
+		config:      c,
+		trxProvider: trxProvider,
+	}
+}
+
+type WalletHandler struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Summary
+//
+//	@Summary		Wallet Summary
+//	@Description	logged in user wallet balance and ledger entries
+//	@Tags			wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			page			query		int		false	"pagination page"
+//	@Param			per_page		query		int		false	"pagination page size"
+//	@Success		200				{object}	Response[WalletSummary]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/wallet [get]
+func (h *WalletHandler) Summary(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var summary *WalletSummary
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		summary, err = service.Wallet.Summary(ctx, userID, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*WalletSummary]{Message: "ok", Data: summary})
+}
+
+// TopUp
+//
+//	@Summary		Top Up Wallet
+//	@Description	user top up wallet with gift card
+//	@Tags			wallet
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string				true	"bearer token"
+//	@Param			request			body		WalletTopUpInput	true	"body request"
+//	@Success		200				{object}	Response[Wallet]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/wallet/top-up [post]
+func (h *WalletHandler) TopUp(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	var input WalletTopUpInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var wallet *Wallet
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		wallet, err = service.Wallet.TopUp(ctx, userID, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Wallet]{Message: "ok", Data: wallet})
+}
+
+// PurchaseGiftCard
+//
+//	@Summary		Purchase Gift Card
+//	@Description	user purchase gift card through payment gateway
+//	@Tags			gift cards
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			request			body		GiftCardInput	true	"body request"
+//	@Success		200				{object}	Response[GiftCard]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/gift-cards [post]
+func (h *WalletHandler) PurchaseGiftCard(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+
+	var input GiftCardInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var giftCard *GiftCard
+	var err error
+	err = NewPaymentProcessor(h.config, h.trxProvider).Charge(
+		ctx,
+		func(service *ServiceRegistry) (*Payment, error) {
+			return service.Wallet.StartGiftCardPurchase(ctx, userID, input)
+		},
+		func(service *ServiceRegistry, receipt *PaymentReceipt) error {
+			giftCard, err = service.Wallet.PurchaseGiftCard(ctx, userID, input, receipt)
+			return err
+		},
+	)
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*GiftCard]{Message: "ok", Data: giftCard})
+}
+
+// UserGiftCards
+//
+//	@Summary		List Purchased Gift Card
+//	@Description	logged in user purchased gift cards
+//	@Tags			gift cards
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string	true	"bearer token"
+//	@Param			page			query		int		false	"pagination page"
+//	@Param			per_page		query		int		false	"pagination page size"
+//	@Success		200				{object}	Response[Paginate[GiftCard]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/user/gift-cards [get]
+func (h *WalletHandler) UserGiftCards(c echo.Context) error {
+	userID, _, _ := GetTokenInfo(c)
+
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var res *Paginate[GiftCard]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Wallet.PaginationGiftCard(ctx, GiftCardFilter{PurchaserIDs: []int64{userID}}, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[GiftCard]]{Message: "ok", Data: res})
+}
+
+// IssueGiftCard
+//
+//	@Summary		Issue Gift Card
+//	@Description	admin issue gift card
+//	@Tags			gift cards
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			request			body		GiftCardInput	true	"body request"
+//	@Success		200				{object}	Response[GiftCard]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/gift-cards [post]
+func (h *WalletHandler) IssueGiftCard(c echo.Context) error {
+	ctx := c.Request().Context()
+
+	var input GiftCardInput
+	if err := c.Bind(&input); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, input)
+
+	var giftCard *GiftCard
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		giftCard, err = service.Wallet.IssueGiftCard(ctx, input)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*GiftCard]{Message: "ok", Data: giftCard})
+}
+
+// PaginationGiftCard
+//
+//	@Summary		Filter Gift Card
+//	@Description	admin filter gift cards
+//	@Tags			gift cards
+//	@Accept			json
+//	@Produce		json
+//	@Param			Authorization	header		string			true	"bearer token"
+//	@Param			page			query		int				false	"pagination page"
+//	@Param			per_page		query		int				false	"pagination page size"
+//	@Param			request			body		GiftCardFilter	false	"filter"
+//	@Success		200				{object}	Response[Paginate[GiftCard]]
+//	@Failure		400				{object}	Response[any]
+//	@Failure		500				{object}	Response[any]
+//	@Router			/api/admin/gift-cards/filter [post]
+func (h *WalletHandler) PaginationGiftCard(c echo.Context) error {
+	ctx := c.Request().Context()
+	page := GetPage(c)
+
+	var filter GiftCardFilter
+	if err := c.Bind(&filter); err != nil {
+		return NewAPIErr(c, err)
+	}
+	c.Set(KeyInput, filter)
+
+	var res *Paginate[GiftCard]
+	var err error
+	err = h.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		res, err = service.Wallet.PaginationGiftCard(ctx, filter, page)
+		if err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return NewAPIErr(c, err)
+	}
+
+	return c.JSON(http.StatusOK, Response[*Paginate[GiftCard]]{Message: "ok", Data: res})
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWallet(t *testing.T) {
+	tokenAdmin := testLoginAdmin(t)
+
+	genre, rec := testCreateGenre(t, tokenAdmin, GenreInput{Name: randomString(4)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	movie, rec := testCreateMovie(t, tokenAdmin, MovieInput{
+		Title:       randomString(5),
+		ReleaseDate: time.Now(),
+		Director:    randomString(5),
+		Duration:    33,
+		PosterURL:   fmt.Sprintf("http://%s.com", randomString(5)),
+		Description: randomString(5),
+		GenreIDs:    []int64{genre.ID},
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	room, rec := testCreateRoom(t, tokenAdmin, RoomInput{Name: randomString(5)})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	rec = testSetRoomSeats(t, tokenAdmin, room.ID, []SeatInput{{Name: "A1"}})
+	require.Equal(t, http.StatusOK, rec.Code)
+	seats, rec := testListRoomSeats(t, room.ID)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	startAt := time.Now().AddDate(0, 0, 30).UTC().Truncate(time.Hour)
+	showtime, rec := testCreateShowtime(t, tokenAdmin, ShowtimeInput{
+		MovieID: movie.ID,
+		RoomID:  room.ID,
+		StartAt: startAt,
+		EndAt:   startAt.Add(movie.GetDuration()),
+		Price:   100_000,
+	})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	userInput := UserInput{
+		Email:    fmt.Sprintf("%s@gmail.com", randomString(5)),
+		Password: "12345678",
+	}
+	_, rec = testRegisterUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+	token, rec := testLoginUser(t, userInput)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// top up with gift card issued by admin
+	_, rec = testIssueGiftCard(t, tokenAdmin, GiftCardInput{Amount: 0})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	giftCard, rec := testIssueGiftCard(t, tokenAdmin, GiftCardInput{Amount: 30_000})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(30_000), giftCard.Balance)
+
+	wallet, rec := testTopUpWallet(t, token, WalletTopUpInput{Code: giftCard.Code})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(30_000), wallet.Balance)
+
+	_, rec = testTopUpWallet(t, token, WalletTopUpInput{Code: giftCard.Code})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	// split payment between wallet and payment gateway
+	cart, rec := testCreateCart(t, token, CartInput{ShowtimeID: showtime.ID, SeatID: seats[0].ID})
+	require.Equal(t, http.StatusOK, rec.Code)
+	reservation, rec := testCreateReservation(t, token, ReservationInput{CartIDs: []int64{cart.ID}})
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	// refused before the gateway is charged, no pending payment left to block the next try
+	_, rec = testPayReservationWith(t, token, reservation.ID, PayReservationInput{WalletAmount: 40_000})
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+
+	reservation, rec = testPayReservationWith(t, token, reservation.ID, PayReservationInput{WalletAmount: 20_000})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, ReservationPaid, reservation.Status)
+	require.Equal(t, int64(20_000), reservation.PaidWallet)
+	require.Equal(t, reservation.TotalPrice-20_000, reservation.PaidGateway)
+	require.NotEmpty(t, reservation.PaymentReference)
+
+	summary, rec := testGetWalletSummary(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, int64(10_000), summary.Wallet.Balance)
+	require.Len(t, summary.Entries.Items, 2)
+	require.Equal(t, JournalPayment, summary.Entries.Items[0].Kind)
+	require.Equal(t, int64(20_000), summary.Entries.Items[0].Amount)
+	require.Equal(t, JournalWalletTopUp, summary.Entries.Items[1].Kind)
+	require.Equal(t, int64(-30_000), summary.Entries.Items[1].Amount)
+
+	// purchase gift card
+	purchased, rec := testPurchaseGiftCard(t, token, GiftCardInput{Amount: 50_000})
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, GiftCardSourcePurchase, purchased.Source)
+
+	giftCards, rec := testListUserGiftCards(t, token)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, giftCards.Items, 1)
+	require.Equal(t, purchased.Code, giftCards.Items[0].Code)
+}
+
+func TestOfflinePaymentGatewayIdempotency(t *testing.T) {
+	gateway, err := NewPaymentGateway("offline")
+	require.NoError(t, err)
+
+	charge := PaymentCharge{UserID: 1, Amount: 10_000, IdempotencyKey: randomToken(16)}
+	first, err := gateway.Charge(context.Background(), charge)
+	require.NoError(t, err)
+	retry, err := gateway.Charge(context.Background(), charge)
+	require.NoError(t, err)
+	require.Equal(t, first.Reference, retry.Reference)
+
+	charge.IdempotencyKey = randomToken(16)
+	other, err := gateway.Charge(context.Background(), charge)
+	require.NoError(t, err)
+	require.NotEqual(t, first.Reference, other.Reference)
+}
+
+func testIssueGiftCard(t *testing.T, token string, input GiftCardInput) (*GiftCard, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/admin/gift-cards", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*GiftCard]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testPurchaseGiftCard(t *testing.T, token string, input GiftCardInput) (*GiftCard, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/gift-cards", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*GiftCard]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testListUserGiftCards(t *testing.T, token string) (*Paginate[GiftCard], *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/gift-cards", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Paginate[GiftCard]]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testTopUpWallet(t *testing.T, token string, input WalletTopUpInput) (*Wallet, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/user/wallet/top-up", bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Wallet]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testGetWalletSummary(t *testing.T, token string) (*WalletSummary, *httptest.ResponseRecorder) {
+	req := httptest.NewRequest(http.MethodGet, "/api/user/wallet", nil)
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*WalletSummary]
+	err := json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
+
+func testPayReservationWith(t *testing.T, token string, ID int64, input PayReservationInput) (*Reservation, *httptest.ResponseRecorder) {
+	p, err := json.Marshal(input)
+	require.NoError(t, err)
+
+	uri := fmt.Sprintf("/api/reservations/%d/pay", ID)
+	req := httptest.NewRequest(http.MethodPut, uri, bytes.NewReader(p))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(echo.HeaderAuthorization, token)
+	rec := httptest.NewRecorder()
+	testServer.ServeHTTP(rec, req)
+
+	var res Response[*Reservation]
+	err = json.Unmarshal(rec.Body.Bytes(), &res)
+	require.NoError(t, err)
+
+	return res.Data, rec
+}
@@ -8,21 +8,67 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func jwtMiddleware(config *Config) echo.MiddlewareFunc {
-	return echo_jwt.WithConfig(echo_jwt.Config{
-		SigningKey: []byte(config.JWTSecret),
+// jwtMiddleware parse the access token then check its session is not revoked,
+// so logout, role change and password reset take effect before the token expire
+func jwtMiddleware(config *Config, user *UserHandler) echo.MiddlewareFunc {
+	parse := echo_jwt.WithConfig(echo_jwt.Config{
+		KeyFunc: config.JWTKeys.Keyfunc,
 		NewClaimsFunc: func(c echo.Context) jwt.Claims {
 			return new(JwtCustomClaims)
 		},
 	})
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return parse(func(c echo.Context) error {
+			err := user.VerifySession(c)
+			if ErrIs(err, ErrInput) {
+				return c.JSON(http.StatusUnauthorized, Response[any]{Message: err.Error()})
+			}
+			if err != nil {
+				return NewAPIErr(c, err)
+			}
+			return next(c)
+		})
+	}
+}
+
+// optionalJwtMiddleware is jwtMiddleware when the request has bearer token, public route use it to personalize the response
+func optionalJwtMiddleware(config *Config, user *UserHandler) echo.MiddlewareFunc {
+	authenticate := jwtMiddleware(config, user)
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		withToken := authenticate(next)
+		return func(c echo.Context) error {
+			if c.Request().Header.Get(echo.HeaderAuthorization) == "" {
+				return next(c)
+			}
+			return withToken(c)
+		}
+	}
+}
+
+// adminMFAMiddleware require session started with two-factor on admin endpoints when RequireAdminMFA is set
+func adminMFAMiddleware(config *Config) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if config.RequireAdminMFA && !GetTokenMFA(c) {
+				return c.JSON(http.StatusForbidden, Response[any]{Message: "two-factor login is required for admin"})
+			}
+			return next(c)
+		}
+	}
 }
 
-func adminMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		_, _, role := GetTokenInfo(c)
-		if role == UserAdmin {
+// permissionMiddleware allow the route when the role of the token is granted the permission
+func permissionMiddleware(role *RoleHandler, permission Permission) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			err := role.Authorize(c, permission)
+			if ErrIs(err, ErrInput) {
+				return c.JSON(http.StatusUnauthorized, Response[any]{Message: err.Error()})
+			}
+			if err != nil {
+				return NewAPIErr(c, err)
+			}
 			return next(c)
 		}
-		return c.JSON(http.StatusUnauthorized, Response[any]{Message: "unauthorized"})
 	}
 }
@@ -3,10 +3,20 @@ package main
 import "github.com/labstack/echo/v4"
 
 func Route(e *echo.Echo, config *Config, handler *HandlerRegistry) {
+	e.GET("/.well-known/jwks.json", handler.User.JWKS)
+
 	public := e.Group("/api")
 	{
 		public.POST("/register", handler.User.Register)
 		public.POST("/login", handler.User.Login)
+		public.POST("/login/mfa", handler.User.LoginMFA)
+		public.POST("/refresh", handler.User.Refresh)
+		public.POST("/email/verify", handler.User.VerifyEmail)
+		public.POST("/password/forgot", handler.User.ForgotPassword)
+		public.POST("/password/reset", handler.User.ResetPassword)
+		public.GET("/oidc/providers", handler.OIDC.Providers)
+		public.GET("/oidc/:provider/authorize", handler.OIDC.Authorize)
+		public.GET("/oidc/:provider/callback", handler.OIDC.Callback)
 
 		public.POST("/genres/filter", handler.Movie.PaginationGenre)
 		public.GET("/genres", handler.Movie.PaginationGenre)
@@ -15,21 +25,53 @@ func Route(e *echo.Echo, config *Config, handler *HandlerRegistry) {
 		public.POST("/movies/filter", handler.Movie.Pagination)
 		public.GET("/movies", handler.Movie.Pagination)
 		public.GET("/movies/:id", handler.Movie.GetByID)
+		public.GET("/movies/:id/showtimes.ics", handler.Calendar.Movie)
 
+		public.GET("/showtimes/formats", handler.Showtime.ListFormatSurcharges)
 		public.GET("/showtimes/:id", handler.Showtime.GetByID)
 		public.GET("/showtimes/:id/seats", handler.Showtime.GetShowtimeSeatByID)
-		public.POST("/showtimes/filter", handler.Showtime.Pagination)
-		public.GET("/showtimes", handler.Showtime.Pagination)
+		public.POST("/showtimes/filter", handler.Showtime.Pagination, optionalJwtMiddleware(config, handler.User))
+		public.GET("/showtimes", handler.Showtime.Pagination, optionalJwtMiddleware(config, handler.User))
 
 		public.POST("/rooms/filter", handler.Room.Pagination)
 		public.GET("/rooms", handler.Room.Pagination)
 		public.GET("/rooms/:id", handler.Room.GetByID)
 		public.GET("/rooms/:id/seats", handler.Room.ListSeats)
+		public.GET("/rooms/:id/schedule", handler.Room.Schedule)
+
+		public.GET("/ticket-types", handler.TicketType.List)
+		public.GET("/ticket-types/:id", handler.TicketType.GetByID)
+
+		public.GET("/fees", handler.Fee.List)
+		public.GET("/fees/:id", handler.Fee.GetByID)
+
+		public.POST("/cinemas/filter", handler.Cinema.Pagination)
+		public.GET("/cinemas", handler.Cinema.Pagination)
+		public.GET("/cinemas/:id", handler.Cinema.GetByID)
+		public.GET("/cinemas/:id/showtimes.ics", handler.Calendar.Cinema)
 	}
 
-	loggedIn := e.Group("/api", jwtMiddleware(config))
+	loggedIn := e.Group("/api", jwtMiddleware(config, handler.User))
 	{
+		loggedIn.POST("/logout", handler.User.Logout)
+		loggedIn.POST("/logout/all", handler.User.LogoutAll)
 		loggedIn.GET("/user", handler.User.LoggedIn)
+		loggedIn.PUT("/user/password", handler.User.ChangePassword)
+		loggedIn.GET("/user/profile", handler.Profile.Get)
+		loggedIn.PUT("/user/profile", handler.Profile.Update)
+		loggedIn.GET("/user/profile/history", handler.Profile.History)
+		loggedIn.POST("/email/verify/resend", handler.User.ResendEmailVerification)
+		loggedIn.GET("/user/mfa", handler.MFA.Status)
+		loggedIn.POST("/user/mfa/enroll", handler.MFA.Enroll)
+		loggedIn.POST("/user/mfa/confirm", handler.MFA.Confirm)
+		loggedIn.POST("/user/mfa/backup-codes", handler.MFA.RegenerateBackupCodes)
+		loggedIn.DELETE("/user/mfa", handler.MFA.Disable)
+		loggedIn.GET("/user/identities", handler.OIDC.Identities)
+		loggedIn.GET("/user/points", handler.Point.Summary)
+		loggedIn.GET("/user/wallet", handler.Wallet.Summary)
+		loggedIn.POST("/user/wallet/top-up", handler.Wallet.TopUp)
+		loggedIn.GET("/user/gift-cards", handler.Wallet.UserGiftCards)
+		loggedIn.POST("/user/gift-cards", handler.Wallet.PurchaseGiftCard)
 
 		loggedIn.GET("/carts/:id", handler.Cart.UserGetByID)
 		loggedIn.GET("/carts", handler.Cart.UserGetPagination)
@@ -42,35 +84,123 @@ func Route(e *echo.Echo, config *Config, handler *HandlerRegistry) {
 		loggedIn.GET("/reservations", handler.Reservation.UserGetPagination)
 		loggedIn.POST("/reservations/filter", handler.Reservation.UserGetPagination)
 		loggedIn.POST("/reservations", handler.Reservation.UserCreate)
+		loggedIn.GET("/reservations/:id/receipt", handler.Reservation.Receipt)
+		loggedIn.GET("/reservations/:id/calendar.ics", handler.Calendar.Reservation)
+		loggedIn.GET("/reservations/:id/tickets", handler.Ticket.List)
+		loggedIn.GET("/reservations/:id/tickets.pdf", handler.Ticket.PDF)
+		loggedIn.GET("/reservations/:id/tickets/:item_id/qr", handler.Ticket.QRCode)
 		loggedIn.PUT("/reservations/:id/pay", handler.Reservation.Pay)
 		loggedIn.PUT("/reservations/:id/cancel", handler.Reservation.Cancel)
+		loggedIn.PUT("/reservations/:id/promo", handler.Reservation.ApplyPromoCode)
+		loggedIn.DELETE("/reservations/:id/promo", handler.Reservation.RemovePromoCode)
+		loggedIn.PUT("/reservations/:id/points", handler.Point.Redeem)
+		loggedIn.DELETE("/reservations/:id/points", handler.Point.Unredeem)
 		loggedIn.DELETE("/reservations/:id", handler.Reservation.UserDeleteByID)
 	}
 
-	admin := e.Group("/api/admin", jwtMiddleware(config), adminMiddleware)
-	{
-		admin.POST("/roles/filter", handler.User.PaginationRole)
-		admin.GET("/roles", handler.User.PaginationRole)
-		admin.GET("/roles/:id", handler.User.GetRoleByID)
-
-		admin.PUT("/user/:id", handler.User.ChangeRoleByID)
-
-		admin.POST("/genres", handler.Movie.CreateGenre)
-		admin.PUT("/genres/:id", handler.Movie.UpdateGenreByID)
-		admin.DELETE("/genres/:id", handler.Movie.DeleteGenreByID)
-
-		admin.POST("/movies", handler.Movie.Create)
-		admin.PUT("/movies/:id", handler.Movie.UpdateByID)
-		admin.DELETE("/movies/:id", handler.Movie.DeleteByID)
-
-		admin.POST("/rooms", handler.Room.Create)
-		admin.PUT("/rooms/:id", handler.Room.UpdateByID)
-		admin.DELETE("/rooms/:id", handler.Room.DeleteByID)
+	can := func(permission Permission) echo.MiddlewareFunc {
+		return permissionMiddleware(handler.Role, permission)
+	}
 
-		admin.POST("/rooms/:id/seats", handler.Room.SetSeats)
+	staff := e.Group("/api/staff", jwtMiddleware(config, handler.User))
+	{
+		staff.POST("/check-in", handler.Ticket.CheckIn, can(PermissionTicketCheckIn))
+		staff.POST("/check-in/sync", handler.Ticket.SyncCheckIn, can(PermissionTicketCheckIn))
+		staff.GET("/showtimes/:id/attendance", handler.Ticket.Attendance, can(PermissionTicketCheckIn))
+	}
 
-		admin.POST("/showtimes", handler.Showtime.Create)
-		admin.PUT("/showtimes/:id", handler.Showtime.UpdateByID)
-		admin.DELETE("/showtimes/:id", handler.Showtime.DeleteByID)
+	admin := e.Group("/api/admin", jwtMiddleware(config, handler.User), adminMFAMiddleware(config))
+	{
+		admin.POST("/roles/filter", handler.User.PaginationRole, can(PermissionRoleManage))
+		admin.GET("/roles", handler.User.PaginationRole, can(PermissionRoleManage))
+		admin.GET("/roles/:id", handler.User.GetRoleByID, can(PermissionRoleManage))
+		admin.POST("/roles", handler.Role.Create, can(PermissionRoleManage))
+		admin.PUT("/roles/:id/permissions", handler.Role.SetPermissions, can(PermissionRoleManage))
+		admin.DELETE("/roles/:id", handler.Role.DeleteByID, can(PermissionRoleManage))
+		admin.GET("/permissions", handler.Role.Permissions, can(PermissionRoleManage))
+
+		admin.POST("/users/filter", handler.User.Pagination, can(PermissionUserManage))
+		admin.GET("/users", handler.User.Pagination, can(PermissionUserManage))
+		admin.GET("/user/:id", handler.User.GetByID, can(PermissionUserManage))
+		admin.PUT("/user/:id", handler.User.ChangeRoleByID, can(PermissionRoleManage))
+		admin.DELETE("/user/:id", handler.User.DeleteByID, can(PermissionUserManage))
+		admin.PUT("/user/:id/disable", handler.User.DisableByID, can(PermissionUserManage))
+		admin.PUT("/user/:id/enable", handler.User.EnableByID, can(PermissionUserManage))
+		admin.POST("/user/:id/password/reset", handler.User.ForcePasswordReset, can(PermissionUserManage))
+		admin.POST("/user/:id/unlock", handler.LoginAttempt.Unlock, can(PermissionUserManage))
+		admin.POST("/user/:id/reservations/filter", handler.Reservation.AdminGetPagination, can(PermissionReservationRead))
+		admin.GET("/user/:id/reservations", handler.Reservation.AdminGetPagination, can(PermissionReservationRead))
+		admin.POST("/user/:id/carts/filter", handler.Cart.AdminGetPagination, can(PermissionReservationRead))
+		admin.GET("/user/:id/carts", handler.Cart.AdminGetPagination, can(PermissionReservationRead))
+
+		admin.POST("/login-attempts/filter", handler.LoginAttempt.Pagination, can(PermissionUserManage))
+		admin.GET("/login-attempts", handler.LoginAttempt.Pagination, can(PermissionUserManage))
+
+		admin.POST("/genres", handler.Movie.CreateGenre, can(PermissionMovieManage))
+		admin.PUT("/genres/:id", handler.Movie.UpdateGenreByID, can(PermissionMovieManage))
+		admin.DELETE("/genres/:id", handler.Movie.DeleteGenreByID, can(PermissionMovieManage))
+
+		admin.POST("/movies", handler.Movie.Create, can(PermissionMovieManage))
+		admin.PUT("/movies/:id", handler.Movie.UpdateByID, can(PermissionMovieManage))
+		admin.DELETE("/movies/:id", handler.Movie.DeleteByID, can(PermissionMovieManage))
+
+		admin.POST("/rooms", handler.Room.Create, can(PermissionCinemaManage))
+		admin.PUT("/rooms/:id", handler.Room.UpdateByID, can(PermissionCinemaManage))
+		admin.DELETE("/rooms/:id", handler.Room.DeleteByID, can(PermissionCinemaManage))
+
+		admin.POST("/rooms/:id/seats", handler.Room.SetSeats, can(PermissionCinemaManage))
+
+		admin.POST("/showtimes", handler.Showtime.Create, can(PermissionShowtimeManage))
+		admin.PUT("/showtimes/:id", handler.Showtime.UpdateByID, can(PermissionShowtimeManage))
+		admin.DELETE("/showtimes/:id", handler.Showtime.DeleteByID, can(PermissionShowtimeManage))
+		admin.PUT("/showtimes/formats/:format", handler.Showtime.SetFormatSurcharge, can(PermissionShowtimeManage))
+
+		admin.POST("/cinemas", handler.Cinema.Create, can(PermissionCinemaManage))
+		admin.PUT("/cinemas/:id", handler.Cinema.UpdateByID, can(PermissionCinemaManage))
+		admin.DELETE("/cinemas/:id", handler.Cinema.DeleteByID, can(PermissionCinemaManage))
+
+		admin.POST("/blackouts/filter", handler.Blackout.Pagination, can(PermissionCinemaManage))
+		admin.GET("/blackouts", handler.Blackout.Pagination, can(PermissionCinemaManage))
+		admin.GET("/blackouts/:id", handler.Blackout.GetByID, can(PermissionCinemaManage))
+		admin.POST("/blackouts", handler.Blackout.Create, can(PermissionCinemaManage))
+		admin.PUT("/blackouts/:id", handler.Blackout.UpdateByID, can(PermissionCinemaManage))
+		admin.DELETE("/blackouts/:id", handler.Blackout.DeleteByID, can(PermissionCinemaManage))
+
+		admin.POST("/ticket-types/filter", handler.TicketType.Filter, can(PermissionPricingManage))
+		admin.POST("/ticket-types", handler.TicketType.Create, can(PermissionPricingManage))
+		admin.PUT("/ticket-types/:id", handler.TicketType.UpdateByID, can(PermissionPricingManage))
+		admin.DELETE("/ticket-types/:id", handler.TicketType.DeleteByID, can(PermissionPricingManage))
+
+		admin.POST("/fees/filter", handler.Fee.Filter, can(PermissionPricingManage))
+		admin.POST("/fees", handler.Fee.Create, can(PermissionPricingManage))
+		admin.PUT("/fees/:id", handler.Fee.UpdateByID, can(PermissionPricingManage))
+		admin.DELETE("/fees/:id", handler.Fee.DeleteByID, can(PermissionPricingManage))
+
+		admin.POST("/pricing-rules/filter", handler.PricingRule.Pagination, can(PermissionPricingManage))
+		admin.GET("/pricing-rules", handler.PricingRule.Pagination, can(PermissionPricingManage))
+		admin.GET("/pricing-rules/:id", handler.PricingRule.GetByID, can(PermissionPricingManage))
+		admin.POST("/pricing-rules", handler.PricingRule.Create, can(PermissionPricingManage))
+		admin.PUT("/pricing-rules/:id", handler.PricingRule.UpdateByID, can(PermissionPricingManage))
+		admin.DELETE("/pricing-rules/:id", handler.PricingRule.DeleteByID, can(PermissionPricingManage))
+
+		admin.POST("/promo-codes/filter", handler.PromoCode.Pagination, can(PermissionPricingManage))
+		admin.GET("/promo-codes", handler.PromoCode.Pagination, can(PermissionPricingManage))
+		admin.GET("/promo-codes/:id", handler.PromoCode.GetByID, can(PermissionPricingManage))
+		admin.POST("/promo-codes", handler.PromoCode.Create, can(PermissionPricingManage))
+		admin.PUT("/promo-codes/:id", handler.PromoCode.UpdateByID, can(PermissionPricingManage))
+		admin.DELETE("/promo-codes/:id", handler.PromoCode.DeleteByID, can(PermissionPricingManage))
+
+		admin.POST("/gift-cards/filter", handler.Wallet.PaginationGiftCard, can(PermissionGiftCardManage))
+		admin.GET("/gift-cards", handler.Wallet.PaginationGiftCard, can(PermissionGiftCardManage))
+		admin.POST("/gift-cards", handler.Wallet.IssueGiftCard, can(PermissionGiftCardManage))
+
+		admin.GET("/reservations/:id/tickets.pdf", handler.Ticket.AdminPDF, can(PermissionReservationRead))
+
+		admin.POST("/reconciliations", handler.Reconciliation.Run, can(PermissionFinanceManage))
+		admin.POST("/reconciliations/filter", handler.Reconciliation.Pagination, can(PermissionFinanceManage))
+		admin.GET("/reconciliations", handler.Reconciliation.Pagination, can(PermissionFinanceManage))
+
+		admin.POST("/planner/propose", handler.Planner.Propose, can(PermissionShowtimeManage))
+		admin.POST("/planner/commit", handler.Planner.Commit, can(PermissionShowtimeManage))
 	}
 }
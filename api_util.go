@@ -31,3 +31,29 @@ func GetTokenInfo(c echo.Context) (id int64, email string, role string) {
 	}
 	return claims.ID, claims.Email, claims.Role
 }
+
+// GetTokenSession is the login session of the token and the token version of the user when issued
+func GetTokenSession(c echo.Context) (sessionID int64, tokenVersion int64) {
+	user, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return 0, 0
+	}
+	claims, ok := user.Claims.(*JwtCustomClaims)
+	if !ok {
+		return 0, 0
+	}
+	return claims.SessionID, claims.TokenVersion
+}
+
+// GetTokenMFA is true when the session of the token started with second factor
+func GetTokenMFA(c echo.Context) bool {
+	user, ok := c.Get("user").(*jwt.Token)
+	if !ok {
+		return false
+	}
+	claims, ok := user.Claims.(*JwtCustomClaims)
+	if !ok {
+		return false
+	}
+	return claims.MFA
+}
@@ -1,22 +1,65 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
+)
+
+const (
+	DefaultJWTSecret    = "secret"
+	DefaultTicketSecret = "ticket-secret"
 )
 
 type Config struct {
 	ServerHost string
 	ServerPort int
+	DevMode    bool // allow insecure defaults for local development
+
+	JWTSecret          string     // HS256 key, only used when there is no JWTKeyFiles
+	JWTKeyFiles        []string   // PEM private keys, the first sign new token, the rest only verify while rotating
+	JWTKeys            *JWTKeySet // loaded from JWTKeyFiles by Load
+	TicketSecret       string     // key to sign ticket token
+	AccessTokenMinutes int64      // lifetime of access token, refresh to get a new one
+	RefreshTokenDays   int64      // session expire when not refreshed within this many days
+	RequireAdminMFA    bool       // admin endpoints need session started with two-factor
+	TrustProxy         bool       // client ip is taken from X-Forwarded-For, only enable behind a reverse proxy
+
+	PermissionCacheSeconds int64 // how long permissions of a role are cached, change on other instance apply after this
 
-	JWTSecret string
+	OIDCProviders map[string]*OIDCProvider // identity providers by name from OIDC_PROVIDERS, see NewOIDCProviders
+
+	LoginMaxFailures    int64 // consecutive failed login of an account before it is locked
+	LoginIPMaxFailures  int64 // consecutive failed login from an ip before it is locked
+	LoginBackoffSeconds int64 // first lockout, doubled on every next failure
+	LoginLockoutMinutes int64 // longest lockout
 
 	PostgresHost     string
 	PostgresPort     int64
 	PostgresUser     string
 	PostgresPassword string
 	PostgresDB       string
+
+	PointEarnUnit       int64 // paid amount to earn 1 point
+	PointExpiryDays     int64 // points expire after this many days since earned
+	PointRedeemValue    int64 // discount amount of 1 redeemed point
+	PointFreeTicketCost int64 // points needed to redeem 1 free ticket
+
+	PaymentGateway string // name of payment gateway, see NewPaymentGateway
+
+	Mailer               string // name of mailer, see NewMailer
+	MailFrom             string
+	MailDir              string // directory of file mailer
+	SMTPAddr             string // host:port
+	SMTPUsername         string
+	SMTPPassword         string
+	PasswordResetMinutes int64 // lifetime of password reset token
+
+	RequireVerifiedEmail     bool  // unverified user cannot create reservation
+	EmailVerificationHours   int64 // lifetime of email verification token
+	EmailVerificationPerHour int64 // maximum verification mail sent to a user within an hour
 }
 
 func (c *Config) ServerAddr() string {
@@ -39,22 +82,88 @@ func NewConfig() *Config {
 		ServerHost: "localhost",
 		ServerPort: 8000,
 
-		JWTSecret: "secret",
+		JWTSecret:          DefaultJWTSecret,
+		TicketSecret:       DefaultTicketSecret,
+		AccessTokenMinutes: 15,
+		RefreshTokenDays:   30,
+
+		PermissionCacheSeconds: 60,
+
+		LoginMaxFailures:    5,
+		LoginIPMaxFailures:  20,
+		LoginBackoffSeconds: 30,
+		LoginLockoutMinutes: 15,
 
 		PostgresHost:     "localhost",
 		PostgresPort:     5432,
 		PostgresUser:     "root",
 		PostgresPassword: "root",
 		PostgresDB:       "movie_reservation_system",
+
+		PointEarnUnit:       1_000,
+		PointExpiryDays:     365,
+		PointRedeemValue:    10,
+		PointFreeTicketCost: 5_000,
+
+		PaymentGateway: "offline",
+
+		Mailer:               "log",
+		MailFrom:             "no-reply@movie-reservation.local",
+		MailDir:              "mail",
+		PasswordResetMinutes: 30,
+
+		RequireVerifiedEmail:     true,
+		EmailVerificationHours:   24,
+		EmailVerificationPerHour: 3,
 	}
 
 	if value, err := strconv.Atoi(os.Getenv("SERVER_PORT")); err == nil {
 		c.ServerPort = value
 	}
 
+	if value, err := strconv.ParseBool(os.Getenv("DEV_MODE")); err == nil {
+		c.DevMode = value
+	}
+
 	if value := os.Getenv("JWT_SECRET"); value != "" {
 		c.JWTSecret = value
 	}
+	for _, file := range strings.Split(os.Getenv("JWT_KEY_FILES"), ",") {
+		if file = strings.TrimSpace(file); file != "" {
+			c.JWTKeyFiles = append(c.JWTKeyFiles, file)
+		}
+	}
+	if value := os.Getenv("TICKET_SECRET"); value != "" {
+		c.TicketSecret = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("ACCESS_TOKEN_MINUTES")); err == nil && value > 0 {
+		c.AccessTokenMinutes = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("REFRESH_TOKEN_DAYS")); err == nil && value > 0 {
+		c.RefreshTokenDays = int64(value)
+	}
+	if value, err := strconv.ParseBool(os.Getenv("REQUIRE_ADMIN_MFA")); err == nil {
+		c.RequireAdminMFA = value
+	}
+	if value, err := strconv.ParseBool(os.Getenv("TRUST_PROXY")); err == nil {
+		c.TrustProxy = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("PERMISSION_CACHE_SECONDS")); err == nil && value >= 0 {
+		c.PermissionCacheSeconds = int64(value)
+	}
+
+	if value, err := strconv.Atoi(os.Getenv("LOGIN_MAX_FAILURES")); err == nil && value > 0 {
+		c.LoginMaxFailures = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("LOGIN_IP_MAX_FAILURES")); err == nil && value > 0 {
+		c.LoginIPMaxFailures = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("LOGIN_BACKOFF_SECONDS")); err == nil && value > 0 {
+		c.LoginBackoffSeconds = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("LOGIN_LOCKOUT_MINUTES")); err == nil && value > 0 {
+		c.LoginLockoutMinutes = int64(value)
+	}
 
 	if value := os.Getenv("POSTGRES_HOST"); value != "" {
 		c.PostgresHost = value
@@ -71,5 +180,80 @@ func NewConfig() *Config {
 	if value := os.Getenv("POSTGRES_DB"); value != "" {
 		c.PostgresDB = value
 	}
+
+	if value, err := strconv.Atoi(os.Getenv("POINT_EARN_UNIT")); err == nil && value > 0 {
+		c.PointEarnUnit = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("POINT_EXPIRY_DAYS")); err == nil && value > 0 {
+		c.PointExpiryDays = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("POINT_REDEEM_VALUE")); err == nil && value > 0 {
+		c.PointRedeemValue = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("POINT_FREE_TICKET_COST")); err == nil && value > 0 {
+		c.PointFreeTicketCost = int64(value)
+	}
+
+	if value := os.Getenv("PAYMENT_GATEWAY"); value != "" {
+		c.PaymentGateway = value
+	}
+
+	if value := os.Getenv("MAILER"); value != "" {
+		c.Mailer = value
+	}
+	if value := os.Getenv("MAIL_FROM"); value != "" {
+		c.MailFrom = value
+	}
+	if value := os.Getenv("MAIL_DIR"); value != "" {
+		c.MailDir = value
+	}
+	if value := os.Getenv("SMTP_ADDR"); value != "" {
+		c.SMTPAddr = value
+	}
+	if value := os.Getenv("SMTP_USERNAME"); value != "" {
+		c.SMTPUsername = value
+	}
+	if value := os.Getenv("SMTP_PASSWORD"); value != "" {
+		c.SMTPPassword = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("PASSWORD_RESET_MINUTES")); err == nil && value > 0 {
+		c.PasswordResetMinutes = int64(value)
+	}
+
+	if value, err := strconv.ParseBool(os.Getenv("REQUIRE_VERIFIED_EMAIL")); err == nil {
+		c.RequireVerifiedEmail = value
+	}
+	if value, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_HOURS")); err == nil && value > 0 {
+		c.EmailVerificationHours = int64(value)
+	}
+	if value, err := strconv.Atoi(os.Getenv("EMAIL_VERIFICATION_PER_HOUR")); err == nil && value > 0 {
+		c.EmailVerificationPerHour = int64(value)
+	}
+
+	c.JWTKeys, _ = NewJWTKeySet(c.JWTSecret)
+	c.OIDCProviders, _ = NewOIDCProviders()
 	return &c
 }
+
+// Load the signing keys and refuse insecure defaults outside dev mode
+func (c *Config) Load() error {
+	if len(c.JWTKeyFiles) == 0 && c.JWTSecret == DefaultJWTSecret && !c.DevMode {
+		return errors.New("refuse to start with default jwt secret, set JWT_KEY_FILES or JWT_SECRET, or DEV_MODE=true for local development")
+	}
+	// ticket token is public once printed, anyone knowing the key can forge ticket of any paid item
+	if c.TicketSecret == DefaultTicketSecret && !c.DevMode {
+		return errors.New("refuse to start with default ticket secret, set TICKET_SECRET, or DEV_MODE=true for local development")
+	}
+	keys, err := LoadJWTKeySet(c.JWTSecret, c.JWTKeyFiles)
+	if err != nil {
+		return err
+	}
+	c.JWTKeys = keys
+
+	providers, err := NewOIDCProviders()
+	if err != nil {
+		return err
+	}
+	c.OIDCProviders = providers
+	return nil
+}
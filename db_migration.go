@@ -10,7 +10,7 @@ import (
 //go:embed migration/*.sql
 var embedMigrations embed.FS
 
-var MIGRATE_VERSION int64 = 20241125035656
+var MIGRATE_VERSION int64 = 20241127200000
 
 func migrate(db *sql.DB) error {
 	goose.SetBaseFS(embedMigrations)
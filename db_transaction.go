@@ -21,15 +21,25 @@ type TransactionProvider struct {
 }
 
 func (t *TransactionProvider) Transact(ctx context.Context, fn func(service *ServiceRegistry) error) error {
-	return runInTx(ctx, t.db, func(tx pgx.Tx) error {
+	return t.transact(ctx, pgx.RepeatableRead, fn)
+}
+
+// TransactReadCommitted is for transaction that wait on row lock of a counter, after the wait it see the row
+// committed by the other transaction instead of failing with serialization error
+func (t *TransactionProvider) TransactReadCommitted(ctx context.Context, fn func(service *ServiceRegistry) error) error {
+	return t.transact(ctx, pgx.ReadCommitted, fn)
+}
+
+func (t *TransactionProvider) transact(ctx context.Context, isoLevel pgx.TxIsoLevel, fn func(service *ServiceRegistry) error) error {
+	return runInTx(ctx, t.db, isoLevel, func(tx pgx.Tx) error {
 		repository := NewRepositoryRegistry(tx)
 		service := NewService(t.config, repository)
 		return fn(service)
 	})
 }
 
-func runInTx(ctx context.Context, db *pgxpool.Pool, fn func(tx pgx.Tx) error) error {
-	tx, err := db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead})
+func runInTx(ctx context.Context, db *pgxpool.Pool, isoLevel pgx.TxIsoLevel, fn func(tx pgx.Tx) error) error {
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{IsoLevel: isoLevel})
 	if err != nil {
 		return err
 	}
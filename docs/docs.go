@@ -681,7 +681,7 @@ const docTemplate = `{
         },
         "/api/admin/showtimes": {
             "post": {
-                "description": "admin create showtime",
+                "description": "admin create showtime, showtime in the same room cannot overlap but can start when the previous one end",
                 "consumes": [
                     "application/json"
                 ],
@@ -734,7 +734,7 @@ const docTemplate = `{
         },
         "/api/admin/showtimes/{id}": {
             "put": {
-                "description": "admin update showtime by id",
+                "description": "admin update showtime by id, showtime in the same room cannot overlap but can start when the previous one end",
                 "consumes": [
                     "application/json"
                 ],
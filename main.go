@@ -9,6 +9,16 @@ func main() {
 	ctx := context.Background()
 
 	config := NewConfig()
+	if err := config.Load(); err != nil {
+		log.Fatal(err)
+	}
+
+	if _, err := NewPaymentGateway(config.PaymentGateway); err != nil {
+		log.Fatal(err)
+	}
+	if _, err := NewMailer(config); err != nil {
+		log.Fatal(err)
+	}
 
 	pool, err := NewDBPool(ctx, config.PostgresDSN())
 	if err != nil {
@@ -17,6 +27,8 @@ func main() {
 	defer pool.Close()
 
 	trxProvider := NewTransactionProvider(config, pool)
+	go RunDailyReconciliation(ctx, trxProvider)
+
 	handler := NewHandler(config, trxProvider)
 
 	err = RunServer(config, handler)
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.cinemas (
+	id bigserial NOT NULL,
+	"name" varchar NOT NULL,
+	address text DEFAULT '' NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT cinemas_pk PRIMARY KEY (id),
+	CONSTRAINT cinemas_unique UNIQUE ("name")
+);
+
+ALTER TABLE public.rooms ADD COLUMN IF NOT EXISTS cinema_id bigint NULL;
+ALTER TABLE public.rooms ADD CONSTRAINT rooms_cinemas_fk FOREIGN KEY (cinema_id) REFERENCES public.cinemas(id) ON DELETE SET NULL ON UPDATE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.rooms DROP CONSTRAINT IF EXISTS rooms_cinemas_fk;
+ALTER TABLE public.rooms DROP COLUMN IF EXISTS cinema_id;
+
+DROP TABLE IF EXISTS public.cinemas;
+-- +goose StatementEnd
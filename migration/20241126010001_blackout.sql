@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.blackouts (
+	id bigserial NOT NULL,
+	room_id bigint NULL,
+	cinema_id bigint NULL,
+	start_at timestamptz NOT NULL,
+	end_at timestamptz NOT NULL,
+	reason varchar NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT blackouts_pk PRIMARY KEY (id),
+	CONSTRAINT blackouts_rooms_fk FOREIGN KEY (room_id) REFERENCES public.rooms(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT blackouts_cinemas_fk FOREIGN KEY (cinema_id) REFERENCES public.cinemas(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT blackouts_target_check CHECK ((room_id IS NULL) != (cinema_id IS NULL)),
+	CONSTRAINT blackouts_time_check CHECK (start_at < end_at)
+);
+CREATE INDEX blackouts_room_idx ON public.blackouts (room_id, start_at);
+CREATE INDEX blackouts_cinema_idx ON public.blackouts (cinema_id, start_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.blackouts;
+-- +goose StatementEnd
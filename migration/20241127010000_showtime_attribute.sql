@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE public.showtime_format AS enum ('2D', '3D', 'IMAX', '4DX');
+
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS original_language varchar(3) DEFAULT '' NOT NULL;
+
+ALTER TABLE public.showtimes
+	ADD COLUMN IF NOT EXISTS format public.showtime_format DEFAULT '2D' NOT NULL,
+	ADD COLUMN IF NOT EXISTS audio_language varchar(3) DEFAULT '' NOT NULL,
+	ADD COLUMN IF NOT EXISTS subtitle_language varchar(3) DEFAULT '' NOT NULL,
+	ADD COLUMN IF NOT EXISTS open_captions boolean DEFAULT false NOT NULL,
+	ADD COLUMN IF NOT EXISTS audio_description boolean DEFAULT false NOT NULL;
+
+CREATE TABLE IF NOT EXISTS public.format_surcharges (
+	format public.showtime_format NOT NULL,
+	amount int DEFAULT 0 NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT format_surcharges_pk PRIMARY KEY (format),
+	CONSTRAINT format_surcharges_amount_check CHECK (amount >= 0)
+);
+
+INSERT INTO public.format_surcharges (format, amount)
+VALUES ('2D', 0), ('3D', 0), ('IMAX', 0), ('4DX', 0)
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.format_surcharges;
+
+ALTER TABLE public.showtimes
+	DROP COLUMN IF EXISTS format,
+	DROP COLUMN IF EXISTS audio_language,
+	DROP COLUMN IF EXISTS subtitle_language,
+	DROP COLUMN IF EXISTS open_captions,
+	DROP COLUMN IF EXISTS audio_description;
+
+ALTER TABLE public.movies DROP COLUMN IF EXISTS original_language;
+
+DROP TYPE IF EXISTS public.showtime_format;
+-- +goose StatementEnd
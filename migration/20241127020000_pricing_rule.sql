@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE public.pricing_rule_kind AS enum ('matinee', 'weekend', 'holiday', 'early_bird', 'occupancy');
+
+CREATE TABLE IF NOT EXISTS public.pricing_rules (
+	id bigserial NOT NULL,
+	"name" varchar NOT NULL,
+	kind public.pricing_rule_kind NOT NULL,
+	"percent" int DEFAULT 0 NOT NULL,
+	amount int DEFAULT 0 NOT NULL,
+	priority int DEFAULT 0 NOT NULL,
+	is_active boolean DEFAULT true NOT NULL,
+	timezone varchar DEFAULT 'UTC' NOT NULL,
+	weekdays int[] DEFAULT '{}' NOT NULL,
+	start_clock varchar(5) DEFAULT '' NOT NULL,
+	end_clock varchar(5) DEFAULT '' NOT NULL,
+	dates varchar(10)[] DEFAULT '{}' NOT NULL,
+	min_days_before int DEFAULT 0 NOT NULL,
+	min_occupancy int DEFAULT 0 NOT NULL,
+	max_occupancy int DEFAULT 100 NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT pricing_rules_pk PRIMARY KEY (id),
+	CONSTRAINT pricing_rules_unique UNIQUE ("name"),
+	CONSTRAINT pricing_rules_occupancy_check CHECK (0 <= min_occupancy AND min_occupancy <= max_occupancy AND max_occupancy <= 100)
+);
+
+ALTER TABLE public.reservation_items ADD COLUMN IF NOT EXISTS pricing jsonb NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.reservation_items DROP COLUMN IF EXISTS pricing;
+
+DROP TABLE IF EXISTS public.pricing_rules;
+
+DROP TYPE IF EXISTS public.pricing_rule_kind;
+-- +goose StatementEnd
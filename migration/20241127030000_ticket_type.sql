@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.ticket_types (
+	id bigserial NOT NULL,
+	code varchar NOT NULL,
+	"name" varchar NOT NULL,
+	"percent" int DEFAULT 0 NOT NULL,
+	amount int DEFAULT 0 NOT NULL,
+	require_ticket_type_id bigint NULL,
+	is_default boolean DEFAULT false NOT NULL,
+	is_active boolean DEFAULT true NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT ticket_types_pk PRIMARY KEY (id),
+	CONSTRAINT ticket_types_unique UNIQUE (code),
+	CONSTRAINT ticket_types_percent_check CHECK ("percent" >= -100),
+	CONSTRAINT ticket_types_require_fk FOREIGN KEY (require_ticket_type_id) REFERENCES public.ticket_types(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+-- only one default ticket type
+CREATE UNIQUE INDEX ticket_types_default_idx ON public.ticket_types (is_default) WHERE is_default;
+
+-- seed
+INSERT INTO public.ticket_types (code, "name", "percent", is_default) VALUES ('adult', 'Adult', 0, true);
+INSERT INTO public.ticket_types (code, "name", "percent", require_ticket_type_id)
+	SELECT 'child', 'Child', -30, id FROM public.ticket_types WHERE code = 'adult';
+INSERT INTO public.ticket_types (code, "name", "percent") VALUES ('senior', 'Senior', -25);
+INSERT INTO public.ticket_types (code, "name", "percent") VALUES ('student', 'Student', -20);
+INSERT INTO public.ticket_types (code, "name", "percent") VALUES ('member', 'Member', -10);
+
+ALTER TABLE public.carts ADD COLUMN IF NOT EXISTS ticket_type_id bigint NULL;
+ALTER TABLE public.carts ADD CONSTRAINT carts_ticket_types_fk FOREIGN KEY (ticket_type_id) REFERENCES public.ticket_types(id) ON DELETE SET NULL ON UPDATE CASCADE;
+
+ALTER TABLE public.reservation_items ADD COLUMN IF NOT EXISTS ticket_type_id bigint NULL;
+ALTER TABLE public.reservation_items ADD CONSTRAINT reservation_items_ticket_types_fk FOREIGN KEY (ticket_type_id) REFERENCES public.ticket_types(id) ON DELETE SET NULL ON UPDATE CASCADE;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.reservation_items DROP CONSTRAINT IF EXISTS reservation_items_ticket_types_fk;
+ALTER TABLE public.reservation_items DROP COLUMN IF EXISTS ticket_type_id;
+
+ALTER TABLE public.carts DROP CONSTRAINT IF EXISTS carts_ticket_types_fk;
+ALTER TABLE public.carts DROP COLUMN IF EXISTS ticket_type_id;
+
+DROP TABLE IF EXISTS public.ticket_types;
+-- +goose StatementEnd
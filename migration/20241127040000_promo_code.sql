@@ -0,0 +1,67 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.seats ADD COLUMN IF NOT EXISTS category varchar DEFAULT 'standard' NOT NULL;
+
+CREATE TYPE public.promo_discount_type AS enum ('percent', 'amount');
+
+CREATE TABLE IF NOT EXISTS public.promo_codes (
+	id bigserial NOT NULL,
+	code varchar NOT NULL,
+	description varchar DEFAULT '' NOT NULL,
+	discount_type public.promo_discount_type NOT NULL,
+	discount_value int NOT NULL,
+	max_discount int DEFAULT 0 NOT NULL,
+	min_total_price int DEFAULT 0 NOT NULL,
+	start_at timestamptz NULL,
+	end_at timestamptz NULL,
+	usage_limit int DEFAULT 0 NOT NULL,
+	per_user_limit int DEFAULT 0 NOT NULL,
+	used_count int DEFAULT 0 NOT NULL,
+	movie_ids bigint[] DEFAULT '{}' NOT NULL,
+	genre_ids bigint[] DEFAULT '{}' NOT NULL,
+	showtime_ids bigint[] DEFAULT '{}' NOT NULL,
+	seat_categories varchar[] DEFAULT '{}' NOT NULL,
+	is_active boolean DEFAULT true NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT promo_codes_pk PRIMARY KEY (id),
+	CONSTRAINT promo_codes_unique UNIQUE (code),
+	CONSTRAINT promo_codes_discount_check CHECK (discount_value > 0),
+	CONSTRAINT promo_codes_usage_check CHECK (usage_limit = 0 OR used_count <= usage_limit)
+);
+
+CREATE TABLE IF NOT EXISTS public.promo_code_usages (
+	id bigserial NOT NULL,
+	promo_code_id bigint NOT NULL,
+	user_id bigint NOT NULL,
+	reservation_id bigint NOT NULL,
+	discount int DEFAULT 0 NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT promo_code_usages_pk PRIMARY KEY (id),
+	CONSTRAINT promo_code_usages_unique UNIQUE (reservation_id),
+	CONSTRAINT promo_code_usages_promo_codes_fk FOREIGN KEY (promo_code_id) REFERENCES public.promo_codes(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT promo_code_usages_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT promo_code_usages_reservations_fk FOREIGN KEY (reservation_id) REFERENCES public.reservations(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX promo_code_usages_user_idx ON public.promo_code_usages (promo_code_id, user_id);
+
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS subtotal_price int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS discount int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS promo_code_id bigint NULL;
+ALTER TABLE public.reservations ADD CONSTRAINT reservations_promo_codes_fk FOREIGN KEY (promo_code_id) REFERENCES public.promo_codes(id) ON DELETE SET NULL ON UPDATE CASCADE;
+UPDATE public.reservations SET subtotal_price = total_price;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.reservations DROP CONSTRAINT IF EXISTS reservations_promo_codes_fk;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS promo_code_id;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS discount;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS subtotal_price;
+
+DROP TABLE IF EXISTS public.promo_code_usages;
+DROP TABLE IF EXISTS public.promo_codes;
+DROP TYPE IF EXISTS public.promo_discount_type;
+
+ALTER TABLE public.seats DROP COLUMN IF EXISTS category;
+-- +goose StatementEnd
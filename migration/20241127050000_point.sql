@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE public.point_transaction_kind AS enum ('earn', 'redeem', 'reverse', 'expire');
+
+-- earn transaction is a lot, redeem consume the oldest lots first
+CREATE TABLE IF NOT EXISTS public.point_transactions (
+	id bigserial NOT NULL,
+	user_id bigint NOT NULL,
+	reservation_id bigint NULL,
+	kind public.point_transaction_kind NOT NULL,
+	points int NOT NULL,
+	remaining int DEFAULT 0 NOT NULL,
+	expires_at timestamptz NULL,
+	description varchar DEFAULT '' NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT point_transactions_pk PRIMARY KEY (id),
+	CONSTRAINT point_transactions_remaining_check CHECK (remaining >= 0 OR kind = 'earn'), -- negative is owed, earn reversed after spent
+	CONSTRAINT point_transactions_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT point_transactions_reservations_fk FOREIGN KEY (reservation_id) REFERENCES public.reservations(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+CREATE INDEX point_transactions_user_idx ON public.point_transactions (user_id, kind);
+
+-- which lots are consumed by a redeem transaction, or moved to repay an earn lot owing points
+CREATE TABLE IF NOT EXISTS public.point_allocations (
+	id bigserial NOT NULL,
+	transaction_id bigint NOT NULL,
+	lot_id bigint NOT NULL,
+	points int NOT NULL,
+	CONSTRAINT point_allocations_pk PRIMARY KEY (id),
+	CONSTRAINT point_allocations_transactions_fk FOREIGN KEY (transaction_id) REFERENCES public.point_transactions(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT point_allocations_lots_fk FOREIGN KEY (lot_id) REFERENCES public.point_transactions(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS points_redeemed int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS points_discount int DEFAULT 0 NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS points_discount;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS points_redeemed;
+
+DROP TABLE IF EXISTS public.point_allocations;
+DROP TABLE IF EXISTS public.point_transactions;
+DROP TYPE IF EXISTS public.point_transaction_kind;
+-- +goose StatementEnd
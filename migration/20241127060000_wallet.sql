@@ -0,0 +1,88 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE public.ledger_account_type AS enum ('asset', 'liability', 'equity', 'revenue', 'expense');
+
+CREATE TABLE IF NOT EXISTS public.ledger_accounts (
+	id bigserial NOT NULL,
+	code varchar NOT NULL,
+	"name" varchar NOT NULL,
+	"type" public.ledger_account_type NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT ledger_accounts_pk PRIMARY KEY (id),
+	CONSTRAINT ledger_accounts_unique UNIQUE (code)
+);
+
+CREATE TABLE IF NOT EXISTS public.gift_cards (
+	id bigserial NOT NULL,
+	code varchar NOT NULL,
+	initial_amount int NOT NULL,
+	balance int NOT NULL,
+	"source" varchar NOT NULL,
+	purchaser_id bigint NULL,
+	redeemed_by_id bigint NULL,
+	redeemed_at timestamptz NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT gift_cards_pk PRIMARY KEY (id),
+	CONSTRAINT gift_cards_unique UNIQUE (code),
+	CONSTRAINT gift_cards_balance_check CHECK (0 <= balance AND balance <= initial_amount),
+	CONSTRAINT gift_cards_purchaser_fk FOREIGN KEY (purchaser_id) REFERENCES public.users(id) ON DELETE SET NULL ON UPDATE CASCADE,
+	CONSTRAINT gift_cards_redeemed_by_fk FOREIGN KEY (redeemed_by_id) REFERENCES public.users(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+-- every journal is balanced, amount is positive for debit and negative for credit
+CREATE TABLE IF NOT EXISTS public.ledger_journals (
+	id bigserial NOT NULL,
+	kind varchar NOT NULL,
+	description varchar DEFAULT '' NOT NULL,
+	reference varchar DEFAULT '' NOT NULL,
+	reservation_id bigint NULL,
+	gift_card_id bigint NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT ledger_journals_pk PRIMARY KEY (id),
+	CONSTRAINT ledger_journals_reservations_fk FOREIGN KEY (reservation_id) REFERENCES public.reservations(id) ON DELETE SET NULL ON UPDATE CASCADE,
+	CONSTRAINT ledger_journals_gift_cards_fk FOREIGN KEY (gift_card_id) REFERENCES public.gift_cards(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS public.ledger_entries (
+	id bigserial NOT NULL,
+	journal_id bigint NOT NULL,
+	account_id bigint NOT NULL,
+	amount bigint NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT ledger_entries_pk PRIMARY KEY (id),
+	CONSTRAINT ledger_entries_amount_check CHECK (amount <> 0),
+	CONSTRAINT ledger_entries_journals_fk FOREIGN KEY (journal_id) REFERENCES public.ledger_journals(id) ON DELETE RESTRICT ON UPDATE CASCADE,
+	CONSTRAINT ledger_entries_accounts_fk FOREIGN KEY (account_id) REFERENCES public.ledger_accounts(id) ON DELETE RESTRICT ON UPDATE CASCADE
+);
+CREATE INDEX ledger_entries_account_idx ON public.ledger_entries (account_id);
+CREATE INDEX ledger_entries_journal_idx ON public.ledger_entries (journal_id);
+
+CREATE TABLE IF NOT EXISTS public.wallets (
+	user_id bigint NOT NULL,
+	balance int DEFAULT 0 NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT wallets_pk PRIMARY KEY (user_id),
+	CONSTRAINT wallets_balance_check CHECK (balance >= 0),
+	CONSTRAINT wallets_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS paid_wallet int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS paid_gateway int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS payment_reference varchar DEFAULT '' NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS payment_reference;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS paid_gateway;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS paid_wallet;
+
+DROP TABLE IF EXISTS public.wallets;
+DROP TABLE IF EXISTS public.ledger_entries;
+DROP TABLE IF EXISTS public.ledger_journals;
+DROP TABLE IF EXISTS public.gift_cards;
+DROP TABLE IF EXISTS public.ledger_accounts;
+DROP TYPE IF EXISTS public.ledger_account_type;
+-- +goose StatementEnd
@@ -0,0 +1,51 @@
+-- +goose Up
+-- +goose StatementBegin
+
+-- journals keep the reservation and gift card id as audit record even after they are deleted
+ALTER TABLE public.ledger_journals DROP CONSTRAINT IF EXISTS ledger_journals_reservations_fk;
+ALTER TABLE public.ledger_journals DROP CONSTRAINT IF EXISTS ledger_journals_gift_cards_fk;
+CREATE INDEX IF NOT EXISTS ledger_journals_reservation_idx ON public.ledger_journals (reservation_id);
+
+CREATE OR REPLACE FUNCTION public.ledger_append_only() RETURNS trigger AS $$
+BEGIN
+	RAISE EXCEPTION 'ledger is append only, % on % is not allowed', TG_OP, TG_TABLE_NAME;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER ledger_journals_append_only
+	BEFORE UPDATE OR DELETE ON public.ledger_journals
+	FOR EACH ROW EXECUTE FUNCTION public.ledger_append_only();
+
+CREATE TRIGGER ledger_entries_append_only
+	BEFORE UPDATE OR DELETE ON public.ledger_entries
+	FOR EACH ROW EXECUTE FUNCTION public.ledger_append_only();
+
+CREATE TABLE IF NOT EXISTS public.reconciliation_issues (
+	id bigserial NOT NULL,
+	reservation_id bigint NOT NULL,
+	status public.reservation_status NOT NULL,
+	issue varchar NOT NULL,
+	total_price int NOT NULL,
+	sold bigint NOT NULL,
+	collected bigint NOT NULL,
+	refunded bigint NOT NULL,
+	detected_on date NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT reconciliation_issues_pk PRIMARY KEY (id),
+	CONSTRAINT reconciliation_issues_unique UNIQUE (reservation_id, detected_on, issue),
+	CONSTRAINT reconciliation_issues_reservations_fk FOREIGN KEY (reservation_id) REFERENCES public.reservations(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.reconciliation_issues;
+
+DROP TRIGGER IF EXISTS ledger_entries_append_only ON public.ledger_entries;
+DROP TRIGGER IF EXISTS ledger_journals_append_only ON public.ledger_journals;
+DROP FUNCTION IF EXISTS public.ledger_append_only();
+
+DROP INDEX IF EXISTS public.ledger_journals_reservation_idx;
+ALTER TABLE public.ledger_journals ADD CONSTRAINT ledger_journals_reservations_fk FOREIGN KEY (reservation_id) REFERENCES public.reservations(id) ON DELETE SET NULL ON UPDATE CASCADE NOT VALID;
+ALTER TABLE public.ledger_journals ADD CONSTRAINT ledger_journals_gift_cards_fk FOREIGN KEY (gift_card_id) REFERENCES public.gift_cards(id) ON DELETE SET NULL ON UPDATE CASCADE NOT VALID;
+-- +goose StatementEnd
@@ -0,0 +1,62 @@
+-- +goose Up
+-- +goose StatementBegin
+-- tax rate in basis points, e.g. 1100 is 11%
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS tax_rate int DEFAULT 0 NOT NULL;
+ALTER TABLE public.cinemas ADD CONSTRAINT cinemas_tax_rate_check CHECK (tax_rate >= 0 AND tax_rate <= 10000);
+
+CREATE TYPE public.fee_kind AS ENUM ('per_ticket', 'per_order');
+
+CREATE TABLE IF NOT EXISTS public.fees (
+	id bigserial NOT NULL,
+	"name" varchar NOT NULL,
+	kind public.fee_kind NOT NULL,
+	amount int NOT NULL,
+	cinema_id bigint NULL, -- null apply to every cinema
+	is_active boolean DEFAULT true NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT fees_pk PRIMARY KEY (id),
+	CONSTRAINT fees_amount_check CHECK (amount > 0),
+	CONSTRAINT fees_cinemas_fk FOREIGN KEY (cinema_id) REFERENCES public.cinemas(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- receipt number is counted for each cinema without gap, series 0 for reservation without cinema
+CREATE TABLE IF NOT EXISTS public.receipt_counters (
+	series bigint NOT NULL,
+	"last" bigint DEFAULT 0 NOT NULL,
+	CONSTRAINT receipt_counters_pk PRIMARY KEY (series)
+);
+
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS cinema_id bigint NULL;
+ALTER TABLE public.reservations ADD CONSTRAINT reservations_cinemas_fk FOREIGN KEY (cinema_id) REFERENCES public.cinemas(id) ON DELETE SET NULL ON UPDATE CASCADE;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS fees int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS fee_items jsonb DEFAULT '[]' NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS tax_rate int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS tax int DEFAULT 0 NOT NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS receipt_series bigint NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS receipt_number bigint NULL;
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS receipt_issued_at timestamptz NULL;
+ALTER TABLE public.reservations ADD CONSTRAINT reservations_receipt_number_unique UNIQUE (receipt_series, receipt_number);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.reservations DROP CONSTRAINT IF EXISTS reservations_receipt_number_unique;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS receipt_issued_at;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS receipt_number;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS receipt_series;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS tax;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS tax_rate;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS fee_items;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS fees;
+ALTER TABLE public.reservations DROP CONSTRAINT IF EXISTS reservations_cinemas_fk;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS cinema_id;
+
+DROP TABLE IF EXISTS public.receipt_counters;
+
+DROP TABLE IF EXISTS public.fees;
+DROP TYPE IF EXISTS public.fee_kind;
+
+ALTER TABLE public.cinemas DROP CONSTRAINT IF EXISTS cinemas_tax_rate_check;
+ALTER TABLE public.cinemas DROP COLUMN IF EXISTS tax_rate;
+-- +goose StatementEnd
@@ -0,0 +1,95 @@
+-- +goose Up
+-- +goose StatementBegin
+-- amounts are in the minor unit of the currency, widen them so sums do not overflow
+ALTER TABLE public.showtimes ALTER COLUMN price TYPE bigint;
+ALTER TABLE public.seats ALTER COLUMN additional_price TYPE bigint;
+ALTER TABLE public.fees ALTER COLUMN amount TYPE bigint;
+ALTER TABLE public.reservation_items ALTER COLUMN total_price TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN total_price TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN subtotal_price TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN discount TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN points_discount TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN fees TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN tax TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN paid_wallet TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN paid_gateway TYPE bigint;
+ALTER TABLE public.reservations ALTER COLUMN points_redeemed TYPE bigint;
+ALTER TABLE public.pricing_rules ALTER COLUMN amount TYPE bigint;
+ALTER TABLE public.ticket_types ALTER COLUMN amount TYPE bigint;
+ALTER TABLE public.promo_codes ALTER COLUMN discount_value TYPE bigint;
+ALTER TABLE public.promo_codes ALTER COLUMN max_discount TYPE bigint;
+ALTER TABLE public.promo_codes ALTER COLUMN min_total_price TYPE bigint;
+ALTER TABLE public.promo_code_usages ALTER COLUMN discount TYPE bigint;
+ALTER TABLE public.gift_cards ALTER COLUMN initial_amount TYPE bigint;
+ALTER TABLE public.gift_cards ALTER COLUMN balance TYPE bigint;
+ALTER TABLE public.wallets ALTER COLUMN balance TYPE bigint;
+ALTER TABLE public.reconciliation_issues ALTER COLUMN total_price TYPE bigint;
+ALTER TABLE public.format_surcharges ALTER COLUMN amount TYPE bigint;
+
+-- ISO 4217 currency code
+ALTER TABLE public.cinemas ADD COLUMN IF NOT EXISTS currency char(3) DEFAULT 'IDR' NOT NULL;
+ALTER TABLE public.cinemas ADD CONSTRAINT cinemas_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE public.fees ADD COLUMN IF NOT EXISTS currency char(3) DEFAULT 'IDR' NOT NULL;
+ALTER TABLE public.fees ADD CONSTRAINT fees_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE public.reservations ADD COLUMN IF NOT EXISTS currency char(3) DEFAULT 'IDR' NOT NULL;
+ALTER TABLE public.reservations ADD CONSTRAINT reservations_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+
+-- catalog shared by every cinema, fixed amount only apply to cinema of the same currency
+ALTER TABLE public.pricing_rules ADD COLUMN IF NOT EXISTS currency char(3) DEFAULT 'IDR' NOT NULL;
+ALTER TABLE public.pricing_rules ADD CONSTRAINT pricing_rules_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE public.ticket_types ADD COLUMN IF NOT EXISTS currency char(3) DEFAULT 'IDR' NOT NULL;
+ALTER TABLE public.ticket_types ADD CONSTRAINT ticket_types_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE public.promo_codes ADD COLUMN IF NOT EXISTS currency char(3) DEFAULT 'IDR' NOT NULL;
+ALTER TABLE public.promo_codes ADD CONSTRAINT promo_codes_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE public.format_surcharges ADD COLUMN IF NOT EXISTS currency char(3) DEFAULT 'IDR' NOT NULL;
+ALTER TABLE public.format_surcharges ADD CONSTRAINT format_surcharges_currency_check CHECK (currency ~ '^[A-Z]{3}$');
+ALTER TABLE public.format_surcharges DROP CONSTRAINT format_surcharges_pk;
+ALTER TABLE public.format_surcharges ADD CONSTRAINT format_surcharges_pk PRIMARY KEY (format, currency);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DELETE FROM public.format_surcharges WHERE currency <> 'IDR';
+ALTER TABLE public.format_surcharges DROP CONSTRAINT format_surcharges_pk;
+ALTER TABLE public.format_surcharges ADD CONSTRAINT format_surcharges_pk PRIMARY KEY (format);
+ALTER TABLE public.format_surcharges DROP CONSTRAINT IF EXISTS format_surcharges_currency_check;
+ALTER TABLE public.format_surcharges DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.promo_codes DROP CONSTRAINT IF EXISTS promo_codes_currency_check;
+ALTER TABLE public.promo_codes DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.ticket_types DROP CONSTRAINT IF EXISTS ticket_types_currency_check;
+ALTER TABLE public.ticket_types DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.pricing_rules DROP CONSTRAINT IF EXISTS pricing_rules_currency_check;
+ALTER TABLE public.pricing_rules DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE public.reservations DROP CONSTRAINT IF EXISTS reservations_currency_check;
+ALTER TABLE public.reservations DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.fees DROP CONSTRAINT IF EXISTS fees_currency_check;
+ALTER TABLE public.fees DROP COLUMN IF EXISTS currency;
+ALTER TABLE public.cinemas DROP CONSTRAINT IF EXISTS cinemas_currency_check;
+ALTER TABLE public.cinemas DROP COLUMN IF EXISTS currency;
+
+ALTER TABLE public.format_surcharges ALTER COLUMN amount TYPE int;
+ALTER TABLE public.reconciliation_issues ALTER COLUMN total_price TYPE int;
+ALTER TABLE public.wallets ALTER COLUMN balance TYPE int;
+ALTER TABLE public.gift_cards ALTER COLUMN balance TYPE int;
+ALTER TABLE public.gift_cards ALTER COLUMN initial_amount TYPE int;
+ALTER TABLE public.promo_code_usages ALTER COLUMN discount TYPE int;
+ALTER TABLE public.promo_codes ALTER COLUMN min_total_price TYPE int;
+ALTER TABLE public.promo_codes ALTER COLUMN max_discount TYPE int;
+ALTER TABLE public.promo_codes ALTER COLUMN discount_value TYPE int;
+ALTER TABLE public.ticket_types ALTER COLUMN amount TYPE int;
+ALTER TABLE public.pricing_rules ALTER COLUMN amount TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN points_redeemed TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN paid_gateway TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN paid_wallet TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN tax TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN fees TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN points_discount TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN discount TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN subtotal_price TYPE int;
+ALTER TABLE public.reservations ALTER COLUMN total_price TYPE int;
+ALTER TABLE public.reservation_items ALTER COLUMN total_price TYPE int;
+ALTER TABLE public.fees ALTER COLUMN amount TYPE int;
+ALTER TABLE public.seats ALTER COLUMN additional_price TYPE int;
+ALTER TABLE public.showtimes ALTER COLUMN price TYPE int;
+-- +goose StatementEnd
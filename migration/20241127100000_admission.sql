@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+INSERT INTO public.roles ("name") VALUES ('staff') ON CONFLICT DO NOTHING;
+
+CREATE TABLE IF NOT EXISTS public.admissions (
+	id bigserial NOT NULL,
+	reservation_item_id bigint NOT NULL,
+	reservation_id bigint NOT NULL,
+	showtime_id bigint NOT NULL,
+	seat_id bigint NOT NULL,
+	scanned_by bigint NULL,
+	device_id varchar DEFAULT '' NOT NULL,
+	scanned_at timestamptz NOT NULL, -- time on the scanner, may be earlier than created_at when synced offline
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT admissions_pk PRIMARY KEY (id),
+	CONSTRAINT admissions_reservation_item_unique UNIQUE (reservation_item_id),
+	CONSTRAINT admissions_reservation_items_fk FOREIGN KEY (reservation_item_id) REFERENCES public.reservation_items(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT admissions_reservations_fk FOREIGN KEY (reservation_id) REFERENCES public.reservations(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT admissions_showtimes_fk FOREIGN KEY (showtime_id) REFERENCES public.showtimes(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT admissions_users_fk FOREIGN KEY (scanned_by) REFERENCES public.users(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS admissions_showtime_id_idx ON public.admissions (showtime_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.admissions;
+
+DELETE FROM public.roles WHERE "name" = 'staff';
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS token_version bigint DEFAULT 0 NOT NULL; -- bumped to invalidate every access token of the user
+
+CREATE TABLE IF NOT EXISTS public.sessions (
+	id bigserial NOT NULL,
+	user_id bigint NOT NULL,
+	refresh_token_hash varchar NOT NULL,
+	previous_token_hash varchar NULL, -- refresh token before the last rotation, presenting it again means the token was stolen
+	user_agent varchar DEFAULT '' NOT NULL,
+	expires_at timestamptz NOT NULL,
+	revoked_at timestamptz NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT sessions_pk PRIMARY KEY (id),
+	CONSTRAINT sessions_refresh_token_hash_unique UNIQUE (refresh_token_hash),
+	CONSTRAINT sessions_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS sessions_user_id_idx ON public.sessions (user_id);
+CREATE INDEX IF NOT EXISTS sessions_previous_token_hash_idx ON public.sessions (previous_token_hash);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.sessions;
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS token_version;
+-- +goose StatementEnd
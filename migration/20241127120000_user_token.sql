@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.user_tokens (
+	id bigserial NOT NULL,
+	user_id bigint NOT NULL,
+	purpose varchar NOT NULL, -- what the token is for, e.g. password_reset
+	token_hash varchar NOT NULL,
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz NULL, -- token is single-use
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT user_tokens_pk PRIMARY KEY (id),
+	CONSTRAINT user_tokens_token_hash_unique UNIQUE (token_hash),
+	CONSTRAINT user_tokens_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS user_tokens_user_id_purpose_idx ON public.user_tokens (user_id, purpose);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.user_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS email_verified_at timestamptz NULL;
+
+-- users registered before verification existed keep working
+UPDATE public.users SET email_verified_at = created_at WHERE email_verified_at IS NULL;
+
+CREATE INDEX IF NOT EXISTS user_tokens_created_at_idx ON public.user_tokens (user_id, purpose, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS public.user_tokens_created_at_idx;
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS email_verified_at;
+-- +goose StatementEnd
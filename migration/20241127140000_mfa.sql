@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS mfa_secret varchar NULL; -- TOTP secret, set on enrollment
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS mfa_enabled_at timestamptz NULL; -- set when enrollment is confirmed
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS mfa_last_step bigint DEFAULT 0 NOT NULL; -- last accepted TOTP time step, a code cannot be replayed
+
+ALTER TABLE public.sessions ADD COLUMN IF NOT EXISTS mfa bool DEFAULT false NOT NULL; -- session started with second factor
+
+CREATE TABLE IF NOT EXISTS public.mfa_backup_codes (
+	id bigserial NOT NULL,
+	user_id bigint NOT NULL,
+	code_hash varchar NOT NULL,
+	used_at timestamptz NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT mfa_backup_codes_pk PRIMARY KEY (id),
+	CONSTRAINT mfa_backup_codes_unique UNIQUE (user_id, code_hash),
+	CONSTRAINT mfa_backup_codes_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.mfa_backup_codes;
+
+ALTER TABLE public.sessions DROP COLUMN IF EXISTS mfa;
+
+ALTER TABLE public.users DROP COLUMN IF EXISTS mfa_last_step;
+ALTER TABLE public.users DROP COLUMN IF EXISTS mfa_enabled_at;
+ALTER TABLE public.users DROP COLUMN IF EXISTS mfa_secret;
+-- +goose StatementEnd
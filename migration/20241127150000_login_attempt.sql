@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.login_attempts (
+	id bigserial NOT NULL,
+	user_id bigint NULL,
+	email varchar NOT NULL, -- lowercase, also recorded for unknown email
+	ip varchar NOT NULL,
+	user_agent varchar DEFAULT '' NOT NULL,
+	step varchar NOT NULL, -- password or mfa
+	success bool NOT NULL,
+	reason varchar DEFAULT '' NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT login_attempts_pk PRIMARY KEY (id),
+	CONSTRAINT login_attempts_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS login_attempts_email_idx ON public.login_attempts (email, created_at);
+CREATE INDEX IF NOT EXISTS login_attempts_ip_idx ON public.login_attempts (ip, created_at);
+
+-- consecutive failed login per account (email) and per ip, shared by every instance
+CREATE TABLE IF NOT EXISTS public.login_throttles (
+	scope varchar NOT NULL, -- account or ip
+	key varchar NOT NULL,
+	failures bigint DEFAULT 0 NOT NULL,
+	last_failed_at timestamptz DEFAULT NOW() NOT NULL,
+	locked_until timestamptz NULL,
+	CONSTRAINT login_throttles_pk PRIMARY KEY (scope, key)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.login_throttles;
+DROP TABLE IF EXISTS public.login_attempts;
+-- +goose StatementEnd
@@ -0,0 +1,35 @@
+-- +goose Up
+-- +goose StatementBegin
+-- pending sign in with identity provider, created on authorize and used once on callback
+CREATE TABLE IF NOT EXISTS public.oidc_states (
+	id bigserial NOT NULL,
+	provider varchar NOT NULL,
+	state_hash varchar NOT NULL,
+	nonce varchar NOT NULL,
+	code_verifier varchar NOT NULL, -- PKCE verifier, the provider only know its S256 challenge
+	expires_at timestamptz NOT NULL,
+	used_at timestamptz NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT oidc_states_pk PRIMARY KEY (id),
+	CONSTRAINT oidc_states_unique UNIQUE (state_hash)
+);
+
+-- external identity linked to user, subject is the stable id of the user at the provider
+CREATE TABLE IF NOT EXISTS public.user_identities (
+	id bigserial NOT NULL,
+	user_id bigint NOT NULL,
+	provider varchar NOT NULL,
+	subject varchar NOT NULL,
+	email varchar NOT NULL, -- email at the provider when linked
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT user_identities_pk PRIMARY KEY (id),
+	CONSTRAINT user_identities_unique UNIQUE (provider, subject),
+	CONSTRAINT user_identities_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.user_identities;
+DROP TABLE IF EXISTS public.oidc_states;
+-- +goose StatementEnd
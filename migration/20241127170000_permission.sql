@@ -0,0 +1,76 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS public.permissions (
+	"name" varchar NOT NULL,
+	description varchar NOT NULL,
+	CONSTRAINT permissions_pk PRIMARY KEY ("name")
+);
+
+CREATE TABLE IF NOT EXISTS public.role_permissions (
+	role_id int NOT NULL,
+	permission varchar NOT NULL,
+	CONSTRAINT role_permissions_pk PRIMARY KEY (role_id, permission),
+	CONSTRAINT role_permissions_roles_fk FOREIGN KEY (role_id) REFERENCES public.roles(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT role_permissions_permissions_fk FOREIGN KEY (permission) REFERENCES public.permissions("name") ON DELETE CASCADE ON UPDATE CASCADE
+);
+
+-- seed
+INSERT INTO public.permissions ("name", description) VALUES
+	('user.manage', 'manage user accounts, unlock login and audit login attempts'),
+	('role.manage', 'manage roles and their permissions'),
+	('movie.manage', 'manage movies and genres'),
+	('cinema.manage', 'manage cinemas, rooms, seats and blackouts'),
+	('showtime.manage', 'manage showtimes, format surcharges and the planner'),
+	('pricing.manage', 'manage ticket types, fees, pricing rules and promo codes'),
+	('gift_card.manage', 'issue and list gift cards'),
+	('reservation.read', 'view reservations and tickets of any user'),
+	('finance.manage', 'run and view reconciliations'),
+	('ticket.check_in', 'check in tickets and view attendance')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.roles ("name") VALUES
+	('box_office'),
+	('usher'),
+	('content_manager'),
+	('finance'),
+	('support')
+ON CONFLICT DO NOTHING;
+
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, p.permission
+FROM (
+	VALUES
+		('box_office', 'reservation.read'),
+		('box_office', 'ticket.check_in'),
+		('usher', 'ticket.check_in'),
+		('staff', 'ticket.check_in'),
+		('content_manager', 'movie.manage'),
+		('content_manager', 'cinema.manage'),
+		('content_manager', 'showtime.manage'),
+		('finance', 'pricing.manage'),
+		('finance', 'gift_card.manage'),
+		('finance', 'reservation.read'),
+		('finance', 'finance.manage'),
+		('support', 'user.manage'),
+		('support', 'reservation.read')
+) AS p(role, permission)
+JOIN public.roles r ON r."name" = p.role
+ON CONFLICT DO NOTHING;
+
+-- admin has every permission
+INSERT INTO public.role_permissions (role_id, permission)
+SELECT r.id, p."name"
+FROM public.roles r, public.permissions p
+WHERE r."name" = 'admin'
+ON CONFLICT DO NOTHING;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.role_permissions;
+DROP TABLE IF EXISTS public.permissions;
+-- user of a removed role would be deleted by cascade, make them regular user
+UPDATE public.users SET role_id = (SELECT id FROM public.roles WHERE "name" = 'user')
+WHERE role_id IN (SELECT id FROM public.roles WHERE "name" IN ('box_office', 'usher', 'content_manager', 'finance', 'support'));
+DELETE FROM public.roles WHERE "name" IN ('box_office', 'usher', 'content_manager', 'finance', 'support');
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS disabled_at timestamptz NULL;
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS disabled_reason varchar NULL;
+ALTER TABLE public.users ADD COLUMN IF NOT EXISTS deleted_at timestamptz NULL; -- personal data is erased, the row is kept for paid reservations and the ledger
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE public.users DROP COLUMN IF EXISTS disabled_reason;
+ALTER TABLE public.users DROP COLUMN IF EXISTS disabled_at;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+-- optional data of the user, missing row is an empty profile
+CREATE TABLE IF NOT EXISTS public.user_profiles (
+	user_id bigint NOT NULL,
+	display_name varchar DEFAULT '' NOT NULL,
+	phone varchar DEFAULT '' NOT NULL, -- E.164 format
+	birth_date date NULL,
+	preferred_cinema_id bigint NULL,
+	"language" varchar DEFAULT '' NOT NULL, -- ISO 639 code
+	marketing_consent bool DEFAULT false NOT NULL,
+	marketing_consent_at timestamptz NULL, -- last time consent was given
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT user_profiles_pk PRIMARY KEY (user_id),
+	CONSTRAINT user_profiles_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE,
+	CONSTRAINT user_profiles_cinemas_fk FOREIGN KEY (preferred_cinema_id) REFERENCES public.cinemas(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+
+-- every changed field of the profile, old and new value as text
+CREATE TABLE IF NOT EXISTS public.user_profile_changes (
+	id bigserial NOT NULL,
+	user_id bigint NOT NULL,
+	field varchar NOT NULL,
+	old_value varchar DEFAULT '' NOT NULL,
+	new_value varchar DEFAULT '' NOT NULL,
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT user_profile_changes_pk PRIMARY KEY (id),
+	CONSTRAINT user_profile_changes_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE CASCADE ON UPDATE CASCADE
+);
+CREATE INDEX IF NOT EXISTS user_profile_changes_user_id_idx ON public.user_profile_changes (user_id, created_at);
+
+-- age rating of the movie, checked against birth date of the user on reservation
+ALTER TABLE public.movies ADD COLUMN IF NOT EXISTS min_age int DEFAULT 0 NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE public.movies DROP COLUMN IF EXISTS min_age;
+
+DROP TABLE IF EXISTS public.user_profile_changes;
+DROP TABLE IF EXISTS public.user_profiles;
+-- +goose StatementEnd
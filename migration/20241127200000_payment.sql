@@ -0,0 +1,31 @@
+-- +goose Up
+-- +goose StatementBegin
+-- request to the payment gateway, recorded before the gateway is called so no charge is ever unknown,
+-- pending payment that stay pending need to be checked against the gateway
+CREATE TABLE IF NOT EXISTS public.payments (
+	id bigserial NOT NULL,
+	kind varchar NOT NULL, -- charge or refund
+	status varchar NOT NULL,
+	user_id bigint NULL,
+	reservation_id bigint NULL,
+	amount bigint NOT NULL,
+	description varchar DEFAULT '' NOT NULL,
+	idempotency_key varchar NOT NULL, -- sent to the gateway so a retried request is not charged twice
+	reference varchar DEFAULT '' NOT NULL, -- charge reference from the gateway, for refund it is the refunded charge
+	created_at timestamptz DEFAULT NOW() NOT NULL,
+	updated_at timestamptz DEFAULT NOW() NOT NULL,
+	CONSTRAINT payments_pk PRIMARY KEY (id),
+	CONSTRAINT payments_unique UNIQUE (idempotency_key),
+	CONSTRAINT payments_amount_check CHECK (amount > 0),
+	CONSTRAINT payments_users_fk FOREIGN KEY (user_id) REFERENCES public.users(id) ON DELETE SET NULL ON UPDATE CASCADE,
+	CONSTRAINT payments_reservations_fk FOREIGN KEY (reservation_id) REFERENCES public.reservations(id) ON DELETE SET NULL ON UPDATE CASCADE
+);
+-- one payment of a reservation at a time, a parallel request is refused before the gateway is charged
+CREATE UNIQUE INDEX payments_reservation_pending_idx ON public.payments (reservation_id, kind) WHERE status = 'pending';
+CREATE INDEX payments_status_idx ON public.payments (status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS public.payments;
+-- +goose StatementEnd
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type AdmissionFilter struct {
+	ReservationItemIDs []int64 `json:"reservation_item_ids"`
+	ShowtimeIDs        []int64 `json:"showtime_ids"`
+}
+
+type CheckInInput struct {
+	Token      string    `json:"token,omitempty"`       // scanned ticket token
+	ShowtimeID int64     `json:"showtime_id,omitempty"` // showtime the door is admitting
+	RoomID     int64     `json:"room_id,omitempty"`     // optional, room the door belongs to
+	DeviceID   string    `json:"device_id,omitempty"`   // optional, scanner identifier
+	ScannedAt  time.Time `json:"scanned_at,omitempty"`  // optional, default now, set by scanner syncing offline scans
+}
+
+func (i *CheckInInput) Validate() error {
+	i.Token = strings.TrimSpace(i.Token)
+	i.DeviceID = strings.TrimSpace(i.DeviceID)
+	if i.Token == "" {
+		return NewErr(ErrInput, nil, "token is required")
+	}
+	if i.ShowtimeID <= 0 {
+		return NewErr(ErrInput, nil, "showtime id is required")
+	}
+	if i.RoomID < 0 {
+		return NewErr(ErrInput, nil, "room id is invalid")
+	}
+	return nil
+}
+
+type CheckInSyncInput struct {
+	Scans []CheckInInput `json:"scans"`
+}
+
+func (i *CheckInSyncInput) Validate() error {
+	if len(i.Scans) == 0 {
+		return NewErr(ErrInput, nil, "scans is required")
+	}
+	if len(i.Scans) > 1_000 {
+		return NewErr(ErrInput, nil, "scans maximum is 1000 per sync")
+	}
+	return nil
+}
+
+type Admission struct {
+	ID                int64     `json:"id"`
+	ReservationItemID int64     `json:"reservation_item_id"`
+	ReservationID     int64     `json:"reservation_id"`
+	ShowtimeID        int64     `json:"showtime_id"`
+	SeatID            int64     `json:"seat_id"`
+	ScannedBy         int64     `json:"scanned_by,omitempty"`
+	DeviceID          string    `json:"device_id,omitempty"`
+	ScannedAt         time.Time `json:"scanned_at"`
+	CreatedAt         time.Time `json:"created_at"`
+
+	// relation
+	Movie string `json:"movie,omitempty"`
+	Room  string `json:"room,omitempty"`
+	Seat  string `json:"seat,omitempty"`
+}
+
+func NewAdmission(ticket *Ticket, input CheckInInput, scannedBy int64) *Admission {
+	scannedAt := input.ScannedAt
+	if scannedAt.IsZero() {
+		scannedAt = time.Now()
+	}
+	return &Admission{
+		ReservationItemID: ticket.ReservationItemID,
+		ReservationID:     ticket.ReservationID,
+		ShowtimeID:        ticket.ShowtimeID,
+		SeatID:            ticket.SeatID,
+		ScannedBy:         scannedBy,
+		DeviceID:          input.DeviceID,
+		ScannedAt:         scannedAt,
+		Movie:             ticket.Movie,
+		Room:              ticket.Room,
+		Seat:              ticket.Seat,
+	}
+}
+
+func ErrAlreadyAdmitted(first *Admission) error {
+	return NewErr(ErrInput, nil, "ticket already admitted at %s", first.ScannedAt.Format(time.RFC3339))
+}
+
+type CheckInStatus string
+
+const (
+	CheckInAdmitted  CheckInStatus = "admitted"
+	CheckInDuplicate CheckInStatus = "duplicate"
+	CheckInRejected  CheckInStatus = "rejected"
+)
+
+// CheckInResult is the outcome of one scan of bulk sync
+type CheckInResult struct {
+	Token          string        `json:"token"`
+	Status         CheckInStatus `json:"status"`
+	Reason         string        `json:"reason,omitempty"`
+	Admission      *Admission    `json:"admission,omitempty"`
+	FirstScannedAt *time.Time    `json:"first_scanned_at,omitempty"` // set for duplicate
+}
+
+type Attendance struct {
+	ShowtimeID int64     `json:"showtime_id"`
+	StartAt    time.Time `json:"start_at"`
+	EndAt      time.Time `json:"end_at"`
+	Sold       int64     `json:"sold"`     // seat of paid reservation
+	Admitted   int64     `json:"admitted"` // seat checked in
+	NotYet     int64     `json:"not_yet"`  // sold but not checked in yet
+	NoShow     int64     `json:"no_show"`  // sold but never checked in, counted once the showtime ended
+}
+
+func NewAttendance(showtime *Showtime, sold, admitted int64, now time.Time) *Attendance {
+	attendance := Attendance{
+		ShowtimeID: showtime.ID,
+		StartAt:    showtime.StartAt,
+		EndAt:      showtime.EndAt,
+		Sold:       sold,
+		Admitted:   admitted,
+	}
+	if now.After(showtime.EndAt) {
+		attendance.NoShow = sold - admitted
+	} else {
+		attendance.NotYet = sold - admitted
+	}
+	return &attendance
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type BlackoutFilter struct {
+	IDs           []int64   `json:"ids"`
+	RoomIDs       []int64   `json:"room_ids"`
+	CinemaIDs     []int64   `json:"cinema_ids"`
+	AffectRoomIDs []int64   `json:"affect_room_ids"`                            // blackout of the rooms or of the cinema of the rooms
+	After         time.Time `json:"after" example:"2006-01-02T15:04:05+08:00"`  // only list blackout ended after/equal this time
+	Before        time.Time `json:"before" example:"2006-01-02T15:04:05+08:00"` // only list blackout started before/equal this time
+}
+
+func (f *BlackoutFilter) Validate() error {
+	if !f.After.IsZero() && !f.Before.IsZero() && f.After.After(f.Before) {
+		return NewErr(ErrInput, nil, "time invalid")
+	}
+	return nil
+}
+
+type BlackoutInput struct {
+	RoomID   int64     `json:"room_id,omitempty"`
+	CinemaID int64     `json:"cinema_id,omitempty"`
+	StartAt  time.Time `json:"start_at,omitempty" example:"2006-01-02T15:04:05+08:00"`
+	EndAt    time.Time `json:"end_at,omitempty" example:"2006-01-02T18:04:05+08:00"`
+	Reason   string    `json:"reason,omitempty" example:"maintenance"`
+}
+
+func (i *BlackoutInput) Validate() error {
+	i.Reason = strings.Trim(i.Reason, " ")
+
+	if i.RoomID < 0 || i.CinemaID < 0 {
+		return NewErr(ErrInput, nil, "room id or cinema id is invalid")
+	}
+	if (i.RoomID > 0) == (i.CinemaID > 0) {
+		return NewErr(ErrInput, nil, "blackout must be set for either room or cinema")
+	}
+	if i.StartAt.IsZero() {
+		return NewErr(ErrInput, nil, "start at is required")
+	}
+	if i.EndAt.IsZero() {
+		return NewErr(ErrInput, nil, "end at is required")
+	}
+	if !i.StartAt.Before(i.EndAt) {
+		return NewErr(ErrInput, nil, "time invalid")
+	}
+	if i.Reason == "" {
+		return NewErr(ErrInput, nil, "reason is required")
+	}
+	return nil
+}
+
+func NewBlackout(input BlackoutInput) (*Blackout, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	blackout := Blackout{
+		RoomID:   input.RoomID,
+		CinemaID: input.CinemaID,
+		StartAt:  input.StartAt,
+		EndAt:    input.EndAt,
+		Reason:   input.Reason,
+	}
+	return &blackout, nil
+}
+
+type Blackout struct {
+	ID        int64     `json:"id,omitempty"`
+	RoomID    int64     `json:"room_id,omitempty"`
+	CinemaID  int64     `json:"cinema_id,omitempty"`
+	StartAt   time.Time `json:"start_at,omitempty"`
+	EndAt     time.Time `json:"end_at,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+// AffectRoom report whether room is blocked by this blackout
+func (b *Blackout) AffectRoom(room Room) bool {
+	if b.RoomID > 0 {
+		return b.RoomID == room.ID
+	}
+	return room.CinemaID > 0 && b.CinemaID == room.CinemaID
+}
+
+func (b *Blackout) ValidateOverlapping(startAt, endAt time.Time) error {
+	if isTimeOverlapping(b.StartAt, b.EndAt, startAt, endAt) {
+		return NewErr(
+			ErrInput,
+			nil,
+			"room is unavailable from %s until %s: %s",
+			b.StartAt.Format(time.RFC3339),
+			b.EndAt.Format(time.RFC3339),
+			b.Reason,
+		)
+	}
+	return nil
+}
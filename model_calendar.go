@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+const calendarDomain = "movie-reservation-system"
+
+type CalendarEventStatus string
+
+const (
+	CalendarTentative CalendarEventStatus = "TENTATIVE"
+	CalendarConfirmed CalendarEventStatus = "CONFIRMED"
+	CalendarCancelled CalendarEventStatus = "CANCELLED"
+)
+
+// CalendarEvent is one VEVENT, the uid stay the same across updates so subscribed calendar replace
+// the event instead of adding new one, sequence tell which version is newer
+type CalendarEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	End         time.Time
+	Stamp       time.Time
+	Sequence    int64
+	Status      CalendarEventStatus
+}
+
+// calendarSequence grow on every update of the record
+func calendarSequence(createdAt, updatedAt time.Time) int64 {
+	return max(int64(updatedAt.Sub(createdAt).Seconds()), 0)
+}
+
+// NewReservationEvents create one event for each showtime of the reservation with the reserved seats
+func NewReservationEvents(reservation *Reservation, location string) []CalendarEvent {
+	status := CalendarTentative
+	switch reservation.Status {
+	case ReservationPaid:
+		status = CalendarConfirmed
+	case ReservationCancelled:
+		status = CalendarCancelled
+	}
+
+	itemMap := map[int64][]ReservationItem{}
+	for _, item := range reservation.Items {
+		itemMap[item.ShowtimeID] = append(itemMap[item.ShowtimeID], item)
+	}
+	showtimeIDs := make([]int64, 0, len(itemMap))
+	for showtimeID := range itemMap {
+		showtimeIDs = append(showtimeIDs, showtimeID)
+	}
+	sort.Slice(showtimeIDs, func(i, j int) bool { return showtimeIDs[i] < showtimeIDs[j] })
+
+	events := make([]CalendarEvent, 0, len(showtimeIDs))
+	for _, showtimeID := range showtimeIDs {
+		items := itemMap[showtimeID]
+		seats := make([]string, 0, len(items))
+		for _, item := range items {
+			seats = append(seats, item.Seat)
+		}
+		eventLocation := items[0].Room
+		if location != "" {
+			eventLocation = fmt.Sprintf("%s, %s", items[0].Room, location)
+		}
+		events = append(events, CalendarEvent{
+			UID:         fmt.Sprintf("reservation-%d-showtime-%d@%s", reservation.ID, showtimeID, calendarDomain),
+			Summary:     items[0].Movie,
+			Description: fmt.Sprintf("Reservation #%d, seat %s", reservation.ID, strings.Join(seats, ", ")),
+			Location:    eventLocation,
+			Start:       items[0].ShowtimeStart,
+			End:         items[0].ShowtimeEnd,
+			Stamp:       reservation.UpdatedAt,
+			Sequence:    calendarSequence(reservation.CreatedAt, reservation.UpdatedAt),
+			Status:      status,
+		})
+	}
+	return events
+}
+
+func NewShowtimeEvent(showtime *Showtime, location string) CalendarEvent {
+	eventLocation := showtime.RoomName
+	if location != "" {
+		eventLocation = fmt.Sprintf("%s, %s", showtime.RoomName, location)
+	}
+	description := fmt.Sprintf("%s, %d of %d seat available", showtime.Format, showtime.AvailableSeat, showtime.TotalSeat)
+	if showtime.Format == "" {
+		description = fmt.Sprintf("%d of %d seat available", showtime.AvailableSeat, showtime.TotalSeat)
+	}
+	return CalendarEvent{
+		UID:         fmt.Sprintf("showtime-%d@%s", showtime.ID, calendarDomain),
+		Summary:     showtime.MovieTitle,
+		Description: description,
+		Location:    eventLocation,
+		Start:       showtime.StartAt,
+		End:         showtime.EndAt,
+		Stamp:       showtime.UpdatedAt,
+		Sequence:    calendarSequence(showtime.CreatedAt, showtime.UpdatedAt),
+		Status:      CalendarConfirmed,
+	}
+}
@@ -19,6 +19,8 @@ type CartInput struct {
 	UserID     int64 `json:"user_id,omitempty"`
 	ShowtimeID int64 `json:"showtime_id,omitempty"`
 	SeatID     int64 `json:"seat_id,omitempty"`
+
+	TicketTypeID int64 `json:"ticket_type_id,omitempty"` // optional, default ticket type when empty
 }
 
 func (i *CartInput) Validate() error {
@@ -31,6 +33,9 @@ func (i *CartInput) Validate() error {
 	if i.SeatID <= 0 {
 		return NewErr(ErrInput, nil, "seat id is invalid")
 	}
+	if i.TicketTypeID < 0 {
+		return NewErr(ErrInput, nil, "ticket type id is invalid")
+	}
 	return nil
 }
 
@@ -43,6 +48,8 @@ func NewCart(input CartInput) (*Cart, error) {
 		UserID:     input.UserID,
 		ShowtimeID: input.ShowtimeID,
 		SeatID:     input.SeatID,
+
+		TicketTypeID: input.TicketTypeID,
 	}
 	return &Cart, nil
 }
@@ -55,11 +62,18 @@ type Cart struct {
 	CreatedAt  time.Time `json:"created_at,omitempty"`
 	UpdatedAt  time.Time `json:"updated_at,omitempty"`
 
+	TicketTypeID int64 `json:"ticket_type_id,omitempty"`
+
 	// relation
 	Movie         string    `json:"movie"`
 	ShowtimeStart time.Time `json:"showtime_start"`
 	ShowtimeEnd   time.Time `json:"showtime_end"`
 	Room          string    `json:"room"`
 	Seat          string    `json:"seat"`
-	Price         int64     `json:"price"`
+	BasePrice     int64     `json:"base_price"`
+	Price         int64     `json:"price"` // base price after pricing rules applied
+	Currency      Currency  `json:"currency"`
+
+	Pricing   *PriceSnapshot `json:"pricing,omitempty"`
+	occupancy int64
 }
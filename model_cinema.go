@@ -0,0 +1,73 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type CinemaFilter struct {
+	IDs   []int64  `json:"ids"`
+	Names []string `json:"names"`
+}
+
+func (f *CinemaFilter) Validate() error {
+	for i, v := range f.Names {
+		name := strings.Trim(v, " ")
+		if name == "" {
+			return NewErr(ErrInput, nil, "name is required")
+		}
+		f.Names[i] = name
+	}
+	return nil
+}
+
+type CinemaInput struct {
+	Name     string   `json:"name,omitempty"`
+	Address  string   `json:"address,omitempty"`
+	TaxRate  int64    `json:"tax_rate,omitempty" example:"1100"` // basis points, 1100 is 11%
+	Currency Currency `json:"currency,omitempty" example:"IDR"`  // default IDR
+}
+
+func (i *CinemaInput) Validate() error {
+	i.Name = strings.Trim(i.Name, " ")
+	i.Address = strings.Trim(i.Address, " ")
+	if i.Name == "" {
+		return NewErr(ErrInput, nil, "name is required")
+	}
+	if i.TaxRate < 0 || i.TaxRate > 10_000 {
+		return NewErr(ErrInput, nil, "tax rate must be between 0 and 10000 basis points")
+	}
+	currency, err := NormalizeCurrency(i.Currency)
+	if err != nil {
+		return err
+	}
+	i.Currency = currency
+	return nil
+}
+
+type Cinema struct {
+	ID        int64     `json:"id,omitempty"`
+	Name      string    `json:"name,omitempty"`
+	Address   string    `json:"address"`
+	TaxRate   int64     `json:"tax_rate"`
+	Currency  Currency  `json:"currency"`
+	CreatedAt time.Time `json:"created_at,omitempty"`
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+
+	// relation
+	TotalRoom int64 `json:"total_room"`
+}
+
+func NewCinema(input CinemaInput) (*Cinema, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	cinema := Cinema{
+		Name:     input.Name,
+		Address:  input.Address,
+		TaxRate:  input.TaxRate,
+		Currency: input.Currency,
+	}
+	return &cinema, nil
+}
@@ -0,0 +1,154 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type FeeKind string
+
+const (
+	FeePerTicket FeeKind = "per_ticket"
+	FeePerOrder  FeeKind = "per_order"
+)
+
+type FeeFilter struct {
+	IDs       []int64 `json:"ids"`
+	CinemaIDs []int64 `json:"cinema_ids"`
+	IsActive  *bool   `json:"is_active"`
+}
+
+func (f *FeeFilter) Validate() error {
+	return nil
+}
+
+type FeeInput struct {
+	Name     string   `json:"name,omitempty" example:"Booking fee"`
+	Kind     FeeKind  `json:"kind,omitempty" example:"per_ticket"`
+	Amount   int64    `json:"amount,omitempty" example:"2500"`
+	Currency Currency `json:"currency,omitempty" example:"IDR"` // default IDR, must match the cinema currency
+	CinemaID int64    `json:"cinema_id,omitempty" example:"0"`  // optional, empty apply to every cinema
+	IsActive *bool    `json:"is_active,omitempty"`              // default true
+}
+
+func (i *FeeInput) Validate() error {
+	i.Name = strings.Trim(i.Name, " ")
+	i.Kind = FeeKind(strings.ToLower(strings.Trim(string(i.Kind), " ")))
+
+	if i.Name == "" {
+		return NewErr(ErrInput, nil, "name is required")
+	}
+	if i.Kind != FeePerTicket && i.Kind != FeePerOrder {
+		return NewErr(ErrInput, nil, "kind must be %s or %s", FeePerTicket, FeePerOrder)
+	}
+	if i.Amount <= 0 {
+		return NewErr(ErrInput, nil, "amount minimum is 1")
+	}
+	currency, err := NormalizeCurrency(i.Currency)
+	if err != nil {
+		return err
+	}
+	i.Currency = currency
+	if i.CinemaID < 0 {
+		return NewErr(ErrInput, nil, "cinema id is invalid")
+	}
+	if i.IsActive == nil {
+		active := true
+		i.IsActive = &active
+	}
+	return nil
+}
+
+func NewFee(input FeeInput) (*Fee, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	fee := Fee{
+		Name:     input.Name,
+		Kind:     input.Kind,
+		Amount:   input.Amount,
+		Currency: input.Currency,
+		CinemaID: input.CinemaID,
+		IsActive: *input.IsActive,
+	}
+	return &fee, nil
+}
+
+type Fee struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	Kind      FeeKind   `json:"kind"`
+	Amount    int64     `json:"amount"`
+	Currency  Currency  `json:"currency"`
+	CinemaID  int64     `json:"cinema_id,omitempty"`
+	IsActive  bool      `json:"is_active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AppliesTo check the fee is charged in the cinema, fee for every cinema only applies to cinema of the same currency
+func (f *Fee) AppliesTo(cinemaID int64, currency Currency) bool {
+	if !f.IsActive {
+		return false
+	}
+	if f.CinemaID == 0 {
+		return f.Currency == currency
+	}
+	return f.CinemaID == cinemaID
+}
+
+// ReservationFee is the fee charged on a reservation, kept as is when the fee changes later
+type ReservationFee struct {
+	FeeID    int64   `json:"fee_id"`
+	Name     string  `json:"name"`
+	Kind     FeeKind `json:"kind"`
+	Amount   int64   `json:"amount"`
+	Quantity int64   `json:"quantity"`
+	Total    int64   `json:"total"`
+}
+
+// ApplyFees charge the fees of the cinema to an order of n tickets
+func ApplyFees(fees []Fee, cinemaID int64, currency Currency, tickets int64) ([]ReservationFee, int64, error) {
+	items := []ReservationFee{}
+	total := NewMoney(0, currency)
+	for _, fee := range fees {
+		if !fee.AppliesTo(cinemaID, currency) {
+			continue
+		}
+		quantity := int64(1)
+		if fee.Kind == FeePerTicket {
+			quantity = tickets
+		}
+		subtotal, err := NewMoney(fee.Amount, fee.Currency).Mul(quantity)
+		if err != nil {
+			return nil, 0, err
+		}
+		total, err = total.Add(subtotal)
+		if err != nil {
+			return nil, 0, err
+		}
+		items = append(items, ReservationFee{
+			FeeID:    fee.ID,
+			Name:     fee.Name,
+			Kind:     fee.Kind,
+			Amount:   fee.Amount,
+			Quantity: quantity,
+			Total:    subtotal.Amount,
+		})
+	}
+	return items, total.Amount, nil
+}
+
+// Tax of the taxable amount, rate is in basis points and rounded half up
+func Tax(taxable, rate int64) int64 {
+	if taxable <= 0 || rate <= 0 {
+		return 0
+	}
+	tax, err := MulAmount(taxable, rate)
+	if err != nil {
+		// split to keep the product in range, only reachable for absurd amount
+		return taxable/10_000*rate + Tax(taxable%10_000, rate)
+	}
+	return (tax + 5_000) / 10_000
+}
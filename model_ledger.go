@@ -0,0 +1,158 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type LedgerAccountType string
+
+const (
+	LedgerAsset     LedgerAccountType = "asset"
+	LedgerLiability LedgerAccountType = "liability"
+	LedgerEquity    LedgerAccountType = "equity"
+	LedgerRevenue   LedgerAccountType = "revenue"
+	LedgerExpense   LedgerAccountType = "expense"
+)
+
+// fixed ledger accounts
+const (
+	AccountGatewayCash   = "asset:gateway"
+	AccountReceivable    = "asset:receivable" // sold but not collected yet
+	AccountTicketRevenue = "revenue:ticket"
+	AccountFeeRevenue    = "revenue:fee"
+	AccountTaxPayable    = "liability:tax"
+	AccountDiscount      = "expense:discount" // promo code and redeemed points
+	AccountGiftCardGrant = "expense:gift_card_grant"
+)
+
+func WalletAccount(userID int64) string {
+	return fmt.Sprintf("liability:wallet:%d", userID)
+}
+
+func GiftCardAccount(giftCardID int64) string {
+	return fmt.Sprintf("liability:gift_card:%d", giftCardID)
+}
+
+// LedgerAccountTypeOf derive the account type from its code prefix
+func LedgerAccountTypeOf(code string) LedgerAccountType {
+	for _, t := range []LedgerAccountType{LedgerAsset, LedgerLiability, LedgerEquity, LedgerRevenue, LedgerExpense} {
+		prefix := string(t) + ":"
+		if len(code) > len(prefix) && strings.HasPrefix(code, prefix) {
+			return t
+		}
+	}
+	return ""
+}
+
+type LedgerJournalKind string
+
+const (
+	JournalGiftCardIssue    LedgerJournalKind = "gift_card_issue"
+	JournalGiftCardPurchase LedgerJournalKind = "gift_card_purchase"
+	JournalWalletTopUp      LedgerJournalKind = "wallet_top_up"
+	JournalSale             LedgerJournalKind = "sale"
+	JournalSaleReversal     LedgerJournalKind = "sale_reversal"
+	JournalPayment          LedgerJournalKind = "payment"
+	JournalRefund           LedgerJournalKind = "refund"
+)
+
+type LedgerEntryInput struct {
+	AccountCode string
+	Amount      int64 // positive for debit, negative for credit
+}
+
+type LedgerJournalInput struct {
+	Kind          LedgerJournalKind
+	Description   string
+	Reference     string
+	ReservationID int64
+	GiftCardID    int64
+	Entries       []LedgerEntryInput
+}
+
+// Validate make sure the journal is balanced, total debit equals total credit
+func (i *LedgerJournalInput) Validate() error {
+	if i.Kind == "" {
+		return NewErr(ErrInput, nil, "journal kind is required")
+	}
+	if len(i.Entries) < 2 {
+		return NewErr(ErrInput, nil, "journal require at least 2 entries")
+	}
+	var sum int64
+	for _, entry := range i.Entries {
+		if entry.Amount == 0 {
+			return NewErr(ErrInput, nil, "journal entry amount cannot be 0")
+		}
+		if LedgerAccountTypeOf(entry.AccountCode) == "" {
+			return NewErr(ErrInput, nil, "ledger account %s is invalid", entry.AccountCode)
+		}
+		sum += entry.Amount
+	}
+	if sum != 0 {
+		return NewErr(ErrInput, nil, "journal is not balanced, difference %d", sum)
+	}
+	return nil
+}
+
+// NewLedgerJournalInput drop entries with zero amount, so optional legs like split payment can be passed as is
+func NewLedgerJournalInput(kind LedgerJournalKind, description string, entries ...LedgerEntryInput) LedgerJournalInput {
+	input := LedgerJournalInput{Kind: kind, Description: description}
+	for _, entry := range entries {
+		if entry.Amount != 0 {
+			input.Entries = append(input.Entries, entry)
+		}
+	}
+	return input
+}
+
+// SaleJournal recognize the revenue of the reservation, the price after discount is owed as receivable
+// until it is collected by payment
+func SaleJournal(reservation *Reservation) LedgerJournalInput {
+	journal := NewLedgerJournalInput(
+		JournalSale,
+		fmt.Sprintf("sale of reservation #%d", reservation.ID),
+		LedgerEntryInput{AccountCode: AccountReceivable, Amount: reservation.TotalPrice},
+		LedgerEntryInput{AccountCode: AccountDiscount, Amount: reservation.Discount + reservation.PointsDiscount},
+		LedgerEntryInput{AccountCode: AccountTicketRevenue, Amount: -reservation.SubtotalPrice},
+		LedgerEntryInput{AccountCode: AccountFeeRevenue, Amount: -reservation.Fees},
+		LedgerEntryInput{AccountCode: AccountTaxPayable, Amount: -reservation.Tax},
+	)
+	journal.ReservationID = reservation.ID
+	return journal
+}
+
+// SaleReversalJournal undo the sale of cancelled reservation, entries are never removed from ledger
+func SaleReversalJournal(reservation *Reservation) LedgerJournalInput {
+	sale := SaleJournal(reservation)
+	journal := LedgerJournalInput{
+		Kind:          JournalSaleReversal,
+		Description:   fmt.Sprintf("sale of reservation #%d reversed", reservation.ID),
+		ReservationID: reservation.ID,
+	}
+	for _, entry := range sale.Entries {
+		journal.Entries = append(journal.Entries, LedgerEntryInput{AccountCode: entry.AccountCode, Amount: -entry.Amount})
+	}
+	return journal
+}
+
+type LedgerEntryFilter struct {
+	AccountCodes   []string `json:"account_codes"`
+	ReservationIDs []int64  `json:"reservation_ids"`
+	GiftCardIDs    []int64  `json:"gift_card_ids"`
+	Kinds          []string `json:"kinds"`
+}
+
+type LedgerEntry struct {
+	ID            int64             `json:"id"`
+	JournalID     int64             `json:"journal_id"`
+	Kind          LedgerJournalKind `json:"kind"`
+	Description   string            `json:"description"`
+	Reference     string            `json:"reference,omitempty"`
+	ReservationID int64             `json:"reservation_id,omitempty"`
+	GiftCardID    int64             `json:"gift_card_id,omitempty"`
+	AccountCode   string            `json:"account_code"`
+	Amount        int64             `json:"amount"` // positive for debit, negative for credit
+	CreatedAt     time.Time         `json:"created_at"`
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type LoginStep string
+
+const (
+	LoginStepPassword LoginStep = "password"
+	LoginStepMFA      LoginStep = "mfa"
+)
+
+type LoginThrottleScope string
+
+const (
+	LoginThrottleAccount LoginThrottleScope = "account"
+	LoginThrottleIP      LoginThrottleScope = "ip"
+)
+
+// failed login count start over when the last failure is older than this
+const loginFailureWindow = 24 * time.Hour
+
+type LoginAttemptFilter struct {
+	UserIDs []int64  `json:"user_ids"`
+	Emails  []string `json:"emails"`
+	IPs     []string `json:"ips"`
+	Success *bool    `json:"success"`
+}
+
+func NewLoginAttempt(email, ip, userAgent string, step LoginStep, err error) *LoginAttempt {
+	attempt := LoginAttempt{
+		Email:     normalizeLoginEmail(email),
+		IP:        ip,
+		UserAgent: userAgent,
+		Step:      step,
+		Success:   err == nil,
+	}
+	if err != nil {
+		attempt.Reason = err.Error()
+	}
+	return &attempt
+}
+
+type LoginAttempt struct {
+	ID        int64     `json:"id"`
+	UserID    *int64    `json:"user_id"`
+	Email     string    `json:"email"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent"`
+	Step      LoginStep `json:"step"`
+	Success   bool      `json:"success"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginThrottle is the consecutive failed login of an account or an ip
+type LoginThrottle struct {
+	Scope        LoginThrottleScope
+	Key          string
+	Failures     int64
+	LastFailedAt time.Time
+	LockedUntil  *time.Time
+}
+
+func normalizeLoginEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// LoginBackoff is how long login is locked after the failures, zero below maxFailures,
+// then start from base and doubled on every next failure up to limit
+func LoginBackoff(failures, maxFailures int64, base, limit time.Duration) time.Duration {
+	if failures < maxFailures {
+		return 0
+	}
+	wait := base
+	for i := maxFailures; i < failures && wait < limit; i++ {
+		wait *= 2
+	}
+	return min(wait, limit)
+}
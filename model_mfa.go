@@ -0,0 +1,69 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type MFAEnrollment struct {
+	Secret string `json:"secret"`  // for manual entry in authenticator app
+	URI    string `json:"uri"`     // otpauth:// provisioning URI
+	QRCode string `json:"qr_code"` // provisioning URI as PNG data URI
+}
+
+type MFACodeInput struct {
+	Code string `json:"code"` // TOTP code, or backup code when allowed
+}
+
+func (i *MFACodeInput) Validate() error {
+	i.Code = normalizeMFACode(i.Code)
+	if i.Code == "" {
+		return NewErr(ErrInput, nil, "code is required")
+	}
+	return nil
+}
+
+type MFALoginInput struct {
+	MFAToken string `json:"mfa_token"` // challenge token from login
+	Code     string `json:"code"`      // TOTP code or backup code
+}
+
+func (i *MFALoginInput) Validate() error {
+	i.MFAToken = strings.TrimSpace(i.MFAToken)
+	i.Code = normalizeMFACode(i.Code)
+	if i.MFAToken == "" {
+		return NewErr(ErrInput, nil, "mfa token is required")
+	}
+	if i.Code == "" {
+		return NewErr(ErrInput, nil, "code is required")
+	}
+	return nil
+}
+
+// normalizeMFACode remove spaces and the dash of backup code, as typed by the user
+func normalizeMFACode(code string) string {
+	return strings.ToLower(strings.NewReplacer(" ", "", "-", "").Replace(code))
+}
+
+type MFABackupCodes struct {
+	Codes []string `json:"codes"` // shown once, each code can be used once instead of TOTP code
+}
+
+// NewMFABackupCodes return the codes to show and the hashes to store
+func NewMFABackupCodes() ([]string, []string) {
+	codes := make([]string, 0, mfaBackupSize)
+	hashes := make([]string, 0, mfaBackupSize)
+	for range mfaBackupSize {
+		token := randomToken(5)
+		code := token[:5] + "-" + token[5:]
+		codes = append(codes, code)
+		hashes = append(hashes, HashToken(token))
+	}
+	return codes, hashes
+}
+
+type MFAStatus struct {
+	Enabled     bool       `json:"enabled"`
+	EnabledAt   *time.Time `json:"enabled_at,omitempty"`
+	BackupCodes int64      `json:"backup_codes"` // unused backup codes left
+}
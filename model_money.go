@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Currency is ISO 4217 currency code
+type Currency string
+
+const DefaultCurrency Currency = "IDR"
+
+// currencyMinorUnits is the number of decimal of the minor unit every amount is stored in,
+// rupiah is priced in whole unit
+var currencyMinorUnits = map[Currency]int{
+	"IDR": 0,
+	"SGD": 2,
+	"MYR": 2,
+	"USD": 2,
+	"EUR": 2,
+	"JPY": 0,
+}
+
+func isCurrencyValid(currency Currency) bool {
+	_, ok := currencyMinorUnits[currency]
+	return ok
+}
+
+// NormalizeCurrency upper case the code and fallback empty one to default currency
+func NormalizeCurrency(currency Currency) (Currency, error) {
+	currency = Currency(strings.ToUpper(strings.Trim(string(currency), " ")))
+	if currency == "" {
+		return DefaultCurrency, nil
+	}
+	if !isCurrencyValid(currency) {
+		return "", NewErr(ErrInput, nil, "currency %s is not supported", currency)
+	}
+	return currency, nil
+}
+
+// Money is an amount in the minor unit of its currency
+type Money struct {
+	Amount   int64    `json:"amount"`
+	Currency Currency `json:"currency"`
+}
+
+func NewMoney(amount int64, currency Currency) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// Add sum money of the same currency, mixing currency or overflowing is rejected
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, NewErr(ErrInput, nil, "cannot mix %s and %s", m.Currency, other.Currency)
+	}
+	amount, err := AddAmount(m.Amount, other.Amount)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(amount, m.Currency), nil
+}
+
+func (m Money) Mul(n int64) (Money, error) {
+	amount, err := MulAmount(m.Amount, n)
+	if err != nil {
+		return Money{}, err
+	}
+	return NewMoney(amount, m.Currency), nil
+}
+
+// String format the money in major unit, e.g. USD 12.50
+func (m Money) String() string {
+	digits := currencyMinorUnits[m.Currency]
+	if digits == 0 {
+		return fmt.Sprintf("%s %d", m.Currency, m.Amount)
+	}
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	unit := int64(math.Pow10(digits))
+	return fmt.Sprintf("%s %s%d.%0*d", m.Currency, sign, amount/unit, digits, amount%unit)
+}
+
+func AddAmount(a, b int64) (int64, error) {
+	if (b > 0 && a > math.MaxInt64-b) || (b < 0 && a < math.MinInt64-b) {
+		return 0, NewErr(ErrInput, nil, "amount is too large")
+	}
+	return a + b, nil
+}
+
+func MulAmount(a, b int64) (int64, error) {
+	if a == 0 || b == 0 {
+		return 0, nil
+	}
+	result := a * b
+	if result/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+		return 0, NewErr(ErrInput, nil, "amount is too large")
+	}
+	return result, nil
+}
@@ -7,13 +7,15 @@ import (
 )
 
 type MovieInput struct {
-	Title       string    `json:"title,omitempty"`
-	ReleaseDate time.Time `json:"release_date,omitempty" example:"2006-01-02T15:04:05+08:00"`
-	Director    string    `json:"director,omitempty"`
-	Duration    int64     `json:"duration,omitempty"`
-	PosterURL   string    `json:"poster_url,omitempty"`
-	Description string    `json:"description,omitempty"`
-	GenreIDs    []int64   `json:"genre_ids,omitempty"`
+	Title            string    `json:"title,omitempty"`
+	ReleaseDate      time.Time `json:"release_date,omitempty" example:"2006-01-02T15:04:05+08:00"`
+	Director         string    `json:"director,omitempty"`
+	Duration         int64     `json:"duration,omitempty"`
+	PosterURL        string    `json:"poster_url,omitempty"`
+	Description      string    `json:"description,omitempty"`
+	GenreIDs         []int64   `json:"genre_ids,omitempty"`
+	OriginalLanguage string    `json:"original_language,omitempty" example:"en"` // optional, ISO 639 code
+	MinAge           int64     `json:"min_age,omitempty" example:"13"`           // age rating, 0 for all ages
 }
 
 func (i *MovieInput) Validate() error {
@@ -47,6 +49,13 @@ func (i *MovieInput) Validate() error {
 	if len(i.GenreIDs) == 0 {
 		return NewErr(ErrInput, nil, "genre ids is required")
 	}
+	i.OriginalLanguage, err = normalizeLanguage(i.OriginalLanguage)
+	if err != nil {
+		return NewErr(ErrInput, err, "original language is invalid")
+	}
+	if i.MinAge < 0 || i.MinAge > 21 {
+		return NewErr(ErrInput, nil, "min age must be between 0 and 21")
+	}
 
 	return nil
 }
@@ -74,30 +83,34 @@ func NewMovie(input MovieInput) (*Movie, error) {
 
 	now := time.Now()
 	movie := Movie{
-		Title:       input.Title,
-		ReleaseDate: input.ReleaseDate,
-		Director:    input.Director,
-		Duration:    input.Duration,
-		PosterURL:   input.PosterURL,
-		Description: input.Description,
-		GenreIDs:    input.GenreIDs,
-		CreatedAt:   now,
-		UpdatedAt:   now,
+		Title:            input.Title,
+		ReleaseDate:      input.ReleaseDate,
+		Director:         input.Director,
+		Duration:         input.Duration,
+		PosterURL:        input.PosterURL,
+		Description:      input.Description,
+		GenreIDs:         input.GenreIDs,
+		OriginalLanguage: input.OriginalLanguage,
+		MinAge:           input.MinAge,
+		CreatedAt:        now,
+		UpdatedAt:        now,
 	}
 
 	return &movie, nil
 }
 
 type Movie struct {
-	ID          int64     `json:"id"`
-	Title       string    `json:"title"`
-	ReleaseDate time.Time `json:"release_date"`
-	Director    string    `json:"director"`
-	Duration    int64     `json:"duration"`
-	PosterURL   string    `json:"poster_url"`
-	Description string    `json:"description"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID               int64     `json:"id"`
+	Title            string    `json:"title"`
+	ReleaseDate      time.Time `json:"release_date"`
+	Director         string    `json:"director"`
+	Duration         int64     `json:"duration"`
+	PosterURL        string    `json:"poster_url"`
+	Description      string    `json:"description"`
+	OriginalLanguage string    `json:"original_language"`
+	MinAge           int64     `json:"min_age"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
 
 	// relation
 	GenreIDs []int64  `json:"genre_ids"`
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// sign in at the provider must be completed within this time
+const oidcStateTTL = 10 * time.Minute
+
+// oidcStateCookie keep the state in the browser that started sign in,
+// callback with state issued to another browser is refused so nobody can be signed in to the account of someone else
+const oidcStateCookie = "oidc_state"
+
+type OIDCAuthorization struct {
+	URL   string `json:"url"`   // redirect the user here
+	State string `json:"state"` // returned by the provider to the callback
+}
+
+type OIDCCallbackInput struct {
+	Code  string `json:"code" query:"code"`
+	State string `json:"state" query:"state"`
+	Error string `json:"error" query:"error"` // set by the provider when sign in is denied
+}
+
+func (i *OIDCCallbackInput) Validate() error {
+	if i.Error != "" {
+		return NewErr(ErrInput, nil, "sign in failed: %s", i.Error)
+	}
+	i.Code = strings.TrimSpace(i.Code)
+	i.State = strings.TrimSpace(i.State)
+	if i.Code == "" {
+		return NewErr(ErrInput, nil, "code is required")
+	}
+	if i.State == "" {
+		return NewErr(ErrInput, nil, "state is required")
+	}
+	return nil
+}
+
+// NewOIDCState create the state, nonce and PKCE verifier of an authorization, the returned state is never stored
+func NewOIDCState(provider string) (*OIDCState, string) {
+	state := randomToken(32)
+	oidcState := OIDCState{
+		Provider:     provider,
+		StateHash:    HashToken(state),
+		Nonce:        randomToken(16),
+		CodeVerifier: oauth2.GenerateVerifier(),
+		ExpiresAt:    time.Now().Add(oidcStateTTL),
+	}
+	return &oidcState, state
+}
+
+type OIDCState struct {
+	ID           int64
+	Provider     string
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+// OIDCIdentity is the user verified by the id token of the provider
+type OIDCIdentity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+}
+
+type UserIdentity struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Provider  string    `json:"provider"`
+	Subject   string    `json:"subject"`
+	Email     string    `json:"email"`
+	CreatedAt time.Time `json:"created_at"`
+}
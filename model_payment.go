@@ -0,0 +1,45 @@
+package main
+
+import "time"
+
+type PaymentKind string
+
+const (
+	PaymentKindCharge PaymentKind = "charge"
+	PaymentKindRefund PaymentKind = "refund"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentPending  PaymentStatus = "pending"  // recorded, gateway not called yet or the result is not known
+	PaymentSettled  PaymentStatus = "settled"  // gateway succeed and the purchase is stored
+	PaymentFailed   PaymentStatus = "failed"   // gateway refused, nothing was collected
+	PaymentReversed PaymentStatus = "reversed" // gateway charged but the purchase failed, the charge is refunded
+)
+
+func NewPayment(kind PaymentKind, userID, reservationID, amount int64, description string) *Payment {
+	return &Payment{
+		Kind:           kind,
+		Status:         PaymentPending,
+		UserID:         userID,
+		ReservationID:  reservationID,
+		Amount:         amount,
+		Description:    description,
+		IdempotencyKey: randomToken(16),
+	}
+}
+
+type Payment struct {
+	ID             int64         `json:"id"`
+	Kind           PaymentKind   `json:"kind"`
+	Status         PaymentStatus `json:"status"`
+	UserID         int64         `json:"user_id,omitempty"`
+	ReservationID  int64         `json:"reservation_id,omitempty"`
+	Amount         int64         `json:"amount"`
+	Description    string        `json:"description"`
+	IdempotencyKey string        `json:"-"`
+	Reference      string        `json:"reference"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
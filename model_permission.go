@@ -0,0 +1,70 @@
+package main
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type Permission string
+
+// permissions are seeded by migration, a new one must be added there too
+const (
+	PermissionUserManage      Permission = "user.manage"
+	PermissionRoleManage      Permission = "role.manage"
+	PermissionMovieManage     Permission = "movie.manage"
+	PermissionCinemaManage    Permission = "cinema.manage"
+	PermissionShowtimeManage  Permission = "showtime.manage"
+	PermissionPricingManage   Permission = "pricing.manage"
+	PermissionGiftCardManage  Permission = "gift_card.manage"
+	PermissionReservationRead Permission = "reservation.read"
+	PermissionFinanceManage   Permission = "finance.manage"
+	PermissionTicketCheckIn   Permission = "ticket.check_in"
+)
+
+type PermissionInfo struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{2,29}$`)
+
+type RoleInput struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+func (i *RoleInput) Validate() error {
+	i.Name = strings.ToLower(strings.TrimSpace(i.Name))
+	if !roleNamePattern.MatchString(i.Name) {
+		return NewErr(ErrInput, nil, "role name must be 3-30 lowercase letters, digits or underscore")
+	}
+	i.Permissions = normalizePermissions(i.Permissions)
+	return nil
+}
+
+type RolePermissionInput struct {
+	Permissions []string `json:"permissions"`
+}
+
+func (i *RolePermissionInput) Validate() error {
+	i.Permissions = normalizePermissions(i.Permissions)
+	return nil
+}
+
+// builtin roles cannot be deleted and admin always has every permission
+func isBuiltinRole(name string) bool {
+	return name == UserRegular || name == UserAdmin
+}
+
+func normalizePermissions(permissions []string) []string {
+	res := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		permission = strings.TrimSpace(permission)
+		if permission != "" && !slices.Contains(res, permission) {
+			res = append(res, permission)
+		}
+	}
+	slices.Sort(res)
+	return res
+}
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+const (
+	plannerDefaultDays           = 7
+	plannerDefaultOpenAt         = "10:00"
+	plannerDefaultCloseAt        = "23:00"
+	plannerDefaultPrimeTimeStart = "18:00"
+	plannerDefaultPrimeTimeEnd   = "22:00"
+	plannerDefaultBufferMinutes  = 15
+	plannerDefaultSlotMinutes    = 15
+)
+
+type PlannerMovieInput struct {
+	MovieID   int64 `json:"movie_id"`
+	ShowCount int64 `json:"show_count"`
+	Price     int64 `json:"price"`
+	PrimeTime bool  `json:"prime_time"` // prefer to be played on prime time
+}
+
+type PlannerInput struct {
+	StartDate      time.Time           `json:"start_date" example:"2006-01-02T00:00:00+08:00"` // operating hours follow this timezone
+	Days           int64               `json:"days"`
+	RoomIDs        []int64             `json:"room_ids"` // empty means all usable rooms
+	OpenAt         string              `json:"open_at" example:"10:00"`
+	CloseAt        string              `json:"close_at" example:"23:00"` // before open at means after midnight
+	PrimeTimeStart string              `json:"prime_time_start" example:"18:00"`
+	PrimeTimeEnd   string              `json:"prime_time_end" example:"22:00"`
+	BufferMinutes  *int64              `json:"buffer_minutes"` // cleaning time between showtimes, 0 allow back to back
+	SlotMinutes    int64               `json:"slot_minutes"`   // showtime start granularity
+	Movies         []PlannerMovieInput `json:"movies"`
+}
+
+func (i *PlannerInput) Validate() error {
+	if i.StartDate.IsZero() {
+		return NewErr(ErrInput, nil, "start date is required")
+	}
+	y, m, d := i.StartDate.Date()
+	i.StartDate = time.Date(y, m, d, 0, 0, 0, 0, i.StartDate.Location())
+
+	if i.Days == 0 {
+		i.Days = plannerDefaultDays
+	}
+	if i.Days < 0 || i.Days > 31 {
+		return NewErr(ErrInput, nil, "days must be between 1 and 31")
+	}
+	if i.OpenAt == "" {
+		i.OpenAt = plannerDefaultOpenAt
+	}
+	if i.CloseAt == "" {
+		i.CloseAt = plannerDefaultCloseAt
+	}
+	if i.PrimeTimeStart == "" {
+		i.PrimeTimeStart = plannerDefaultPrimeTimeStart
+	}
+	if i.PrimeTimeEnd == "" {
+		i.PrimeTimeEnd = plannerDefaultPrimeTimeEnd
+	}
+	clocks := [][2]string{
+		{"open at", i.OpenAt},
+		{"close at", i.CloseAt},
+		{"prime time start", i.PrimeTimeStart},
+		{"prime time end", i.PrimeTimeEnd},
+	}
+	for _, clock := range clocks {
+		if _, err := parseClock(clock[1]); err != nil {
+			return NewErr(ErrInput, err, "%s must use HH:MM format", clock[0])
+		}
+	}
+	if i.OpenAt == i.CloseAt {
+		return NewErr(ErrInput, nil, "open at and close at cannot be equal")
+	}
+
+	if i.BufferMinutes == nil {
+		bufferMinutes := int64(plannerDefaultBufferMinutes)
+		i.BufferMinutes = &bufferMinutes
+	}
+	if *i.BufferMinutes < 0 {
+		return NewErr(ErrInput, nil, "buffer minutes minimum is 0")
+	}
+	if i.SlotMinutes == 0 {
+		i.SlotMinutes = plannerDefaultSlotMinutes
+	}
+	if i.SlotMinutes < 0 {
+		return NewErr(ErrInput, nil, "slot minutes minimum is 1")
+	}
+
+	for idx, roomID := range i.RoomIDs {
+		if roomID <= 0 {
+			return NewErr(ErrInput, nil, "room id with index %d invalid", idx)
+		}
+	}
+
+	if len(i.Movies) == 0 {
+		return NewErr(ErrInput, nil, "movies is required")
+	}
+	movieSet := map[int64]struct{}{}
+	for idx, movie := range i.Movies {
+		if movie.MovieID <= 0 {
+			return NewErr(ErrInput, nil, "movie id with index %d invalid", idx)
+		}
+		if _, ok := movieSet[movie.MovieID]; ok {
+			return NewErr(ErrInput, nil, "movie id %d is duplicated", movie.MovieID)
+		}
+		movieSet[movie.MovieID] = struct{}{}
+		if movie.ShowCount <= 0 {
+			return NewErr(ErrInput, nil, "show count with index %d minimum is 1", idx)
+		}
+		if movie.Price <= 0 {
+			return NewErr(ErrInput, nil, "price with index %d minimum is 1", idx)
+		}
+	}
+	return nil
+}
+
+func (i *PlannerInput) EndDate() time.Time {
+	return i.StartDate.AddDate(0, 0, int(i.Days))
+}
+
+func (i *PlannerInput) MovieIDs() []int64 {
+	IDs := make([]int64, 0, len(i.Movies))
+	for _, movie := range i.Movies {
+		IDs = append(IDs, movie.MovieID)
+	}
+	return IDs
+}
+
+type PlannerShowtime struct {
+	ShowtimeInput
+	MovieTitle  string `json:"movie_title"`
+	RoomName    string `json:"room_name"`
+	IsPrimeTime bool   `json:"is_prime_time"`
+}
+
+type PlannerUnscheduled struct {
+	MovieID    int64  `json:"movie_id"`
+	MovieTitle string `json:"movie_title"`
+	Requested  int64  `json:"requested"`
+	Scheduled  int64  `json:"scheduled"`
+}
+
+type PlannerProposal struct {
+	StartDate   time.Time            `json:"start_date"`
+	EndDate     time.Time            `json:"end_date"`
+	Showtimes   []PlannerShowtime    `json:"showtimes"`
+	Unscheduled []PlannerUnscheduled `json:"unscheduled"`
+}
+
+type PlannerCommitInput struct {
+	Showtimes []ShowtimeInput `json:"showtimes"`
+}
+
+func (i *PlannerCommitInput) Validate() error {
+	if len(i.Showtimes) == 0 {
+		return NewErr(ErrInput, nil, "showtimes is required")
+	}
+	for idx := range i.Showtimes {
+		err := i.Showtimes[idx].Validate()
+		if err != nil {
+			return NewErr(ErrInput, err, "showtime with index %d invalid: %s", idx, err.Error())
+		}
+	}
+	return nil
+}
+
+// PlanSchedule build a conflict free proposal from validated input.
+// The result only depends on the arguments, same input always give same proposal.
+func PlanSchedule(input PlannerInput, movies []Movie, rooms []Room, existing []Showtime, blackouts []Blackout) *PlannerProposal {
+	proposal := PlannerProposal{
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate(),
+		Showtimes:   []PlannerShowtime{},
+		Unscheduled: []PlannerUnscheduled{},
+	}
+
+	openAt, _ := parseClock(input.OpenAt)
+	closeAt, _ := parseClock(input.CloseAt)
+	if closeAt <= openAt {
+		closeAt += 24 * time.Hour
+	}
+	primeStart, _ := parseClock(input.PrimeTimeStart)
+	primeEnd, _ := parseClock(input.PrimeTimeEnd)
+	if primeEnd <= primeStart {
+		primeEnd += 24 * time.Hour
+	}
+	buffer := time.Duration(*input.BufferMinutes) * time.Minute
+	slot := time.Duration(input.SlotMinutes) * time.Minute
+
+	rooms = append([]Room{}, rooms...)
+	sort.SliceStable(rooms, func(i, j int) bool {
+		if rooms[i].Capacity != rooms[j].Capacity {
+			return rooms[i].Capacity > rooms[j].Capacity
+		}
+		return rooms[i].ID < rooms[j].ID
+	})
+
+	// map[room_id]occupied ranges, end already include the buffer
+	occupied := map[int64][]timeRange{}
+	for _, showtime := range existing {
+		occupied[showtime.RoomID] = append(occupied[showtime.RoomID], timeRange{
+			StartAt: showtime.StartAt,
+			EndAt:   showtime.EndAt.Add(buffer),
+		})
+	}
+	for _, blackout := range blackouts {
+		for _, room := range rooms {
+			if !blackout.AffectRoom(room) {
+				continue
+			}
+			occupied[room.ID] = append(occupied[room.ID], timeRange{
+				StartAt: blackout.StartAt,
+				EndAt:   blackout.EndAt,
+			})
+		}
+	}
+
+	movieMap := map[int64]Movie{}
+	for _, movie := range movies {
+		movieMap[movie.ID] = movie
+	}
+
+	demands := make([]PlannerMovieInput, 0, len(input.Movies))
+	for _, item := range input.Movies {
+		if _, ok := movieMap[item.MovieID]; ok {
+			demands = append(demands, item)
+		}
+	}
+	sort.SliceStable(demands, func(i, j int) bool {
+		a, b := demands[i], demands[j]
+		if a.PrimeTime != b.PrimeTime {
+			return a.PrimeTime
+		}
+		durationA, durationB := movieMap[a.MovieID].Duration, movieMap[b.MovieID].Duration
+		if durationA != durationB {
+			return durationA > durationB
+		}
+		return a.MovieID < b.MovieID
+	})
+
+	isPrimeTime := func(dayStart, startAt time.Time) bool {
+		return !startAt.Before(dayStart.Add(primeStart)) && startAt.Before(dayStart.Add(primeEnd))
+	}
+
+	// earliest start on the slot grid not before "from" and the movie release, and not after "latest"
+	findStart := func(roomID int64, dayStart, from, latest time.Time, movie Movie) (time.Time, bool) {
+		if from.Before(movie.ReleaseDate) {
+			from = movie.ReleaseDate
+		}
+		duration := movie.GetDuration()
+		open := dayStart.Add(openAt)
+		closedAt := dayStart.Add(closeAt)
+		startAt := open
+		if from.After(open) {
+			steps := (from.Sub(open) + slot - 1) / slot
+			startAt = open.Add(steps * slot)
+		}
+		for ; !startAt.After(latest); startAt = startAt.Add(slot) {
+			endAt := startAt.Add(duration)
+			if endAt.After(closedAt) {
+				return time.Time{}, false
+			}
+			free := true
+			for _, busy := range occupied[roomID] {
+				if isTimeOverlapping(startAt, endAt.Add(buffer), busy.StartAt, busy.EndAt) {
+					free = false
+					break
+				}
+			}
+			if free {
+				return startAt, true
+			}
+		}
+		return time.Time{}, false
+	}
+
+	type candidate struct {
+		day       int
+		roomIndex int
+		startAt   time.Time
+		dayCount  int64
+		mismatch  int
+	}
+	better := func(a, b candidate) bool {
+		if a.dayCount != b.dayCount {
+			return a.dayCount < b.dayCount
+		}
+		if a.mismatch != b.mismatch {
+			return a.mismatch < b.mismatch
+		}
+		offsetA := a.startAt.Sub(input.StartDate.AddDate(0, 0, a.day))
+		offsetB := b.startAt.Sub(input.StartDate.AddDate(0, 0, b.day))
+		if offsetA != offsetB {
+			return offsetA < offsetB
+		}
+		if a.day != b.day {
+			return a.day < b.day
+		}
+		return a.roomIndex < b.roomIndex
+	}
+
+	// map[movie_id][day]total show
+	dayCount := map[int64][]int64{}
+	scheduled := map[int64]int64{}
+	for _, demand := range demands {
+		dayCount[demand.MovieID] = make([]int64, input.Days)
+	}
+
+	// round robin so every movie get a fair share of the good slots
+	remaining := true
+	for round := int64(0); remaining; round++ {
+		remaining = false
+		for _, demand := range demands {
+			if round >= demand.ShowCount {
+				continue
+			}
+			remaining = true
+			movie := movieMap[demand.MovieID]
+			duration := movie.GetDuration()
+
+			var best *candidate
+			for day := 0; day < int(input.Days); day++ {
+				dayStart := input.StartDate.AddDate(0, 0, day)
+				for roomIndex, room := range rooms {
+					starts := []time.Time{}
+					if demand.PrimeTime {
+						if startAt, ok := findStart(room.ID, dayStart, dayStart.Add(primeStart), dayStart.Add(primeEnd-time.Nanosecond), movie); ok {
+							starts = append(starts, startAt)
+						}
+					}
+					if startAt, ok := findStart(room.ID, dayStart, dayStart, dayStart.Add(closeAt), movie); ok {
+						starts = append(starts, startAt)
+					}
+					for _, startAt := range starts {
+						mismatch := 0
+						if isPrimeTime(dayStart, startAt) != demand.PrimeTime {
+							mismatch = 1
+						}
+						cur := candidate{
+							day:       day,
+							roomIndex: roomIndex,
+							startAt:   startAt,
+							dayCount:  dayCount[movie.ID][day],
+							mismatch:  mismatch,
+						}
+						if best == nil || better(cur, *best) {
+							best = &cur
+						}
+					}
+				}
+			}
+			if best == nil {
+				continue
+			}
+
+			room := rooms[best.roomIndex]
+			endAt := best.startAt.Add(duration)
+			occupied[room.ID] = append(occupied[room.ID], timeRange{
+				StartAt: best.startAt,
+				EndAt:   endAt.Add(buffer),
+			})
+			dayCount[movie.ID][best.day]++
+			scheduled[movie.ID]++
+
+			proposal.Showtimes = append(proposal.Showtimes, PlannerShowtime{
+				ShowtimeInput: ShowtimeInput{
+					MovieID: movie.ID,
+					RoomID:  room.ID,
+					StartAt: best.startAt,
+					EndAt:   endAt,
+					Price:   demand.Price,
+				},
+				MovieTitle:  movie.Title,
+				RoomName:    room.Name,
+				IsPrimeTime: isPrimeTime(input.StartDate.AddDate(0, 0, best.day), best.startAt),
+			})
+		}
+	}
+
+	sort.SliceStable(proposal.Showtimes, func(i, j int) bool {
+		a, b := proposal.Showtimes[i], proposal.Showtimes[j]
+		if !a.StartAt.Equal(b.StartAt) {
+			return a.StartAt.Before(b.StartAt)
+		}
+		return a.RoomID < b.RoomID
+	})
+
+	for _, demand := range demands {
+		if scheduled[demand.MovieID] >= demand.ShowCount {
+			continue
+		}
+		proposal.Unscheduled = append(proposal.Unscheduled, PlannerUnscheduled{
+			MovieID:    demand.MovieID,
+			MovieTitle: movieMap[demand.MovieID].Title,
+			Requested:  demand.ShowCount,
+			Scheduled:  scheduled[demand.MovieID],
+		})
+	}
+
+	return &proposal
+}
+
+type timeRange struct {
+	StartAt time.Time `json:"start_at"`
+	EndAt   time.Time `json:"end_at"`
+}
+
+// parseClock parse HH:MM into duration since midnight
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"sort"
+	"time"
+)
+
+type PointTransactionKind string
+
+const (
+	PointEarn    PointTransactionKind = "earn"
+	PointRedeem  PointTransactionKind = "redeem"
+	PointReverse PointTransactionKind = "reverse"
+	PointExpire  PointTransactionKind = "expire"
+)
+
+type PointTransactionFilter struct {
+	UserIDs        []int64  `json:"user_ids"`
+	ReservationIDs []int64  `json:"reservation_ids"`
+	Kinds          []string `json:"kinds"`
+}
+
+// PointTransaction is a row of user points ledger,
+// earn transaction is also a lot that can be consumed until it expire
+type PointTransaction struct {
+	ID            int64                `json:"id"`
+	UserID        int64                `json:"user_id"`
+	ReservationID int64                `json:"reservation_id,omitempty"`
+	Kind          PointTransactionKind `json:"kind"`
+	Points        int64                `json:"points"`              // positive for credit, negative for debit
+	Remaining     int64                `json:"remaining,omitempty"` // unconsumed points of earn lot
+	ExpiresAt     *time.Time           `json:"expires_at,omitempty"`
+	Description   string               `json:"description"`
+	CreatedAt     time.Time            `json:"created_at"`
+}
+
+// PointAllocation is the points of a lot consumed by a redeem transaction,
+// or moved to repay an earn lot owing points
+type PointAllocation struct {
+	ID            int64 `json:"id"`
+	TransactionID int64 `json:"transaction_id"`
+	LotID         int64 `json:"lot_id"`
+	Points        int64 `json:"points"`
+}
+
+type PointSummary struct {
+	Balance      int64                       `json:"balance"`
+	Transactions *Paginate[PointTransaction] `json:"transactions"`
+}
+
+type RedeemPointInput struct {
+	Points      int64 `json:"points,omitempty" example:"100"`     // redeemed as discount
+	FreeTickets int64 `json:"free_tickets,omitempty" example:"0"` // redeemed as free ticket
+}
+
+func (i *RedeemPointInput) Validate() error {
+	if i.Points < 0 {
+		return NewErr(ErrInput, nil, "points minimum is 0")
+	}
+	if i.FreeTickets < 0 {
+		return NewErr(ErrInput, nil, "free tickets minimum is 0")
+	}
+	if i.Points == 0 && i.FreeTickets == 0 {
+		return NewErr(ErrInput, nil, "points or free tickets is required")
+	}
+	return nil
+}
+
+// EarnedPoints is the points for a paid amount
+func EarnedPoints(paid, earnUnit int64) int64 {
+	if earnUnit <= 0 || paid <= 0 {
+		return 0
+	}
+	return paid / earnUnit
+}
+
+// AllocatePoints consume the lots that expire first,
+// lots must be unexpired and have remaining points
+func AllocatePoints(lots []PointTransaction, points int64) ([]PointAllocation, error) {
+	lots = append([]PointTransaction{}, lots...)
+	sort.SliceStable(lots, func(i, j int) bool {
+		a, b := lots[i].ExpiresAt, lots[j].ExpiresAt
+		if a != nil && b != nil && !a.Equal(*b) {
+			return a.Before(*b)
+		}
+		if (a == nil) != (b == nil) {
+			return a != nil
+		}
+		return lots[i].ID < lots[j].ID
+	})
+
+	allocations := []PointAllocation{}
+	left := points
+	for _, lot := range lots {
+		if left <= 0 {
+			break
+		}
+		if lot.Remaining <= 0 {
+			continue
+		}
+		take := min(lot.Remaining, left)
+		allocations = append(allocations, PointAllocation{LotID: lot.ID, Points: take})
+		left -= take
+	}
+	if left > 0 {
+		return nil, NewErr(ErrInput, nil, "insufficient points, need %d more", left)
+	}
+	return allocations, nil
+}
+
+// FreeTicketDiscount is the price of the n most expensive tickets
+func FreeTicketDiscount(prices []int64, n int64) (int64, error) {
+	if n > int64(len(prices)) {
+		return 0, NewErr(ErrInput, nil, "free tickets exceed reserved tickets")
+	}
+	prices = append([]int64{}, prices...)
+	sort.Slice(prices, func(i, j int) bool { return prices[i] > prices[j] })
+
+	var discount int64
+	for _, price := range prices[:n] {
+		discount += price
+	}
+	return discount, nil
+}
@@ -0,0 +1,361 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+type PricingRuleKind string
+
+const (
+	PricingRuleMatinee   PricingRuleKind = "matinee"
+	PricingRuleWeekend   PricingRuleKind = "weekend"
+	PricingRuleHoliday   PricingRuleKind = "holiday"
+	PricingRuleEarlyBird PricingRuleKind = "early_bird"
+	PricingRuleOccupancy PricingRuleKind = "occupancy"
+)
+
+var pricingRuleKinds = []PricingRuleKind{
+	PricingRuleMatinee,
+	PricingRuleWeekend,
+	PricingRuleHoliday,
+	PricingRuleEarlyBird,
+	PricingRuleOccupancy,
+}
+
+func (k PricingRuleKind) Validate() error {
+	for _, kind := range pricingRuleKinds {
+		if k == kind {
+			return nil
+		}
+	}
+	return NewErr(ErrInput, nil, "pricing rule kind %s is invalid, available %v", k, pricingRuleKinds)
+}
+
+type PricingRuleFilter struct {
+	IDs      []int64  `json:"ids"`
+	Kinds    []string `json:"kinds"`
+	IsActive *bool    `json:"is_active"`
+}
+
+func (f *PricingRuleFilter) Validate() error {
+	for _, v := range f.Kinds {
+		err := PricingRuleKind(v).Validate()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type PricingRuleInput struct {
+	Name     string          `json:"name,omitempty" example:"weekday matinee"`
+	Kind     PricingRuleKind `json:"kind,omitempty" example:"matinee"`
+	Percent  int64           `json:"percent,omitempty" example:"-20"`  // adjustment in percent of base price, negative for discount
+	Amount   int64           `json:"amount,omitempty" example:"0"`     // fixed adjustment, negative for discount
+	Currency Currency        `json:"currency,omitempty" example:"IDR"` // currency of the amount, default IDR
+	Priority int64           `json:"priority,omitempty"`               // higher applied first
+	IsActive *bool           `json:"is_active,omitempty"`              // default true
+
+	// conditions, empty means match any
+	Timezone      string   `json:"timezone,omitempty" example:"Asia/Jakarta"` // used to read showtime local time, default UTC
+	Weekdays      []int64  `json:"weekdays,omitempty" example:"1,2,3,4,5"`    // 0 sunday until 6 saturday
+	StartClock    string   `json:"start_clock,omitempty" example:"10:00"`     // showtime start at or after this clock
+	EndClock      string   `json:"end_clock,omitempty" example:"17:00"`       // showtime start before this clock
+	Dates         []string `json:"dates,omitempty" example:"2024-12-25"`      // showtime local date
+	MinDaysBefore int64    `json:"min_days_before,omitempty" example:"7"`     // booked at least n days before showtime
+	MinOccupancy  int64    `json:"min_occupancy,omitempty" example:"0"`       // percent of reserved seat
+	MaxOccupancy  int64    `json:"max_occupancy,omitempty" example:"100"`     // percent of reserved seat, default 100
+}
+
+func (i *PricingRuleInput) Validate() error {
+	i.Name = strings.Trim(i.Name, " ")
+	i.Timezone = strings.Trim(i.Timezone, " ")
+	i.StartClock = strings.Trim(i.StartClock, " ")
+	i.EndClock = strings.Trim(i.EndClock, " ")
+
+	if i.Name == "" {
+		return NewErr(ErrInput, nil, "name is required")
+	}
+	err := i.Kind.Validate()
+	if err != nil {
+		return err
+	}
+	if i.Percent == 0 && i.Amount == 0 {
+		return NewErr(ErrInput, nil, "percent or amount is required")
+	}
+	if i.Percent < -100 {
+		return NewErr(ErrInput, nil, "percent minimum is -100")
+	}
+	currency, err := NormalizeCurrency(i.Currency)
+	if err != nil {
+		return err
+	}
+	i.Currency = currency
+
+	if i.IsActive == nil {
+		active := true
+		i.IsActive = &active
+	}
+	if i.Timezone == "" {
+		i.Timezone = "UTC"
+	}
+	if _, err := time.LoadLocation(i.Timezone); err != nil {
+		return NewErr(ErrInput, err, "timezone is invalid")
+	}
+	if i.Weekdays == nil {
+		i.Weekdays = []int64{}
+	}
+	if i.Dates == nil {
+		i.Dates = []string{}
+	}
+	for _, weekday := range i.Weekdays {
+		if weekday < int64(time.Sunday) || weekday > int64(time.Saturday) {
+			return NewErr(ErrInput, nil, "weekday must be between 0 (sunday) and 6 (saturday)")
+		}
+	}
+	if i.StartClock != "" {
+		if _, err := parseClock(i.StartClock); err != nil {
+			return NewErr(ErrInput, err, "start clock must be in HH:MM format")
+		}
+	}
+	if i.EndClock != "" {
+		if _, err := parseClock(i.EndClock); err != nil {
+			return NewErr(ErrInput, err, "end clock must be in HH:MM format")
+		}
+	}
+	for _, date := range i.Dates {
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			return NewErr(ErrInput, err, "date %s must be in YYYY-MM-DD format", date)
+		}
+	}
+	if i.MinDaysBefore < 0 {
+		return NewErr(ErrInput, nil, "min days before minimum is 0")
+	}
+	if i.MaxOccupancy == 0 {
+		i.MaxOccupancy = 100
+	}
+	if i.MinOccupancy < 0 || i.MaxOccupancy > 100 || i.MinOccupancy > i.MaxOccupancy {
+		return NewErr(ErrInput, nil, "occupancy band must be within 0 until 100")
+	}
+
+	// each kind need its own condition to be meaningful
+	switch i.Kind {
+	case PricingRuleMatinee:
+		if len(i.Weekdays) == 0 {
+			i.Weekdays = []int64{1, 2, 3, 4, 5}
+		}
+		if i.EndClock == "" {
+			return NewErr(ErrInput, nil, "matinee rule require end clock")
+		}
+	case PricingRuleWeekend:
+		if len(i.Weekdays) == 0 {
+			i.Weekdays = []int64{0, 6}
+		}
+	case PricingRuleHoliday:
+		if len(i.Dates) == 0 {
+			return NewErr(ErrInput, nil, "holiday rule require dates")
+		}
+	case PricingRuleEarlyBird:
+		if i.MinDaysBefore <= 0 {
+			return NewErr(ErrInput, nil, "early bird rule require min days before")
+		}
+	case PricingRuleOccupancy:
+		if i.MinOccupancy == 0 && i.MaxOccupancy == 100 {
+			return NewErr(ErrInput, nil, "occupancy rule require occupancy band")
+		}
+	}
+	return nil
+}
+
+func NewPricingRule(input PricingRuleInput) (*PricingRule, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	rule := PricingRule{
+		Name:          input.Name,
+		Kind:          input.Kind,
+		Percent:       input.Percent,
+		Amount:        input.Amount,
+		Currency:      input.Currency,
+		Priority:      input.Priority,
+		IsActive:      *input.IsActive,
+		Timezone:      input.Timezone,
+		Weekdays:      input.Weekdays,
+		StartClock:    input.StartClock,
+		EndClock:      input.EndClock,
+		Dates:         input.Dates,
+		MinDaysBefore: input.MinDaysBefore,
+		MinOccupancy:  input.MinOccupancy,
+		MaxOccupancy:  input.MaxOccupancy,
+	}
+	return &rule, nil
+}
+
+type PricingRule struct {
+	ID            int64           `json:"id"`
+	Name          string          `json:"name"`
+	Kind          PricingRuleKind `json:"kind"`
+	Percent       int64           `json:"percent"`
+	Amount        int64           `json:"amount"`
+	Currency      Currency        `json:"currency"`
+	Priority      int64           `json:"priority"`
+	IsActive      bool            `json:"is_active"`
+	Timezone      string          `json:"timezone"`
+	Weekdays      []int64         `json:"weekdays"`
+	StartClock    string          `json:"start_clock"`
+	EndClock      string          `json:"end_clock"`
+	Dates         []string        `json:"dates"`
+	MinDaysBefore int64           `json:"min_days_before"`
+	MinOccupancy  int64           `json:"min_occupancy"`
+	MaxOccupancy  int64           `json:"max_occupancy"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+}
+
+// PricingContext is the situation a seat price is computed in
+type PricingContext struct {
+	ShowtimeStart time.Time
+	Now           time.Time
+	Occupancy     int64 // percent of reserved seat
+}
+
+// Match report whether every condition of the rule hold
+func (r *PricingRule) Match(pc PricingContext) bool {
+	location, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := pc.ShowtimeStart.In(location)
+
+	if len(r.Weekdays) > 0 {
+		found := false
+		for _, weekday := range r.Weekdays {
+			if time.Weekday(weekday) == local.Weekday() {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	clock := time.Duration(local.Hour())*time.Hour + time.Duration(local.Minute())*time.Minute
+	if r.StartClock != "" {
+		startClock, err := parseClock(r.StartClock)
+		if err != nil || clock < startClock {
+			return false
+		}
+	}
+	if r.EndClock != "" {
+		endClock, err := parseClock(r.EndClock)
+		if err != nil || clock >= endClock {
+			return false
+		}
+	}
+
+	if len(r.Dates) > 0 {
+		found := false
+		for _, date := range r.Dates {
+			if date == local.Format(time.DateOnly) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if r.MinDaysBefore > 0 && pc.ShowtimeStart.Sub(pc.Now) < time.Duration(r.MinDaysBefore)*24*time.Hour {
+		return false
+	}
+
+	if pc.Occupancy < r.MinOccupancy || pc.Occupancy > r.MaxOccupancy {
+		return false
+	}
+	return true
+}
+
+// AppliesTo report whether the rule can adjust a price in the currency,
+// fixed amount only has meaning in its own currency while percent apply to any
+func (r *PricingRule) AppliesTo(currency Currency) bool {
+	return r.Amount == 0 || r.Currency == currency
+}
+
+// Adjustment of the base price when this rule applied
+func (r *PricingRule) Adjustment(basePrice int64) (int64, error) {
+	percent, err := MulAmount(basePrice, r.Percent)
+	if err != nil {
+		return 0, err
+	}
+	return AddAmount(percent/100, r.Amount)
+}
+
+type AppliedPricingRule struct {
+	RuleID     int64           `json:"rule_id"`
+	Name       string          `json:"name"`
+	Kind       PricingRuleKind `json:"kind"`
+	Percent    int64           `json:"percent"`
+	Amount     int64           `json:"amount"`
+	Adjustment int64           `json:"adjustment"`
+}
+
+// PriceSnapshot explain how a seat price is computed
+type PriceSnapshot struct {
+	BasePrice   int64                `json:"base_price"`
+	Price       int64                `json:"price"`
+	Occupancy   int64                `json:"occupancy"`
+	Rules       []AppliedPricingRule `json:"rules"`
+	TicketType  *AppliedTicketType   `json:"ticket_type,omitempty"`
+	EvaluatedAt time.Time            `json:"evaluated_at"`
+}
+
+// ApplyPricingRules compute effective price from the base price in the currency,
+// every matched rule adjust the base price and the result never goes below 0.
+// Rule with fixed amount of other currency is skipped, the same way fee for every cinema is
+func ApplyPricingRules(basePrice int64, currency Currency, pc PricingContext, rules []PricingRule) (PriceSnapshot, error) {
+	rules = append([]PricingRule{}, rules...)
+	sort.SliceStable(rules, func(i, j int) bool {
+		if rules[i].Priority != rules[j].Priority {
+			return rules[i].Priority > rules[j].Priority
+		}
+		return rules[i].ID < rules[j].ID
+	})
+
+	snapshot := PriceSnapshot{
+		BasePrice:   basePrice,
+		Price:       basePrice,
+		Occupancy:   pc.Occupancy,
+		Rules:       []AppliedPricingRule{},
+		EvaluatedAt: pc.Now,
+	}
+	for _, rule := range rules {
+		if !rule.IsActive || !rule.AppliesTo(currency) || !rule.Match(pc) {
+			continue
+		}
+		adjustment, err := rule.Adjustment(basePrice)
+		if err != nil {
+			return PriceSnapshot{}, err
+		}
+		snapshot.Price, err = AddAmount(snapshot.Price, adjustment)
+		if err != nil {
+			return PriceSnapshot{}, err
+		}
+		snapshot.Rules = append(snapshot.Rules, AppliedPricingRule{
+			RuleID:     rule.ID,
+			Name:       rule.Name,
+			Kind:       rule.Kind,
+			Percent:    rule.Percent,
+			Amount:     rule.Amount,
+			Adjustment: adjustment,
+		})
+	}
+	if snapshot.Price < 0 {
+		snapshot.Price = 0
+	}
+	return snapshot, nil
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// profile
+
+const birthDateLayout = "2006-01-02"
+
+var phonePattern = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+
+type UserProfileInput struct {
+	DisplayName       string `json:"display_name,omitempty"`
+	Phone             string `json:"phone,omitempty" example:"+6281234567890"` // E.164 format, spaces and dashes are removed
+	BirthDate         string `json:"birth_date,omitempty" example:"2000-01-31"`
+	PreferredCinemaID int64  `json:"preferred_cinema_id,omitempty"`   // default cinema of showtime listing
+	Language          string `json:"language,omitempty" example:"en"` // ISO 639 code
+	MarketingConsent  bool   `json:"marketing_consent,omitempty"`
+}
+
+func (i *UserProfileInput) Validate() error {
+	i.DisplayName = strings.Trim(i.DisplayName, " ")
+	maxName := 50
+	if utf8.RuneCountInString(i.DisplayName) > maxName {
+		return NewErr(ErrInput, nil, "display name maximum %d characters", maxName)
+	}
+
+	i.Phone = strings.NewReplacer(" ", "", "-", "", "(", "", ")", "").Replace(i.Phone)
+	if i.Phone != "" && !phonePattern.MatchString(i.Phone) {
+		return NewErr(ErrInput, nil, "phone must be in international format, e.g. +6281234567890")
+	}
+
+	i.BirthDate = strings.Trim(i.BirthDate, " ")
+	if i.BirthDate != "" {
+		birthDate, err := time.Parse(birthDateLayout, i.BirthDate)
+		if err != nil {
+			return NewErr(ErrInput, err, "birth date must be in YYYY-MM-DD format")
+		}
+		now := time.Now()
+		if birthDate.After(now) || birthDate.Before(now.AddDate(-130, 0, 0)) {
+			return NewErr(ErrInput, nil, "birth date is invalid")
+		}
+	}
+
+	if i.PreferredCinemaID < 0 {
+		return NewErr(ErrInput, nil, "preferred cinema id is invalid")
+	}
+
+	var err error
+	i.Language, err = normalizeLanguage(i.Language)
+	if err != nil {
+		return NewErr(ErrInput, err, "language is invalid")
+	}
+	return nil
+}
+
+func NewUserProfile(userID int64, input UserProfileInput) (*UserProfile, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	profile := UserProfile{
+		UserID:           userID,
+		DisplayName:      input.DisplayName,
+		Phone:            input.Phone,
+		BirthDate:        input.BirthDate,
+		Language:         input.Language,
+		MarketingConsent: input.MarketingConsent,
+		UpdatedAt:        time.Now(),
+	}
+	if input.PreferredCinemaID > 0 {
+		profile.PreferredCinemaID = &input.PreferredCinemaID
+	}
+	return &profile, nil
+}
+
+type UserProfile struct {
+	UserID             int64      `json:"user_id"`
+	DisplayName        string     `json:"display_name"`
+	Phone              string     `json:"phone"`
+	BirthDate          string     `json:"birth_date" example:"2000-01-31"`
+	PreferredCinemaID  *int64     `json:"preferred_cinema_id"`
+	Language           string     `json:"language"`
+	MarketingConsent   bool       `json:"marketing_consent"`
+	MarketingConsentAt *time.Time `json:"marketing_consent_at"` // last time the consent was given
+	UpdatedAt          time.Time  `json:"updated_at"`
+}
+
+// Age in full years at the time, false when birth date is not set
+func (p *UserProfile) Age(at time.Time) (int64, bool) {
+	birthDate, err := time.Parse(birthDateLayout, p.BirthDate)
+	if err != nil {
+		return 0, false
+	}
+	age := int64(at.Year() - birthDate.Year())
+	if at.Month() < birthDate.Month() || (at.Month() == birthDate.Month() && at.Day() < birthDate.Day()) {
+		age--
+	}
+	return age, true
+}
+
+// CheckAge refuse the movie when the user is younger than the age rating or the birth date is not set
+func (p *UserProfile) CheckAge(movie *Movie, at time.Time) error {
+	if movie.MinAge <= 0 {
+		return nil
+	}
+	age, ok := p.Age(at)
+	if !ok {
+		return NewErr(ErrInput, nil, "movie %s is rated %d+, set birth date in your profile first", movie.Title, movie.MinAge)
+	}
+	if age < movie.MinAge {
+		return NewErr(ErrInput, nil, "movie %s is rated %d+", movie.Title, movie.MinAge)
+	}
+	return nil
+}
+
+// Changes of every field from this profile to the next one
+func (p *UserProfile) Changes(next *UserProfile) []UserProfileChange {
+	cinemaID := func(ID *int64) string {
+		if ID == nil {
+			return ""
+		}
+		return strconv.FormatInt(*ID, 10)
+	}
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"display_name", p.DisplayName, next.DisplayName},
+		{"phone", p.Phone, next.Phone},
+		{"birth_date", p.BirthDate, next.BirthDate},
+		{"preferred_cinema_id", cinemaID(p.PreferredCinemaID), cinemaID(next.PreferredCinemaID)},
+		{"language", p.Language, next.Language},
+		{"marketing_consent", strconv.FormatBool(p.MarketingConsent), strconv.FormatBool(next.MarketingConsent)},
+	}
+	var changes []UserProfileChange
+	for _, field := range fields {
+		if field.before == field.after {
+			continue
+		}
+		changes = append(changes, UserProfileChange{
+			UserID:    p.UserID,
+			Field:     field.name,
+			OldValue:  field.before,
+			NewValue:  field.after,
+			CreatedAt: next.UpdatedAt,
+		})
+	}
+	return changes
+}
+
+type UserProfileChange struct {
+	ID        int64     `json:"id"`
+	UserID    int64     `json:"user_id"`
+	Field     string    `json:"field"`
+	OldValue  string    `json:"old_value"`
+	NewValue  string    `json:"new_value"`
+	CreatedAt time.Time `json:"created_at"`
+}
@@ -0,0 +1,294 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type PromoDiscountType string
+
+const (
+	PromoDiscountPercent PromoDiscountType = "percent"
+	PromoDiscountAmount  PromoDiscountType = "amount"
+)
+
+func (t PromoDiscountType) Validate() error {
+	if t == PromoDiscountPercent || t == PromoDiscountAmount {
+		return nil
+	}
+	return NewErr(ErrInput, nil, "discount type %s is invalid, available %v", t, []PromoDiscountType{PromoDiscountPercent, PromoDiscountAmount})
+}
+
+type PromoCodeFilter struct {
+	IDs      []int64  `json:"ids"`
+	Codes    []string `json:"codes"`
+	IsActive *bool    `json:"is_active"`
+}
+
+func (f *PromoCodeFilter) Validate() error {
+	for i, v := range f.Codes {
+		f.Codes[i] = normalizePromoCode(v)
+	}
+	return nil
+}
+
+func normalizePromoCode(code string) string {
+	return strings.ToUpper(strings.Trim(code, " "))
+}
+
+type PromoCodeInput struct {
+	Code          string            `json:"code,omitempty" example:"HOLIDAY10"`
+	Description   string            `json:"description,omitempty"`
+	DiscountType  PromoDiscountType `json:"discount_type,omitempty" example:"percent"`
+	DiscountValue int64             `json:"discount_value,omitempty" example:"10"` // percent or amount depend on discount type
+	MaxDiscount   int64             `json:"max_discount,omitempty" example:"0"`    // cap for percent discount, 0 means no cap
+	MinTotalPrice int64             `json:"min_total_price,omitempty" example:"0"` // reservation total before discount
+	Currency      Currency          `json:"currency,omitempty" example:"IDR"`      // currency of every amount, only reservation of this currency can use it, default IDR
+	StartAt       *time.Time        `json:"start_at,omitempty"`                    // empty means valid since created
+	EndAt         *time.Time        `json:"end_at,omitempty"`                      // empty means never expire
+	UsageLimit    int64             `json:"usage_limit,omitempty" example:"100"`   // 0 means unlimited
+	PerUserLimit  int64             `json:"per_user_limit,omitempty" example:"1"`  // 0 means unlimited
+	IsActive      *bool             `json:"is_active,omitempty"`                   // default true
+
+	// restrictions, empty means any
+	MovieIDs       []int64  `json:"movie_ids,omitempty"`
+	GenreIDs       []int64  `json:"genre_ids,omitempty"`
+	ShowtimeIDs    []int64  `json:"showtime_ids,omitempty"`
+	SeatCategories []string `json:"seat_categories,omitempty" example:"vip"`
+}
+
+func (i *PromoCodeInput) Validate() error {
+	i.Code = normalizePromoCode(i.Code)
+	i.Description = strings.Trim(i.Description, " ")
+
+	if i.Code == "" {
+		return NewErr(ErrInput, nil, "code is required")
+	}
+	err := i.DiscountType.Validate()
+	if err != nil {
+		return err
+	}
+	if i.DiscountValue <= 0 {
+		return NewErr(ErrInput, nil, "discount value minimum is 1")
+	}
+	if i.DiscountType == PromoDiscountPercent && i.DiscountValue > 100 {
+		return NewErr(ErrInput, nil, "discount percent maximum is 100")
+	}
+	if i.MaxDiscount < 0 {
+		return NewErr(ErrInput, nil, "max discount minimum is 0")
+	}
+	if i.MinTotalPrice < 0 {
+		return NewErr(ErrInput, nil, "min total price minimum is 0")
+	}
+	currency, err := NormalizeCurrency(i.Currency)
+	if err != nil {
+		return err
+	}
+	i.Currency = currency
+	if i.StartAt != nil && i.EndAt != nil && !i.EndAt.After(*i.StartAt) {
+		return NewErr(ErrInput, nil, "end at must be after start at")
+	}
+	if i.UsageLimit < 0 {
+		return NewErr(ErrInput, nil, "usage limit minimum is 0")
+	}
+	if i.PerUserLimit < 0 {
+		return NewErr(ErrInput, nil, "per user limit minimum is 0")
+	}
+	if i.IsActive == nil {
+		active := true
+		i.IsActive = &active
+	}
+
+	if i.MovieIDs == nil {
+		i.MovieIDs = []int64{}
+	}
+	if i.GenreIDs == nil {
+		i.GenreIDs = []int64{}
+	}
+	if i.ShowtimeIDs == nil {
+		i.ShowtimeIDs = []int64{}
+	}
+	if i.SeatCategories == nil {
+		i.SeatCategories = []string{}
+	}
+	for idx, category := range i.SeatCategories {
+		i.SeatCategories[idx] = strings.ToLower(strings.Trim(category, " "))
+		if i.SeatCategories[idx] == "" {
+			return NewErr(ErrInput, nil, "seat category with index %d is empty", idx)
+		}
+	}
+	return nil
+}
+
+func NewPromoCode(input PromoCodeInput) (*PromoCode, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	promoCode := PromoCode{
+		Code:           input.Code,
+		Description:    input.Description,
+		DiscountType:   input.DiscountType,
+		DiscountValue:  input.DiscountValue,
+		MaxDiscount:    input.MaxDiscount,
+		MinTotalPrice:  input.MinTotalPrice,
+		Currency:       input.Currency,
+		StartAt:        input.StartAt,
+		EndAt:          input.EndAt,
+		UsageLimit:     input.UsageLimit,
+		PerUserLimit:   input.PerUserLimit,
+		IsActive:       *input.IsActive,
+		MovieIDs:       input.MovieIDs,
+		GenreIDs:       input.GenreIDs,
+		ShowtimeIDs:    input.ShowtimeIDs,
+		SeatCategories: input.SeatCategories,
+	}
+	return &promoCode, nil
+}
+
+type PromoCode struct {
+	ID             int64             `json:"id"`
+	Code           string            `json:"code"`
+	Description    string            `json:"description"`
+	DiscountType   PromoDiscountType `json:"discount_type"`
+	DiscountValue  int64             `json:"discount_value"`
+	MaxDiscount    int64             `json:"max_discount"`
+	MinTotalPrice  int64             `json:"min_total_price"`
+	Currency       Currency          `json:"currency"`
+	StartAt        *time.Time        `json:"start_at"`
+	EndAt          *time.Time        `json:"end_at"`
+	UsageLimit     int64             `json:"usage_limit"`
+	PerUserLimit   int64             `json:"per_user_limit"`
+	UsedCount      int64             `json:"used_count"`
+	IsActive       bool              `json:"is_active"`
+	MovieIDs       []int64           `json:"movie_ids"`
+	GenreIDs       []int64           `json:"genre_ids"`
+	ShowtimeIDs    []int64           `json:"showtime_ids"`
+	SeatCategories []string          `json:"seat_categories"`
+	CreatedAt      time.Time         `json:"created_at"`
+	UpdatedAt      time.Time         `json:"updated_at"`
+}
+
+// PromoLine is a reserved seat checked against promo code restrictions
+type PromoLine struct {
+	MovieID      int64
+	GenreIDs     []int64
+	ShowtimeID   int64
+	SeatCategory string
+	Price        int64
+}
+
+// ValidateAt make sure the promo code can be used at the given time
+func (p *PromoCode) ValidateAt(now time.Time) error {
+	if !p.IsActive {
+		return NewErr(ErrInput, nil, "promo code is not active")
+	}
+	if p.StartAt != nil && now.Before(*p.StartAt) {
+		return NewErr(ErrInput, nil, "promo code is not valid yet")
+	}
+	if p.EndAt != nil && !now.Before(*p.EndAt) {
+		return NewErr(ErrInput, nil, "promo code is expired")
+	}
+	return nil
+}
+
+// Eligible report whether the reserved seat fulfill every restriction
+func (p *PromoCode) Eligible(line PromoLine) bool {
+	if len(p.MovieIDs) > 0 && !containsInt64(p.MovieIDs, line.MovieID) {
+		return false
+	}
+	if len(p.ShowtimeIDs) > 0 && !containsInt64(p.ShowtimeIDs, line.ShowtimeID) {
+		return false
+	}
+	if len(p.GenreIDs) > 0 {
+		found := false
+		for _, genreID := range line.GenreIDs {
+			if containsInt64(p.GenreIDs, genreID) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if len(p.SeatCategories) > 0 {
+		found := false
+		for _, category := range p.SeatCategories {
+			if category == line.SeatCategory {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Discount compute the discount for a reservation in the currency,
+// only eligible seats are discounted and the discount never exceed their price
+func (p *PromoCode) Discount(currency Currency, totalPrice int64, lines []PromoLine) (int64, error) {
+	if p.Currency != currency {
+		return 0, NewErr(ErrInput, nil, "promo code is for %s, cannot be used in %s", p.Currency, currency)
+	}
+	if totalPrice < p.MinTotalPrice {
+		return 0, NewErr(ErrInput, nil, "promo code require minimum total price %d", p.MinTotalPrice)
+	}
+
+	var eligiblePrice int64
+	for _, line := range lines {
+		if p.Eligible(line) {
+			eligiblePrice += line.Price
+		}
+	}
+	if eligiblePrice == 0 {
+		return 0, NewErr(ErrInput, nil, "promo code is not applicable to this reservation")
+	}
+
+	var discount int64
+	switch p.DiscountType {
+	case PromoDiscountPercent:
+		discount = eligiblePrice * p.DiscountValue / 100
+		if p.MaxDiscount > 0 && discount > p.MaxDiscount {
+			discount = p.MaxDiscount
+		}
+	case PromoDiscountAmount:
+		discount = p.DiscountValue
+	}
+	if discount > eligiblePrice {
+		discount = eligiblePrice
+	}
+	return discount, nil
+}
+
+func containsInt64(items []int64, v int64) bool {
+	for _, item := range items {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
+
+type ApplyPromoCodeInput struct {
+	Code string `json:"code" example:"HOLIDAY10"`
+}
+
+func (i *ApplyPromoCodeInput) Validate() error {
+	i.Code = normalizePromoCode(i.Code)
+	if i.Code == "" {
+		return NewErr(ErrInput, nil, "code is required")
+	}
+	return nil
+}
+
+type PromoCodeUsage struct {
+	ID            int64     `json:"id"`
+	PromoCodeID   int64     `json:"promo_code_id"`
+	UserID        int64     `json:"user_id"`
+	ReservationID int64     `json:"reservation_id"`
+	Discount      int64     `json:"discount"`
+	CreatedAt     time.Time `json:"created_at"`
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"time"
+)
+
+type ReceiptFormat string
+
+const (
+	ReceiptJSON ReceiptFormat = "json"
+	ReceiptText ReceiptFormat = "text"
+	ReceiptHTML ReceiptFormat = "html"
+)
+
+type ReceiptLine struct {
+	Description string `json:"description"`
+	TicketType  string `json:"ticket_type,omitempty"`
+	Amount      int64  `json:"amount"`
+}
+
+type Receipt struct {
+	Number           string            `json:"number"`
+	ReservationID    int64             `json:"reservation_id"`
+	Status           ReservationStatus `json:"status"`
+	IssuedAt         time.Time         `json:"issued_at"`
+	Customer         string            `json:"customer"`
+	Cinema           string            `json:"cinema,omitempty"`
+	CinemaAddress    string            `json:"cinema_address,omitempty"`
+	Lines            []ReceiptLine     `json:"lines"`
+	Subtotal         int64             `json:"subtotal"`
+	PromoCode        string            `json:"promo_code,omitempty"`
+	Discount         int64             `json:"discount"`
+	PointsDiscount   int64             `json:"points_discount"`
+	Fees             []ReservationFee  `json:"fees"`
+	FeeTotal         int64             `json:"fee_total"`
+	TaxRate          int64             `json:"tax_rate"` // basis points
+	Tax              int64             `json:"tax"`
+	Total            int64             `json:"total"`
+	Currency         Currency          `json:"currency"`
+	PaidWallet       int64             `json:"paid_wallet"`
+	PaidGateway      int64             `json:"paid_gateway"`
+	PaymentReference string            `json:"payment_reference,omitempty"`
+}
+
+// TaxPercent is the tax rate for display, e.g. 11.00%
+func (r *Receipt) TaxPercent() string {
+	return fmt.Sprintf("%d.%02d%%", r.TaxRate/100, r.TaxRate%100)
+}
+
+// FormatReceiptNumber prefix the number with its series, number is only unique within the series
+func FormatReceiptNumber(series, number int64) string {
+	return fmt.Sprintf("R%04d-%08d", series, number)
+}
+
+func NewReceipt(reservation *Reservation, user *User, cinema *Cinema) (*Receipt, error) {
+	if reservation.ReceiptNumber <= 0 || reservation.ReceiptIssuedAt == nil {
+		return nil, NewErr(ErrInput, nil, "receipt is only available for paid reservation")
+	}
+
+	lines := make([]ReceiptLine, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		line := ReceiptLine{
+			Description: fmt.Sprintf("%s, %s, %s seat %s", item.Movie, item.ShowtimeStart.Format(time.DateTime), item.Room, item.Seat),
+			Amount:      item.TotalPrice,
+		}
+		if item.Pricing != nil && item.Pricing.TicketType != nil {
+			line.TicketType = item.Pricing.TicketType.Name
+		}
+		lines = append(lines, line)
+	}
+	fees := reservation.FeeItems
+	if fees == nil {
+		fees = []ReservationFee{}
+	}
+
+	receipt := Receipt{
+		Number:           FormatReceiptNumber(reservation.ReceiptSeries, reservation.ReceiptNumber),
+		ReservationID:    reservation.ID,
+		Status:           reservation.Status,
+		IssuedAt:         *reservation.ReceiptIssuedAt,
+		Customer:         user.Email,
+		Cinema:           cinema.Name,
+		CinemaAddress:    cinema.Address,
+		Lines:            lines,
+		Subtotal:         reservation.SubtotalPrice,
+		PromoCode:        reservation.PromoCode,
+		Discount:         reservation.Discount,
+		PointsDiscount:   reservation.PointsDiscount,
+		Fees:             fees,
+		FeeTotal:         reservation.Fees,
+		TaxRate:          reservation.TaxRate,
+		Tax:              reservation.Tax,
+		Total:            reservation.TotalPrice,
+		Currency:         reservation.Currency,
+		PaidWallet:       reservation.PaidWallet,
+		PaidGateway:      reservation.PaidGateway,
+		PaymentReference: reservation.PaymentReference,
+	}
+	return &receipt, nil
+}
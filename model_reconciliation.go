@@ -0,0 +1,74 @@
+package main
+
+import "time"
+
+// ReservationLedger is the receivable movement of a reservation summed per journal kind
+type ReservationLedger struct {
+	ReservationID int64
+	Status        ReservationStatus
+	TotalPrice    int64
+	Sold          int64 // receivable from sale minus sale reversal
+	Collected     int64 // receivable settled by payment
+	Refunded      int64 // payment given back by refund
+}
+
+// Issues list every disagreement between the reservation status and its ledger
+func (l *ReservationLedger) Issues() []string {
+	issues := []string{}
+	switch l.Status {
+	case ReservationUnpaid:
+		if l.Sold != 0 {
+			issues = append(issues, "unpaid reservation has recorded sale")
+		}
+		if l.Collected != 0 {
+			issues = append(issues, "unpaid reservation has captured payment")
+		}
+	case ReservationPaid:
+		if l.Sold != l.TotalPrice {
+			issues = append(issues, "recorded sale differs from total price")
+		}
+		if l.Collected == 0 && l.TotalPrice > 0 {
+			issues = append(issues, "paid reservation has no captured payment")
+		} else if l.Collected != l.TotalPrice {
+			issues = append(issues, "captured payment differs from total price")
+		}
+		if l.Refunded != 0 {
+			issues = append(issues, "paid reservation has refund")
+		}
+	case ReservationCancelled:
+		if l.Sold != 0 {
+			issues = append(issues, "cancelled reservation sale is not reversed")
+		}
+		if l.Collected != l.Refunded {
+			issues = append(issues, "cancelled reservation payment is not fully refunded")
+		}
+	}
+	return issues
+}
+
+type ReconciliationIssueFilter struct {
+	ReservationIDs []int64  `json:"reservation_ids"`
+	Statuses       []string `json:"statuses"`
+}
+
+func (f *ReconciliationIssueFilter) Validate() error {
+	for i, v := range f.Statuses {
+		if !isReservationStatusValid(ReservationStatus(v)) {
+			return NewErr(ErrInput, nil, "status with index %d invalid", i)
+		}
+	}
+	return nil
+}
+
+type ReconciliationIssue struct {
+	ID            int64             `json:"id"`
+	ReservationID int64             `json:"reservation_id"`
+	Status        ReservationStatus `json:"status"`
+	Issue         string            `json:"issue"`
+	TotalPrice    int64             `json:"total_price"`
+	Sold          int64             `json:"sold"`
+	Collected     int64             `json:"collected"`
+	Refunded      int64             `json:"refunded"`
+	DetectedOn    time.Time         `json:"detected_on"`
+	CreatedAt     time.Time         `json:"created_at"`
+}
@@ -75,17 +75,40 @@ func NewReservation(input ReservationInput) (*Reservation, error) {
 }
 
 type Reservation struct {
-	ID         int64             `json:"id,omitempty"`
-	UserID     int64             `json:"user_id,omitempty"`
-	Status     ReservationStatus `json:"status,omitempty"`
-	TotalPrice int64             `json:"total_price,omitempty"`
-	CreatedAt  time.Time         `json:"created_at,omitempty"`
-	UpdatedAt  time.Time         `json:"updated_at,omitempty"`
+	ID               int64             `json:"id,omitempty"`
+	UserID           int64             `json:"user_id,omitempty"`
+	Status           ReservationStatus `json:"status,omitempty"`
+	SubtotalPrice    int64             `json:"subtotal_price,omitempty"` // total price before discount
+	Discount         int64             `json:"discount,omitempty"`
+	Fees             int64             `json:"fees,omitempty"`
+	TaxRate          int64             `json:"tax_rate,omitempty"` // basis points of the cinema when reserved
+	Tax              int64             `json:"tax,omitempty"`
+	TotalPrice       int64             `json:"total_price,omitempty"` // price to pay after discount and redeemed points, with fees and tax
+	Currency         Currency          `json:"currency,omitempty"`    // every amount is in minor unit of this currency
+	PromoCodeID      int64             `json:"promo_code_id,omitempty"`
+	PromoCode        string            `json:"promo_code,omitempty"`
+	PointsRedeemed   int64             `json:"points_redeemed,omitempty"`
+	PointsDiscount   int64             `json:"points_discount,omitempty"`
+	PaidWallet       int64             `json:"paid_wallet,omitempty"`
+	PaidGateway      int64             `json:"paid_gateway,omitempty"`
+	PaymentReference string            `json:"payment_reference,omitempty"`
+	CinemaID         int64             `json:"cinema_id,omitempty"`
+	FeeItems         []ReservationFee  `json:"fee_items,omitempty"`
+	ReceiptSeries    int64             `json:"receipt_series,omitempty"` // cinema of the receipt number
+	ReceiptNumber    int64             `json:"receipt_number,omitempty"`
+	ReceiptIssuedAt  *time.Time        `json:"receipt_issued_at,omitempty"`
+	CreatedAt        time.Time         `json:"created_at,omitempty"`
+	UpdatedAt        time.Time         `json:"updated_at,omitempty"`
 
 	// relation
 	Items []ReservationItem `json:"reservation_items,omitempty"`
 }
 
+// Total is the price to pay in the reservation currency
+func (r *Reservation) Total() Money {
+	return NewMoney(r.TotalPrice, r.Currency)
+}
+
 type ReservationItemFilter struct {
 	IDs            []int64 `json:"ids,omitempty"`
 	UserIDs        []int64 `json:"user_ids,omitempty"`
@@ -105,6 +128,9 @@ type ReservationItemInput struct {
 	ShowtimeID    int64 `json:"showtime_id,omitempty"`
 	TotalPrice    int64 `json:"total_price,omitempty"`
 	SeatID        int64 `json:"seat_id,omitempty"`
+
+	TicketTypeID int64          `json:"ticket_type_id,omitempty"`
+	Pricing      *PriceSnapshot `json:"pricing,omitempty"`
 }
 
 func (i *ReservationItemInput) Validate() error {
@@ -122,6 +148,8 @@ func NewReservationItem(input ReservationItemInput) (*ReservationItem, error) {
 		ShowtimeID:    input.ShowtimeID,
 		SeatID:        input.SeatID,
 		TotalPrice:    input.TotalPrice,
+		TicketTypeID:  input.TicketTypeID,
+		Pricing:       input.Pricing,
 	}
 	return &item, nil
 }
@@ -136,6 +164,9 @@ type ReservationItem struct {
 	CreatedAt     time.Time `json:"created_at,omitempty"`
 	UpdatedAt     time.Time `json:"updated_at,omitempty"`
 
+	TicketTypeID int64          `json:"ticket_type_id,omitempty"`
+	Pricing      *PriceSnapshot `json:"pricing,omitempty"` // price breakdown when reserved
+
 	// relation
 	Movie         string    `json:"movie"`
 	ShowtimeStart time.Time `json:"showtime_start"`
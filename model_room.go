@@ -6,9 +6,10 @@ import (
 )
 
 type RoomFilter struct {
-	IDs      []int64  `json:"ids"`
-	Names    []string `json:"names"`
-	IsUsable *bool    `json:"is_usable"`
+	IDs       []int64  `json:"ids"`
+	Names     []string `json:"names"`
+	CinemaIDs []int64  `json:"cinema_ids"`
+	IsUsable  *bool    `json:"is_usable"`
 }
 
 func (f *RoomFilter) Validate() error {
@@ -23,7 +24,8 @@ func (f *RoomFilter) Validate() error {
 }
 
 type RoomInput struct {
-	Name string `json:"name,omitempty"`
+	Name     string `json:"name,omitempty"`
+	CinemaID int64  `json:"cinema_id,omitempty"` // optional
 }
 
 func (i *RoomInput) Validate() error {
@@ -31,12 +33,16 @@ func (i *RoomInput) Validate() error {
 	if i.Name == "" {
 		return NewErr(ErrInput, nil, "name is required")
 	}
+	if i.CinemaID < 0 {
+		return NewErr(ErrInput, nil, "cinema id is invalid")
+	}
 	return nil
 }
 
 type Room struct {
 	ID        int64     `json:"id,omitempty"`
 	Name      string    `json:"name,omitempty"`
+	CinemaID  int64     `json:"cinema_id,omitempty"`
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 
@@ -50,19 +56,27 @@ func NewRoom(input RoomInput) (*Room, error) {
 		return nil, err
 	}
 	room := Room{
-		Name: input.Name,
+		Name:     input.Name,
+		CinemaID: input.CinemaID,
 	}
 	return &room, nil
 }
 
+const SeatCategoryStandard = "standard"
+
 type SeatInput struct {
 	RoomID          int64  `json:"room_id,omitempty"`
 	Name            string `json:"name,omitempty"`
-	AdditionalPrice int    `json:"additional_price,omitempty"`
+	AdditionalPrice int64  `json:"additional_price,omitempty"`            // in the currency of the room cinema
+	Category        string `json:"category,omitempty" example:"standard"` // default standard
 }
 
 func (i *SeatInput) Validate() error {
 	i.Name = strings.Trim(i.Name, " ")
+	i.Category = strings.ToLower(strings.Trim(i.Category, " "))
+	if i.Category == "" {
+		i.Category = SeatCategoryStandard
+	}
 
 	if i.Name == "" {
 		return NewErr(ErrInput, nil, "name is required")
@@ -86,7 +100,8 @@ type Seat struct {
 	ID              int64     `json:"id"`
 	RoomID          int64     `json:"room_id"`
 	Name            string    `json:"name"`
-	AdditionalPrice int       `json:"additional_price"`
+	AdditionalPrice int64     `json:"additional_price"`
+	Category        string    `json:"category"`
 	CreatedAt       time.Time `json:"created_at"`
 	UpdatedAt       time.Time `json:"updated_at"`
 
@@ -104,8 +119,38 @@ func NewSeat(input SeatInput) (*Seat, error) {
 		RoomID:          input.RoomID,
 		Name:            strings.Trim(input.Name, " "),
 		AdditionalPrice: input.AdditionalPrice,
+		Category:        input.Category,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
 	return &seat, nil
 }
+
+type RoomScheduleInput struct {
+	From time.Time `json:"from" query:"from" example:"2006-01-02T15:04:05Z"` // default now
+	To   time.Time `json:"to" query:"to" example:"2006-01-08T15:04:05Z"`     // default 7 days after from
+}
+
+func (i *RoomScheduleInput) Validate() error {
+	if i.From.IsZero() {
+		i.From = time.Now()
+	}
+	if i.To.IsZero() {
+		i.To = i.From.AddDate(0, 0, 7)
+	}
+	if !i.From.Before(i.To) {
+		return NewErr(ErrInput, nil, "time invalid")
+	}
+	if i.To.Sub(i.From) > 31*24*time.Hour {
+		return NewErr(ErrInput, nil, "schedule range must not exceed 31 days")
+	}
+	return nil
+}
+
+type RoomSchedule struct {
+	Room      Room       `json:"room"`
+	From      time.Time  `json:"from"`
+	To        time.Time  `json:"to"`
+	Showtimes []Showtime `json:"showtimes"`
+	Blackouts []Blackout `json:"blackouts"`
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type SessionFilter struct {
+	IDs                 []int64  `json:"ids,omitempty"`
+	UserIDs             []int64  `json:"user_ids,omitempty"`
+	RefreshTokenHashes  []string `json:"-"`
+	PreviousTokenHashes []string `json:"-"`
+	IsActive            *bool    `json:"is_active,omitempty"` // not revoked and not expired
+}
+
+type RefreshTokenInput struct {
+	RefreshToken string `json:"refresh_token"`
+	UserAgent    string `json:"-"`
+}
+
+func (i *RefreshTokenInput) Validate() error {
+	i.RefreshToken = strings.TrimSpace(i.RefreshToken)
+	if i.RefreshToken == "" {
+		return NewErr(ErrInput, nil, "refresh token is required")
+	}
+	return nil
+}
+
+// NewSession start a login session, the returned refresh token is only known by the client
+func NewSession(userID int64, ttl time.Duration, userAgent string) (*Session, string) {
+	refreshToken := randomToken(32)
+	session := Session{
+		UserID:           userID,
+		RefreshTokenHash: HashToken(refreshToken),
+		UserAgent:        strings.TrimSpace(userAgent),
+		ExpiresAt:        time.Now().Add(ttl),
+	}
+	return &session, refreshToken
+}
+
+type Session struct {
+	ID               int64      `json:"id"`
+	UserID           int64      `json:"user_id"`
+	RefreshTokenHash string     `json:"-"`
+	UserAgent        string     `json:"user_agent,omitempty"`
+	MFA              bool       `json:"mfa"` // started with second factor
+	ExpiresAt        time.Time  `json:"expires_at"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
+
+// AuthToken is given on login and refresh,
+// access token is short-lived and refresh token is rotated on every use
+type AuthToken struct {
+	Token        string    `json:"token,omitempty"`
+	ExpiresAt    time.Time `json:"expires_at,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+
+	// when two-factor is enabled, login only return the challenge to send with the code to /api/login/mfa
+	MFARequired bool   `json:"mfa_required,omitempty"`
+	MFAToken    string `json:"mfa_token,omitempty"`
+}
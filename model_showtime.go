@@ -1,15 +1,94 @@
 package main
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type ShowtimeFormat string
+
+const (
+	ShowtimeFormat2D   ShowtimeFormat = "2D"
+	ShowtimeFormat3D   ShowtimeFormat = "3D"
+	ShowtimeFormatIMAX ShowtimeFormat = "IMAX"
+	ShowtimeFormat4DX  ShowtimeFormat = "4DX"
+)
+
+var showtimeFormats = []ShowtimeFormat{
+	ShowtimeFormat2D,
+	ShowtimeFormat3D,
+	ShowtimeFormatIMAX,
+	ShowtimeFormat4DX,
+}
+
+func (f ShowtimeFormat) Validate() error {
+	for _, format := range showtimeFormats {
+		if f == format {
+			return nil
+		}
+	}
+	return NewErr(ErrInput, nil, "format %s is invalid, available %v", f, showtimeFormats)
+}
+
+// normalizeLanguage lowercase the ISO 639 language code, empty is allowed
+func normalizeLanguage(code string) (string, error) {
+	code = strings.ToLower(strings.Trim(code, " "))
+	if code == "" {
+		return code, nil
+	}
+	if len(code) < 2 || len(code) > 3 {
+		return "", fmt.Errorf("language code %s must be 2 or 3 letters", code)
+	}
+	for _, c := range code {
+		if c < 'a' || c > 'z' {
+			return "", fmt.Errorf("language code %s must only contain letters", code)
+		}
+	}
+	return code, nil
+}
 
 type ShowtimeFilter struct {
 	IDs      []int64   `json:"ids"`
 	MovieIDs []int64   `json:"movie_ids"`
 	RoomIDs  []int64   `json:"room_ids"`
 	After    time.Time `json:"after" example:"2006-01-02T15:04:05+08:00"` // only list showtime after/equal this time
+
+	CinemaIDs  []int64 `json:"cinema_ids"`  // default is preferred cinema of logged in user
+	AllCinemas bool    `json:"all_cinemas"` // ignore preferred cinema of logged in user
+
+	Formats            []ShowtimeFormat `json:"formats" example:"3D"`
+	AudioLanguages     []string         `json:"audio_languages" example:"en"`
+	SubtitleLanguages  []string         `json:"subtitle_languages" example:"id"`
+	HasSubtitle        *bool            `json:"has_subtitle"`
+	IsOriginalLanguage *bool            `json:"is_original_language"` // audio language same as the movie original language
+	OpenCaptions       *bool            `json:"open_captions"`
+	AudioDescription   *bool            `json:"audio_description"`
 }
 
 func (f *ShowtimeFilter) Validate() error {
+	for i, format := range f.Formats {
+		format = ShowtimeFormat(strings.ToUpper(string(format)))
+		err := format.Validate()
+		if err != nil {
+			return err
+		}
+		f.Formats[i] = format
+	}
+	for i, v := range f.AudioLanguages {
+		code, err := normalizeLanguage(v)
+		if err != nil || code == "" {
+			return NewErr(ErrInput, err, "audio language is invalid")
+		}
+		f.AudioLanguages[i] = code
+	}
+	for i, v := range f.SubtitleLanguages {
+		code, err := normalizeLanguage(v)
+		if err != nil || code == "" {
+			return NewErr(ErrInput, err, "subtitle language is invalid")
+		}
+		f.SubtitleLanguages[i] = code
+	}
 	return nil
 }
 
@@ -18,7 +97,13 @@ type ShowtimeInput struct {
 	RoomID  int64     `json:"room_id,omitempty"`
 	StartAt time.Time `json:"start_at,omitempty" example:"2006-01-02T15:04:05+08:00"`
 	EndAt   time.Time `json:"end_at,omitempty" example:"2006-01-02T15:05:05+08:00"`
-	Price   int64     `json:"price,omitempty"`
+	Price   int64     `json:"price,omitempty"` // in the currency of the room cinema
+
+	Format           ShowtimeFormat `json:"format,omitempty" example:"2D"`            // default 2D
+	AudioLanguage    string         `json:"audio_language,omitempty" example:"en"`    // default movie original language
+	SubtitleLanguage string         `json:"subtitle_language,omitempty" example:"id"` // empty when no subtitle
+	OpenCaptions     bool           `json:"open_captions,omitempty"`
+	AudioDescription bool           `json:"audio_description,omitempty"`
 }
 
 func (i *ShowtimeInput) Validate() error {
@@ -40,6 +125,22 @@ func (i *ShowtimeInput) Validate() error {
 	if i.Price <= 0 {
 		return NewErr(ErrInput, nil, "price minimum is 0")
 	}
+	if i.Format == "" {
+		i.Format = ShowtimeFormat2D
+	}
+	i.Format = ShowtimeFormat(strings.ToUpper(string(i.Format)))
+	err := i.Format.Validate()
+	if err != nil {
+		return err
+	}
+	i.AudioLanguage, err = normalizeLanguage(i.AudioLanguage)
+	if err != nil {
+		return NewErr(ErrInput, err, "audio language is invalid")
+	}
+	i.SubtitleLanguage, err = normalizeLanguage(i.SubtitleLanguage)
+	if err != nil {
+		return NewErr(ErrInput, err, "subtitle language is invalid")
+	}
 	return nil
 }
 
@@ -54,6 +155,12 @@ func NewShowtime(input ShowtimeInput) (*Showtime, error) {
 		StartAt: input.StartAt,
 		EndAt:   input.EndAt,
 		Price:   input.Price,
+
+		Format:           input.Format,
+		AudioLanguage:    input.AudioLanguage,
+		SubtitleLanguage: input.SubtitleLanguage,
+		OpenCaptions:     input.OpenCaptions,
+		AudioDescription: input.AudioDescription,
 	}
 	return &showtime, nil
 }
@@ -68,39 +175,58 @@ type Showtime struct {
 	CreatedAt time.Time `json:"created_at,omitempty"`
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 
+	Format           ShowtimeFormat `json:"format"`
+	AudioLanguage    string         `json:"audio_language"`
+	SubtitleLanguage string         `json:"subtitle_language"`
+	OpenCaptions     bool           `json:"open_captions"`
+	AudioDescription bool           `json:"audio_description"`
+
 	// relation
-	MovieTitle    string `json:"movie_title"`
-	RoomName      string `json:"room_name"`
-	TotalSeat     int64  `json:"total_seat"`
-	AvailableSeat int64  `json:"available_seat"`
+	MovieTitle    string   `json:"movie_title"`
+	RoomName      string   `json:"room_name"`
+	TotalSeat     int64    `json:"total_seat"`
+	AvailableSeat int64    `json:"available_seat"`
+	Surcharge     int64    `json:"surcharge"` // format surcharge added to each seat price
+	Currency      Currency `json:"currency"`  // currency of price and surcharge, from the cinema of the room
 }
 
 func (s *Showtime) ValidateOtherOverlapping(roomID int64, startAt, endAt time.Time) error {
-	errOverlapping := NewErr(ErrInput, nil, "showtime room overlapping with other showtime")
-
 	if s.RoomID != roomID {
 		return nil
 	}
-
-	if s.StartAt.Equal(startAt) {
-		return errOverlapping
-	}
-	if s.StartAt.Equal(startAt) {
-		return errOverlapping
-	}
-	if startAt.After(s.StartAt) && startAt.Before(s.EndAt) {
-		return errOverlapping
+	if isTimeOverlapping(s.StartAt, s.EndAt, startAt, endAt) {
+		return NewErr(ErrInput, nil, "showtime room overlapping with other showtime")
 	}
+	return nil
+}
 
-	if s.EndAt.Equal(startAt) {
-		return errOverlapping
-	}
-	if s.EndAt.Equal(endAt) {
-		return errOverlapping
+// isTimeOverlapping report whether two half-open time ranges [start, end) share any moment,
+// range that end exactly when the other start is not overlapping
+func isTimeOverlapping(startAt, endAt, otherStartAt, otherEndAt time.Time) bool {
+	return startAt.Before(otherEndAt) && otherStartAt.Before(endAt)
+}
+
+type FormatSurchargeInput struct {
+	Amount   int64    `json:"amount" example:"15000"`
+	Currency Currency `json:"currency,omitempty" example:"IDR"` // surcharge only apply to cinema of this currency, default IDR
+}
+
+func (i *FormatSurchargeInput) Validate() error {
+	if i.Amount < 0 {
+		return NewErr(ErrInput, nil, "surcharge amount minimum is 0")
 	}
-	if endAt.After(s.StartAt) && endAt.Before(s.EndAt) {
-		return errOverlapping
+	currency, err := NormalizeCurrency(i.Currency)
+	if err != nil {
+		return err
 	}
-
+	i.Currency = currency
 	return nil
 }
+
+// FormatSurcharge is set for each currency, showtime in cinema of other currency get no surcharge
+type FormatSurcharge struct {
+	Format    ShowtimeFormat `json:"format"`
+	Currency  Currency       `json:"currency"`
+	Amount    int64          `json:"amount"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
@@ -0,0 +1,73 @@
+package main
+
+import "time"
+
+type TicketQRFormat string
+
+const (
+	TicketQRPNG TicketQRFormat = "png"
+	TicketQRSVG TicketQRFormat = "svg"
+)
+
+// TicketClaims is what the signed ticket token carries, enough to admit the holder to the seat
+type TicketClaims struct {
+	ReservationID     int64 `json:"r"`
+	ReservationItemID int64 `json:"i"`
+	ShowtimeID        int64 `json:"s"`
+	SeatID            int64 `json:"t"`
+}
+
+// Ticket is the e-ticket of one reserved seat
+type Ticket struct {
+	ReservationID     int64     `json:"reservation_id"`
+	ReservationItemID int64     `json:"reservation_item_id"`
+	ShowtimeID        int64     `json:"showtime_id"`
+	SeatID            int64     `json:"seat_id"`
+	Movie             string    `json:"movie"`
+	Room              string    `json:"room"`
+	Seat              string    `json:"seat"`
+	ShowtimeStart     time.Time `json:"showtime_start"`
+	ShowtimeEnd       time.Time `json:"showtime_end"`
+	Token             string    `json:"token"`   // signed token encoded in the qr code
+	QRCode            string    `json:"qr_code"` // png data uri of the token
+}
+
+// NewTickets issue the tickets of every seat of paid reservation,
+// tickets of cancelled or refunded reservation are void
+func NewTickets(secret string, reservation *Reservation) ([]Ticket, error) {
+	switch reservation.Status {
+	case ReservationPaid:
+	case ReservationCancelled:
+		return nil, NewErr(ErrInput, nil, "tickets of cancelled reservation are void")
+	default:
+		return nil, NewErr(ErrInput, nil, "tickets are only issued for paid reservation")
+	}
+
+	tickets := make([]Ticket, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		token := SignTicketToken(secret, TicketClaims{
+			ReservationID:     reservation.ID,
+			ReservationItemID: item.ID,
+			ShowtimeID:        item.ShowtimeID,
+			SeatID:            item.SeatID,
+		})
+		qrCode, err := TicketQRCodeDataURI(token)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, Ticket{
+			ReservationID:     reservation.ID,
+			ReservationItemID: item.ID,
+			ShowtimeID:        item.ShowtimeID,
+			SeatID:            item.SeatID,
+			Movie:             item.Movie,
+			Room:              item.Room,
+			Seat:              item.Seat,
+			ShowtimeStart:     item.ShowtimeStart,
+			ShowtimeEnd:       item.ShowtimeEnd,
+			Token:             token,
+			QRCode:            qrCode,
+		})
+	}
+	return tickets, nil
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type TicketTypeFilter struct {
+	IDs       []int64  `json:"ids"`
+	Codes     []string `json:"codes"`
+	IsActive  *bool    `json:"is_active"`
+	IsDefault *bool    `json:"is_default"`
+}
+
+func (f *TicketTypeFilter) Validate() error {
+	for i, v := range f.Codes {
+		f.Codes[i] = strings.ToLower(strings.Trim(v, " "))
+	}
+	return nil
+}
+
+type TicketTypeInput struct {
+	Code                string   `json:"code,omitempty" example:"child"`
+	Name                string   `json:"name,omitempty" example:"Child"`
+	Percent             int64    `json:"percent,omitempty" example:"-30"`              // adjustment in percent of seat price, negative for discount
+	Amount              int64    `json:"amount,omitempty" example:"0"`                 // fixed adjustment, negative for discount
+	Currency            Currency `json:"currency,omitempty" example:"IDR"`             // currency of the amount, default IDR
+	RequireTicketTypeID int64    `json:"require_ticket_type_id,omitempty" example:"1"` // ticket type that must exist in the same reservation
+	IsDefault           bool     `json:"is_default,omitempty"`                         // used when cart does not choose ticket type
+	IsActive            *bool    `json:"is_active,omitempty"`                          // default true
+}
+
+func (i *TicketTypeInput) Validate() error {
+	i.Code = strings.ToLower(strings.Trim(i.Code, " "))
+	i.Name = strings.Trim(i.Name, " ")
+
+	if i.Code == "" {
+		return NewErr(ErrInput, nil, "code is required")
+	}
+	if i.Name == "" {
+		return NewErr(ErrInput, nil, "name is required")
+	}
+	if i.Percent < -100 {
+		return NewErr(ErrInput, nil, "percent minimum is -100")
+	}
+	currency, err := NormalizeCurrency(i.Currency)
+	if err != nil {
+		return err
+	}
+	i.Currency = currency
+	if i.RequireTicketTypeID < 0 {
+		return NewErr(ErrInput, nil, "require ticket type id is invalid")
+	}
+	if i.IsActive == nil {
+		active := true
+		i.IsActive = &active
+	}
+	if i.IsDefault && !*i.IsActive {
+		return NewErr(ErrInput, nil, "default ticket type must be active")
+	}
+	if i.IsDefault && i.RequireTicketTypeID > 0 {
+		return NewErr(ErrInput, nil, "default ticket type cannot require other ticket type")
+	}
+	return nil
+}
+
+func NewTicketType(input TicketTypeInput) (*TicketType, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	ticketType := TicketType{
+		Code:                input.Code,
+		Name:                input.Name,
+		Percent:             input.Percent,
+		Amount:              input.Amount,
+		Currency:            input.Currency,
+		RequireTicketTypeID: input.RequireTicketTypeID,
+		IsDefault:           input.IsDefault,
+		IsActive:            *input.IsActive,
+	}
+	return &ticketType, nil
+}
+
+type TicketType struct {
+	ID                  int64     `json:"id"`
+	Code                string    `json:"code"`
+	Name                string    `json:"name"`
+	Percent             int64     `json:"percent"`
+	Amount              int64     `json:"amount"`
+	Currency            Currency  `json:"currency"`
+	RequireTicketTypeID int64     `json:"require_ticket_type_id,omitempty"`
+	IsDefault           bool      `json:"is_default"`
+	IsActive            bool      `json:"is_active"`
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+}
+
+// AppliesTo report whether the ticket type can price a seat of the currency,
+// fixed amount only has meaning in its own currency while percent apply to any
+func (t *TicketType) AppliesTo(currency Currency) bool {
+	return t.Amount == 0 || t.Currency == currency
+}
+
+// Adjustment of the seat price when this ticket type chosen
+func (t *TicketType) Adjustment(price int64) (int64, error) {
+	percent, err := MulAmount(price, t.Percent)
+	if err != nil {
+		return 0, err
+	}
+	return AddAmount(percent/100, t.Amount)
+}
+
+type AppliedTicketType struct {
+	TicketTypeID int64  `json:"ticket_type_id"`
+	Code         string `json:"code"`
+	Name         string `json:"name"`
+	Percent      int64  `json:"percent"`
+	Amount       int64  `json:"amount"`
+	Adjustment   int64  `json:"adjustment"`
+}
+
+// ApplyTicketType adjust the price after pricing rules with the ticket type modifier,
+// ticket type with fixed amount of other currency is refused
+func (s *PriceSnapshot) ApplyTicketType(ticketType TicketType, currency Currency) error {
+	if !ticketType.AppliesTo(currency) {
+		return NewErr(ErrInput, nil, "%s ticket is priced in %s, cannot be used in %s", ticketType.Name, ticketType.Currency, currency)
+	}
+	adjustment, err := ticketType.Adjustment(s.Price)
+	if err != nil {
+		return err
+	}
+	s.Price, err = AddAmount(s.Price, adjustment)
+	if err != nil {
+		return err
+	}
+	if s.Price < 0 {
+		s.Price = 0
+	}
+	s.TicketType = &AppliedTicketType{
+		TicketTypeID: ticketType.ID,
+		Code:         ticketType.Code,
+		Name:         ticketType.Name,
+		Percent:      ticketType.Percent,
+		Amount:       ticketType.Amount,
+		Adjustment:   adjustment,
+	}
+	return nil
+}
+
+// ValidateTicketTypeEligibility make sure every chosen ticket type requirement
+// is fulfilled by other ticket in the same reservation
+func ValidateTicketTypeEligibility(chosen []TicketType, ticketTypes []TicketType) error {
+	chosenSet := map[int64]struct{}{}
+	for _, ticketType := range chosen {
+		chosenSet[ticketType.ID] = struct{}{}
+	}
+	names := map[int64]string{}
+	for _, ticketType := range ticketTypes {
+		names[ticketType.ID] = ticketType.Name
+	}
+	for _, ticketType := range chosen {
+		if !ticketType.IsActive {
+			return NewErr(ErrInput, nil, "%s ticket is not available", ticketType.Name)
+		}
+		if ticketType.RequireTicketTypeID <= 0 {
+			continue
+		}
+		if _, ok := chosenSet[ticketType.RequireTicketTypeID]; !ok {
+			return NewErr(
+				ErrInput,
+				nil,
+				"%s ticket require %s ticket in the same reservation",
+				ticketType.Name,
+				names[ticketType.RequireTicketTypeID],
+			)
+		}
+	}
+	return nil
+}
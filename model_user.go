@@ -11,12 +11,9 @@ import (
 const (
 	UserRegular = "user"
 	UserAdmin   = "admin"
+	UserStaff   = "staff"
 )
 
-func isValidRole(role string) bool {
-	return role == UserRegular || role == UserAdmin
-}
-
 type RoleFilter struct {
 	IDs   []int64  `json:"role_ids,omitempty"`
 	Names []string `json:"roles,omitempty"`
@@ -24,34 +21,43 @@ type RoleFilter struct {
 
 func (f *RoleFilter) Validate() error {
 	for i, v := range f.Names {
-		role := strings.Trim(v, " ")
-		if !isValidRole(v) {
-			return NewErr(ErrInput, nil, "role index %d = %s invalid", i, v)
-		}
-		f.Names[i] = role
+		f.Names[i] = strings.Trim(v, " ")
 	}
 	return nil
 }
 
 type Role struct {
-	ID   int64  `json:"id"`
-	Name string `json:"name"`
+	ID          int64    `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
 }
 
 type UserFilter struct {
-	IDs     []int64  `json:"ids,omitempty"`
-	Emails  []string `json:"emails,omitempty"`
-	RoleIDs []int64  `json:"role_ids,omitempty"`
-	Roles   []string `json:"roles,omitempty"`
+	IDs        []int64  `json:"ids,omitempty"`
+	Emails     []string `json:"emails,omitempty"`
+	RoleIDs    []int64  `json:"role_ids,omitempty"`
+	Roles      []string `json:"roles,omitempty"`
+	Search     string   `json:"search,omitempty"` // part of the email, case insensitive
+	IsDisabled *bool    `json:"is_disabled,omitempty"`
 }
 
 func (f *UserFilter) Validate() error {
 	for i, v := range f.Roles {
-		role := strings.Trim(v, " ")
-		if !isValidRole(v) {
-			return NewErr(ErrInput, nil, "role index %d = %s invalid", i, v)
-		}
-		f.Roles[i] = role
+		f.Roles[i] = strings.Trim(v, " ")
+	}
+	f.Search = strings.Trim(f.Search, " ")
+	return nil
+}
+
+type DisableUserInput struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+func (i *DisableUserInput) Validate() error {
+	i.Reason = strings.Trim(i.Reason, " ")
+	max := 255
+	if len(i.Reason) > max {
+		return NewErr(ErrInput, nil, "reason maximum %d characters", max)
 	}
 	return nil
 }
@@ -72,13 +78,17 @@ func (i *UserInput) Validate() error {
 		return NewErr(ErrInput, nil, "email invalid!")
 	}
 
+	return validatePassword(i.Password)
+}
+
+func validatePassword(password string) error {
 	min := 8
-	if len(i.Password) < min {
+	if len(password) < min {
 		return NewErr(ErrInput, nil, "password minimum %d characters", min)
 	}
 
 	max := 20
-	if len(i.Password) > max {
+	if len(password) > max {
 		return NewErr(ErrInput, nil, "password maximum %d characters", max)
 	}
 
@@ -109,12 +119,32 @@ type User struct {
 	ID           int64     `json:"id"`
 	Email        string    `json:"email"`
 	PasswordHash string    `json:"-"`
+	TokenVersion int64     `json:"-"` // access token with other version is rejected
 	CreatedAt    time.Time `json:"created_at"`
 	UpdatedAt    time.Time `json:"updated_at"`
 
+	EmailVerifiedAt *time.Time `json:"email_verified_at,omitempty"`
+	MFASecret       string     `json:"-"`
+	MFAEnabledAt    *time.Time `json:"mfa_enabled_at,omitempty"`
+	MFALastStep     int64      `json:"-"`
+	DisabledAt      *time.Time `json:"disabled_at,omitempty"`
+	DisabledReason  string     `json:"disabled_reason,omitempty"`
+
 	Role string `json:"role,omitempty"`
 }
 
+func (u *User) IsMFAEnabled() bool {
+	return u.MFAEnabledAt != nil && u.MFASecret != ""
+}
+
+func (u *User) IsDisabled() bool {
+	return u.DisabledAt != nil
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
 func (u *User) UpdatePassword(password string) error {
 	hashedPassword, err := HashPassword(password)
 	if err != nil {
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type UserTokenPurpose string
+
+const (
+	UserTokenPasswordReset     UserTokenPurpose = "password_reset"
+	UserTokenEmailVerification UserTokenPurpose = "email_verification"
+	UserTokenMFAChallenge      UserTokenPurpose = "mfa_challenge"
+)
+
+type UserTokenFilter struct {
+	UserIDs      []int64            `json:"user_ids,omitempty"`
+	Purposes     []UserTokenPurpose `json:"purposes,omitempty"`
+	TokenHashes  []string           `json:"-"`
+	IsUsable     *bool              `json:"is_usable,omitempty"` // not used and not expired
+	CreatedAfter *time.Time         `json:"created_after,omitempty"`
+}
+
+// NewUserToken create single-use token sent to the user, the returned token is never stored
+func NewUserToken(userID int64, purpose UserTokenPurpose, ttl time.Duration) (*UserToken, string) {
+	token := randomToken(32)
+	userToken := UserToken{
+		UserID:    userID,
+		Purpose:   purpose,
+		TokenHash: HashToken(token),
+		ExpiresAt: time.Now().Add(ttl),
+	}
+	return &userToken, token
+}
+
+type UserToken struct {
+	ID        int64            `json:"id"`
+	UserID    int64            `json:"user_id"`
+	Purpose   UserTokenPurpose `json:"purpose"`
+	TokenHash string           `json:"-"`
+	ExpiresAt time.Time        `json:"expires_at"`
+	UsedAt    *time.Time       `json:"used_at,omitempty"`
+	CreatedAt time.Time        `json:"created_at"`
+}
+
+type ForgotPasswordInput struct {
+	Email string `json:"email"`
+}
+
+func (i *ForgotPasswordInput) Validate() error {
+	i.Email = strings.TrimSpace(i.Email)
+	if i.Email == "" {
+		return NewErr(ErrInput, nil, "email is required")
+	}
+	return nil
+}
+
+type ResetPasswordInput struct {
+	Token    string `json:"token"`
+	Password string `json:"password"`
+}
+
+func (i *ResetPasswordInput) Validate() error {
+	i.Token = strings.TrimSpace(i.Token)
+	if i.Token == "" {
+		return NewErr(ErrInput, nil, "token is required")
+	}
+	i.Password = strings.Trim(i.Password, " ")
+	return validatePassword(i.Password)
+}
+
+type VerifyEmailInput struct {
+	Token string `json:"token"`
+}
+
+func (i *VerifyEmailInput) Validate() error {
+	i.Token = strings.TrimSpace(i.Token)
+	if i.Token == "" {
+		return NewErr(ErrInput, nil, "token is required")
+	}
+	return nil
+}
+
+type ChangePasswordInput struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+func (i *ChangePasswordInput) Validate() error {
+	if i.CurrentPassword == "" {
+		return NewErr(ErrInput, nil, "current password is required")
+	}
+	i.NewPassword = strings.Trim(i.NewPassword, " ")
+	err := validatePassword(i.NewPassword)
+	if err != nil {
+		return err
+	}
+	if i.NewPassword == strings.Trim(i.CurrentPassword, " ") {
+		return NewErr(ErrInput, nil, "new password must be different from current password")
+	}
+	return nil
+}
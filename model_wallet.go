@@ -0,0 +1,113 @@
+package main
+
+import (
+	"strings"
+	"time"
+)
+
+type GiftCardSource string
+
+const (
+	GiftCardSourceAdmin    GiftCardSource = "admin"
+	GiftCardSourcePurchase GiftCardSource = "purchase"
+)
+
+type GiftCardFilter struct {
+	IDs          []int64  `json:"ids"`
+	Codes        []string `json:"codes"`
+	PurchaserIDs []int64  `json:"purchaser_ids"`
+	IsRedeemed   *bool    `json:"is_redeemed"`
+}
+
+func (f *GiftCardFilter) Validate() error {
+	for i, v := range f.Codes {
+		f.Codes[i] = normalizeGiftCardCode(v)
+	}
+	return nil
+}
+
+func normalizeGiftCardCode(code string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.Trim(code, " "), " ", ""))
+}
+
+type GiftCardInput struct {
+	Amount int64 `json:"amount" example:"100000"`
+}
+
+func (i *GiftCardInput) Validate() error {
+	if i.Amount <= 0 {
+		return NewErr(ErrInput, nil, "amount minimum is 1")
+	}
+	return nil
+}
+
+func NewGiftCard(input GiftCardInput, source GiftCardSource, purchaserID int64) (*GiftCard, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	giftCard := GiftCard{
+		Code:          newGiftCardCode(),
+		InitialAmount: input.Amount,
+		Balance:       input.Amount,
+		Source:        source,
+		PurchaserID:   purchaserID,
+	}
+	return &giftCard, nil
+}
+
+// newGiftCardCode is 16 random hex characters in groups of 4, e.g. 1A2B-3C4D-5E6F-7A8B
+func newGiftCardCode() string {
+	token := strings.ToUpper(randomToken(8))
+	return strings.Join([]string{token[0:4], token[4:8], token[8:12], token[12:16]}, "-")
+}
+
+type GiftCard struct {
+	ID            int64          `json:"id"`
+	Code          string         `json:"code"`
+	InitialAmount int64          `json:"initial_amount"`
+	Balance       int64          `json:"balance"`
+	Source        GiftCardSource `json:"source"`
+	PurchaserID   int64          `json:"purchaser_id,omitempty"`
+	RedeemedByID  int64          `json:"redeemed_by_id,omitempty"`
+	RedeemedAt    *time.Time     `json:"redeemed_at,omitempty"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+type WalletTopUpInput struct {
+	Code string `json:"code" example:"1A2B-3C4D-5E6F-7A8B"`
+}
+
+func (i *WalletTopUpInput) Validate() error {
+	i.Code = normalizeGiftCardCode(i.Code)
+	if i.Code == "" {
+		return NewErr(ErrInput, nil, "gift card code is required")
+	}
+	return nil
+}
+
+type Wallet struct {
+	UserID    int64     `json:"user_id"`
+	Balance   int64     `json:"balance"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type WalletSummary struct {
+	Wallet  Wallet                 `json:"wallet"`
+	Entries *Paginate[LedgerEntry] `json:"entries"`
+}
+
+type PayReservationInput struct {
+	WalletAmount int64 `json:"wallet_amount,omitempty" example:"0"` // paid from wallet, the rest is charged to payment gateway
+}
+
+func (i *PayReservationInput) Validate(totalPrice int64) error {
+	if i.WalletAmount < 0 {
+		return NewErr(ErrInput, nil, "wallet amount minimum is 0")
+	}
+	if i.WalletAmount > totalPrice {
+		return NewErr(ErrInput, nil, "wallet amount cannot exceed total price %d", totalPrice)
+	}
+	return nil
+}
@@ -3,21 +3,59 @@ package main
 import "github.com/jackc/pgx/v5"
 
 type RepositoryRegistry struct {
-	User        *UserRepository
-	Movie       *MovieRepository
-	Room        *RoomRepository
-	Showtime    *ShowtimeRepository
-	Reservation *ReservationRepository
-	Cart        *CartRepository
+	User           *UserRepository
+	Movie          *MovieRepository
+	Room           *RoomRepository
+	Showtime       *ShowtimeRepository
+	Reservation    *ReservationRepository
+	Cart           *CartRepository
+	Cinema         *CinemaRepository
+	Blackout       *BlackoutRepository
+	PricingRule    *PricingRuleRepository
+	TicketType     *TicketTypeRepository
+	Fee            *FeeRepository
+	PromoCode      *PromoCodeRepository
+	Point          *PointRepository
+	Ledger         *LedgerRepository
+	Wallet         *WalletRepository
+	Reconciliation *ReconciliationRepository
+	Admission      *AdmissionRepository
+	Session        *SessionRepository
+	UserToken      *UserTokenRepository
+	MFA            *MFARepository
+	LoginAttempt   *LoginAttemptRepository
+	OIDC           *OIDCRepository
+	Role           *RoleRepository
+	Profile        *ProfileRepository
+	Payment        *PaymentRepository
 }
 
 func NewRepositoryRegistry(tx pgx.Tx) *RepositoryRegistry {
 	return &RepositoryRegistry{
-		User:        NewUserRepository(tx),
-		Movie:       NewMovieRepository(tx),
-		Room:        NewRoomRepository(tx),
-		Showtime:    NewShowtimeRepository(tx),
-		Reservation: NewReservationRepository(tx),
-		Cart:        NewCartRepository(tx),
+		User:           NewUserRepository(tx),
+		Movie:          NewMovieRepository(tx),
+		Room:           NewRoomRepository(tx),
+		Showtime:       NewShowtimeRepository(tx),
+		Reservation:    NewReservationRepository(tx),
+		Cart:           NewCartRepository(tx),
+		Cinema:         NewCinemaRepository(tx),
+		Blackout:       NewBlackoutRepository(tx),
+		PricingRule:    NewPricingRuleRepository(tx),
+		TicketType:     NewTicketTypeRepository(tx),
+		Fee:            NewFeeRepository(tx),
+		PromoCode:      NewPromoCodeRepository(tx),
+		Point:          NewPointRepository(tx),
+		Ledger:         NewLedgerRepository(tx),
+		Wallet:         NewWalletRepository(tx),
+		Reconciliation: NewReconciliationRepository(tx),
+		Admission:      NewAdmissionRepository(tx),
+		Session:        NewSessionRepository(tx),
+		UserToken:      NewUserTokenRepository(tx),
+		MFA:            NewMFARepository(tx),
+		LoginAttempt:   NewLoginAttemptRepository(tx),
+		OIDC:           NewOIDCRepository(tx),
+		Role:           NewRoleRepository(tx),
+		Profile:        NewProfileRepository(tx),
+		Payment:        NewPaymentRepository(tx),
 	}
 }
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewAdmissionRepository(tx pgx.Tx) *AdmissionRepository {
+	return &AdmissionRepository{
+		tx: tx,
+	}
+}
+
+type AdmissionRepository struct {
+	tx pgx.Tx
+}
+
+// Create record the admission, zero id is returned when the ticket is already admitted
+func (r *AdmissionRepository) Create(ctx context.Context, admission *Admission) (int64, error) {
+	sql := `
+		insert into public.admissions (reservation_item_id, reservation_id, showtime_id, seat_id, scanned_by, device_id, scanned_at)
+		values (@reservation_item_id, @reservation_id, @showtime_id, @seat_id, nullif(@scanned_by, 0), @device_id, @scanned_at)
+		on conflict (reservation_item_id) do nothing
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"reservation_item_id": admission.ReservationItemID,
+		"reservation_id":      admission.ReservationID,
+		"showtime_id":         admission.ShowtimeID,
+		"seat_id":             admission.SeatID,
+		"scanned_by":          admission.ScannedBy,
+		"device_id":           admission.DeviceID,
+		"scanned_at":          admission.ScannedAt,
+	}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *AdmissionRepository) FindOne(ctx context.Context, filter AdmissionFilter) (*Admission, error) {
+	admissions, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(admissions) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "admission not found")
+	}
+	return &admissions[0], nil
+}
+
+func (r *AdmissionRepository) Find(ctx context.Context, filter AdmissionFilter) ([]Admission, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				a.id,
+				a.reservation_item_id,
+				a.reservation_id,
+				a.showtime_id,
+				a.seat_id,
+				coalesce(a.scanned_by, 0),
+				a.device_id,
+				a.scanned_at,
+				a.created_at
+			from
+				public.admissions a
+			where
+				a.id in (%s)
+			order by
+				a.scanned_at asc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var admissions []Admission
+	for rows.Next() {
+		var admission Admission
+		err := rows.Scan(
+			&admission.ID,
+			&admission.ReservationItemID,
+			&admission.ReservationID,
+			&admission.ShowtimeID,
+			&admission.SeatID,
+			&admission.ScannedBy,
+			&admission.DeviceID,
+			&admission.ScannedAt,
+			&admission.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		admissions = append(admissions, admission)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return admissions, nil
+}
+
+// Attendance count seat sold by paid reservation of the showtime and how many of them are admitted
+func (r *AdmissionRepository) Attendance(ctx context.Context, showtimeID int64) (sold, admitted int64, err error) {
+	sql := `
+		select
+			count(ri.id),
+			count(a.id)
+		from
+			public.reservation_items ri
+			join public.reservations rv on rv.id = ri.reservation_id
+			left join public.admissions a on a.reservation_item_id = ri.id
+		where
+			ri.showtime_id = @showtime_id
+			and rv.status = 'paid'::public.reservation_status
+	`
+	err = r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"showtime_id": showtimeID}).Scan(&sold, &admitted)
+	if err != nil {
+		return 0, 0, NewSQLErr(err)
+	}
+	return sold, admitted, nil
+}
+
+func (r *AdmissionRepository) getFilterSQL(_ context.Context, filter AdmissionFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _a.id
+		from public.admissions _a
+		where
+			case
+				when array_length(@_reservation_item_ids::int[], 1) > 0 then
+					_a.reservation_item_id = any(@_reservation_item_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_showtime_ids::int[], 1) > 0 then
+					_a.showtime_id = any(@_showtime_ids)
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_reservation_item_ids": filter.ReservationItemIDs,
+		"_showtime_ids":         filter.ShowtimeIDs,
+	}
+	return sql, args
+}
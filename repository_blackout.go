@@ -0,0 +1,284 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewBlackoutRepository(tx pgx.Tx) *BlackoutRepository {
+	return &BlackoutRepository{
+		tx: tx,
+	}
+}
+
+type BlackoutRepository struct {
+	tx pgx.Tx
+}
+
+func (r *BlackoutRepository) Create(ctx context.Context, blackout *Blackout) (int64, error) {
+	sql := `
+		insert into public.blackouts (room_id, cinema_id, start_at, end_at, reason)
+		values (nullif(@room_id, 0), nullif(@cinema_id, 0), @start_at, @end_at, @reason)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"room_id":   blackout.RoomID,
+		"cinema_id": blackout.CinemaID,
+		"start_at":  blackout.StartAt,
+		"end_at":    blackout.EndAt,
+		"reason":    blackout.Reason,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *BlackoutRepository) UpdateByID(ctx context.Context, ID int64, input BlackoutInput) error {
+	sql := `
+		update public.blackouts
+		set
+			updated_at = now(),
+			room_id = nullif(@room_id, 0),
+			cinema_id = nullif(@cinema_id, 0),
+			start_at = @start_at,
+			end_at = @end_at,
+			reason = @reason
+		where id = @id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":        ID,
+		"room_id":   input.RoomID,
+		"cinema_id": input.CinemaID,
+		"start_at":  input.StartAt,
+		"end_at":    input.EndAt,
+		"reason":    input.Reason,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *BlackoutRepository) DeleteByID(ctx context.Context, ID int64) error {
+	sql := `delete from public.blackouts where id = @id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *BlackoutRepository) FindOne(ctx context.Context, filter BlackoutFilter) (*Blackout, error) {
+	blackouts, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(blackouts) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "blackout not found")
+	}
+	return &blackouts[0], nil
+}
+
+func (r *BlackoutRepository) Find(ctx context.Context, filter BlackoutFilter) ([]Blackout, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				b.id,
+				coalesce(b.room_id, 0),
+				coalesce(b.cinema_id, 0),
+				b.start_at,
+				b.end_at,
+				b.reason,
+				b.created_at,
+				b.updated_at
+			from
+				public.blackouts b
+			where
+				b.id in (%s)
+			order by
+				b.start_at asc,
+				b.id asc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var blackouts []Blackout
+	for rows.Next() {
+		var blackout Blackout
+		err := rows.Scan(
+			&blackout.ID,
+			&blackout.RoomID,
+			&blackout.CinemaID,
+			&blackout.StartAt,
+			&blackout.EndAt,
+			&blackout.Reason,
+			&blackout.CreatedAt,
+			&blackout.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		blackouts = append(blackouts, blackout)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	return blackouts, nil
+}
+
+func (r *BlackoutRepository) Pagination(ctx context.Context, filter BlackoutFilter, page PaginateInput) (*Paginate[Blackout], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]Blackout{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				b.id,
+				coalesce(b.room_id, 0),
+				coalesce(b.cinema_id, 0),
+				b.start_at,
+				b.end_at,
+				b.reason,
+				b.created_at,
+				b.updated_at
+			from
+				public.blackouts b
+			where
+				b.id in (%s)
+			order by
+				b.start_at asc,
+				b.id asc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var blackouts []Blackout
+	for rows.Next() {
+		var blackout Blackout
+		err := rows.Scan(
+			&blackout.ID,
+			&blackout.RoomID,
+			&blackout.CinemaID,
+			&blackout.StartAt,
+			&blackout.EndAt,
+			&blackout.Reason,
+			&blackout.CreatedAt,
+			&blackout.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		blackouts = append(blackouts, blackout)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = blackouts
+	return p, nil
+}
+
+func (r *BlackoutRepository) getFilterSQL(_ context.Context, filter BlackoutFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _b.id
+		from public.blackouts _b
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_b.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_room_ids::int[], 1) > 0 then
+					_b.room_id = any(@_room_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_cinema_ids::int[], 1) > 0 then
+					_b.cinema_id = any(@_cinema_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_affect_room_ids::int[], 1) > 0 then
+					_b.room_id = any(@_affect_room_ids)
+					or _b.cinema_id in (
+						select _r.cinema_id from public.rooms _r where _r.id = any(@_affect_room_ids)
+					)
+				else
+					true
+			end
+			and
+			case
+				when @_after::timestamptz is not null then
+					@_after <= _b.end_at
+				else
+					true
+			end
+			and
+			case
+				when @_before::timestamptz is not null then
+					_b.start_at <= @_before
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":             filter.IDs,
+		"_room_ids":        filter.RoomIDs,
+		"_cinema_ids":      filter.CinemaIDs,
+		"_affect_room_ids": filter.AffectRoomIDs,
+	}
+	if !filter.After.IsZero() {
+		args["_after"] = filter.After
+	}
+	if !filter.Before.IsZero() {
+		args["_before"] = filter.Before
+	}
+	return sql, args
+}
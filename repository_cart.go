@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 )
@@ -19,8 +20,8 @@ type CartRepository struct {
 
 func (r *CartRepository) Create(ctx context.Context, cart *Cart) (int64, error) {
 	sql := `
-		insert into public.carts (user_id, showtime_id, seat_id)
-		values (@user_id, @showtime_id, @seat_id)
+		insert into public.carts (user_id, showtime_id, seat_id, ticket_type_id)
+		values (@user_id, @showtime_id, @seat_id, nullif(@ticket_type_id, 0))
 		returning id
 	`
 	var ID int64
@@ -28,6 +29,8 @@ func (r *CartRepository) Create(ctx context.Context, cart *Cart) (int64, error)
 		"user_id":     cart.UserID,
 		"showtime_id": cart.ShowtimeID,
 		"seat_id":     cart.SeatID,
+
+		"ticket_type_id": cart.TicketTypeID,
 	}).Scan(&ID)
 	if err != nil {
 		return 0, NewSQLErr(err)
@@ -38,7 +41,12 @@ func (r *CartRepository) Create(ctx context.Context, cart *Cart) (int64, error)
 func (r *CartRepository) UpdateByID(ctx context.Context, ID int64, input CartInput) error {
 	sql := `
 		update public.carts
-		set updated_at=now(), user_id=@user_id, showtime_id=@showtime_id, seat_id=@seat_id
+		set
+			updated_at=now(),
+			user_id=@user_id,
+			showtime_id=@showtime_id,
+			seat_id=@seat_id,
+			ticket_type_id=nullif(@ticket_type_id, 0)
 		where id=@id
 	`
 	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
@@ -46,6 +54,8 @@ func (r *CartRepository) UpdateByID(ctx context.Context, ID int64, input CartInp
 		"user_id":     input.UserID,
 		"showtime_id": input.ShowtimeID,
 		"seat_id":     input.SeatID,
+
+		"ticket_type_id": input.TicketTypeID,
 	})
 	if err != nil {
 		return NewSQLErr(err)
@@ -85,24 +95,45 @@ func (r *CartRepository) Find(ctx context.Context, filter CartFilter) ([]Cart, e
 				c.seat_id,
 				c.created_at,
 				c.updated_at,
+				coalesce(c.ticket_type_id, 0),
 				m.title as movie,
 				s.start_at as showtime_start,
 				s.end_at as showtime_end,
 				r.name as room,
 				st."name" as seat,
-				s.price + st.additional_price as price
+				s.price + st.additional_price + coalesce(fs.amount, 0) as base_price,
+				coalesce(cn.currency, @default_currency) as currency,
+				coalesce((
+					select
+						count(ri.*) * 100 / nullif((select count(*) from seats _st where _st.room_id = s.room_id), 0)
+					from
+						reservation_items ri
+						join reservations rv on rv.id = ri.reservation_id
+					where
+						ri.showtime_id = s.id
+						and (
+							rv.status = 'paid'::reservation_status
+							or (rv.status = 'unpaid'::reservation_status
+								and rv.created_at > now() - interval '30 minutes')
+						)
+				), 0) as occupancy
 			from
 				public.carts c
 				join showtimes s on s.id  = c.showtime_id
 				join movies m on m.id = s.movie_id
 				join seats st on st.id = c.seat_id
 				join rooms r on r.id = st.room_id
+				left join cinemas cn on cn.id = r.cinema_id
+				left join format_surcharges fs on fs.format = s.format and fs.currency = coalesce(cn.currency, @default_currency)
 			where
 				c.id in (%s)
 		`,
 		filterSQL,
 	)
-	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{"default_currency": DefaultCurrency}),
+	)
 	if err != nil {
 		return nil, NewSQLErr(err)
 	}
@@ -118,12 +149,15 @@ func (r *CartRepository) Find(ctx context.Context, filter CartFilter) ([]Cart, e
 			&cart.SeatID,
 			&cart.CreatedAt,
 			&cart.UpdatedAt,
+			&cart.TicketTypeID,
 			&cart.Movie,
 			&cart.ShowtimeStart,
 			&cart.ShowtimeEnd,
 			&cart.Room,
 			&cart.Seat,
-			&cart.Price,
+			&cart.BasePrice,
+			&cart.Currency,
+			&cart.occupancy,
 		)
 		if err != nil {
 			return nil, NewSQLErr(err)
@@ -135,6 +169,11 @@ func (r *CartRepository) Find(ctx context.Context, filter CartFilter) ([]Cart, e
 		return nil, NewSQLErr(err)
 	}
 
+	err = r.applyPricing(ctx, carts)
+	if err != nil {
+		return nil, err
+	}
+
 	return carts, nil
 }
 
@@ -167,18 +206,36 @@ func (r *CartRepository) Pagination(ctx context.Context, filter CartFilter, page
 				c.seat_id,
 				c.created_at,
 				c.updated_at,
+				coalesce(c.ticket_type_id, 0),
 				m.title as movie,
 				s.start_at as showtime_start,
 				s.end_at as showtime_end,
 				r.name as room,
 				st."name" as seat,
-				s.price + st.additional_price as price
+				s.price + st.additional_price + coalesce(fs.amount, 0) as base_price,
+				coalesce(cn.currency, @default_currency) as currency,
+				coalesce((
+					select
+						count(ri.*) * 100 / nullif((select count(*) from seats _st where _st.room_id = s.room_id), 0)
+					from
+						reservation_items ri
+						join reservations rv on rv.id = ri.reservation_id
+					where
+						ri.showtime_id = s.id
+						and (
+							rv.status = 'paid'::reservation_status
+							or (rv.status = 'unpaid'::reservation_status
+								and rv.created_at > now() - interval '30 minutes')
+						)
+				), 0) as occupancy
 			from
 				public.carts c
 				join showtimes s on s.id  = c.showtime_id
 				join movies m on m.id = s.movie_id
 				join seats st on st.id = c.seat_id
 				join rooms r on r.id = st.room_id
+				left join cinemas cn on cn.id = r.cinema_id
+				left join format_surcharges fs on fs.format = s.format and fs.currency = coalesce(cn.currency, @default_currency)
 			where
 				c.id in (%s)
 			limit @page_size offset (@page - 1) * @page_size
@@ -188,8 +245,9 @@ func (r *CartRepository) Pagination(ctx context.Context, filter CartFilter, page
 	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
 		filterArgs,
 		pgx.NamedArgs{
-			"page":      page.Page,
-			"page_size": page.Size,
+			"page":             page.Page,
+			"page_size":        page.Size,
+			"default_currency": DefaultCurrency,
 		}),
 	)
 	if err != nil {
@@ -207,12 +265,15 @@ func (r *CartRepository) Pagination(ctx context.Context, filter CartFilter, page
 			&cart.SeatID,
 			&cart.CreatedAt,
 			&cart.UpdatedAt,
+			&cart.TicketTypeID,
 			&cart.Movie,
 			&cart.ShowtimeStart,
 			&cart.ShowtimeEnd,
 			&cart.Room,
 			&cart.Seat,
-			&cart.Price,
+			&cart.BasePrice,
+			&cart.Currency,
+			&cart.occupancy,
 		)
 		if err != nil {
 			return nil, NewSQLErr(err)
@@ -223,10 +284,64 @@ func (r *CartRepository) Pagination(ctx context.Context, filter CartFilter, page
 	if err != nil {
 		return nil, NewSQLErr(err)
 	}
+
+	err = r.applyPricing(ctx, carts)
+	if err != nil {
+		return nil, err
+	}
 	p.Items = carts
 	return p, nil
 }
 
+// applyPricing compute effective price of each cart line from active pricing rules,
+// then the chosen ticket type (or the default one) modify the price
+func (r *CartRepository) applyPricing(ctx context.Context, carts []Cart) error {
+	if len(carts) == 0 {
+		return nil
+	}
+	active := true
+	rules, err := NewPricingRuleRepository(r.tx).Find(ctx, PricingRuleFilter{IsActive: &active})
+	if err != nil {
+		return err
+	}
+	ticketTypes, err := NewTicketTypeRepository(r.tx).Find(ctx, TicketTypeFilter{})
+	if err != nil {
+		return err
+	}
+	ticketTypeMap := map[int64]TicketType{}
+	var defaultTicketType *TicketType
+	for i, ticketType := range ticketTypes {
+		ticketTypeMap[ticketType.ID] = ticketType
+		if ticketType.IsDefault {
+			defaultTicketType = &ticketTypes[i]
+		}
+	}
+
+	now := time.Now()
+	for i := range carts {
+		snapshot, err := ApplyPricingRules(carts[i].BasePrice, carts[i].Currency, PricingContext{
+			ShowtimeStart: carts[i].ShowtimeStart,
+			Now:           now,
+			Occupancy:     carts[i].occupancy,
+		}, rules)
+		if err != nil {
+			return err
+		}
+		if ticketType, ok := ticketTypeMap[carts[i].TicketTypeID]; ok {
+			err = snapshot.ApplyTicketType(ticketType, carts[i].Currency)
+		} else if defaultTicketType != nil {
+			carts[i].TicketTypeID = defaultTicketType.ID
+			err = snapshot.ApplyTicketType(*defaultTicketType, carts[i].Currency)
+		}
+		if err != nil {
+			return err
+		}
+		carts[i].Price = snapshot.Price
+		carts[i].Pricing = &snapshot
+	}
+	return nil
+}
+
 func (r *CartRepository) getFilterSQL(_ context.Context, filter CartFilter) (sql string, args pgx.NamedArgs) {
 	sql = `
 		select _c.id
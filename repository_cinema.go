@@ -0,0 +1,190 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewCinemaRepository(tx pgx.Tx) *CinemaRepository {
+	return &CinemaRepository{
+		tx: tx,
+	}
+}
+
+type CinemaRepository struct {
+	tx pgx.Tx
+}
+
+func (r *CinemaRepository) Create(ctx context.Context, cinema *Cinema) (int64, error) {
+	sql := `insert into public.cinemas (name, address, tax_rate, currency) values (@name, @address, @tax_rate, @currency) returning id`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"name":     cinema.Name,
+		"address":  cinema.Address,
+		"tax_rate": cinema.TaxRate,
+		"currency": cinema.Currency,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *CinemaRepository) UpdateByID(ctx context.Context, ID int64, input CinemaInput) error {
+	sql := `update public.cinemas set updated_at=now(), name=@name, address=@address, tax_rate=@tax_rate, currency=@currency where id=@id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":       ID,
+		"name":     input.Name,
+		"address":  input.Address,
+		"tax_rate": input.TaxRate,
+		"currency": input.Currency,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *CinemaRepository) DeleteByID(ctx context.Context, ID int64) error {
+	sql := `delete from public.cinemas where id=@id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *CinemaRepository) FindOne(ctx context.Context, filter CinemaFilter) (*Cinema, error) {
+	cinemas, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(cinemas) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "cinema not found")
+	}
+	return &cinemas[0], nil
+}
+
+func (r *CinemaRepository) Find(ctx context.Context, filter CinemaFilter) ([]Cinema, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+	sql := fmt.Sprintf(
+		`
+			select c.id, c.name, c.address, c.tax_rate, c.currency, c.created_at, c.updated_at, count(r.id) as total_room
+			from public.cinemas c
+			left join public.rooms r on c.id = r.cinema_id
+			where c.id in (%s)
+			group by c.id
+			order by c.name asc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var cinemas []Cinema
+	for rows.Next() {
+		var cinema Cinema
+		err := rows.Scan(&cinema.ID, &cinema.Name, &cinema.Address, &cinema.TaxRate, &cinema.Currency, &cinema.CreatedAt, &cinema.UpdatedAt, &cinema.TotalRoom)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		cinemas = append(cinemas, cinema)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	return cinemas, nil
+}
+
+func (r *CinemaRepository) Pagination(ctx context.Context, filter CinemaFilter, page PaginateInput) (*Paginate[Cinema], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]Cinema{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select c.id, c.name, c.address, c.tax_rate, c.currency, c.created_at, c.updated_at, count(r.id) as total_room
+			from public.cinemas c
+			left join public.rooms r on c.id = r.cinema_id
+			where c.id in (%s)
+			group by c.id
+			order by c.name asc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var cinemas []Cinema
+	for rows.Next() {
+		var cinema Cinema
+		err := rows.Scan(&cinema.ID, &cinema.Name, &cinema.Address, &cinema.TaxRate, &cinema.Currency, &cinema.CreatedAt, &cinema.UpdatedAt, &cinema.TotalRoom)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		cinemas = append(cinemas, cinema)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = cinemas
+	return p, nil
+}
+
+func (r *CinemaRepository) getFilterSQL(_ context.Context, filter CinemaFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _c.id
+		from public.cinemas _c
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_c.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_names::text[], 1) > 0 then
+					_c.name = any(@_names)
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":   filter.IDs,
+		"_names": filter.Names,
+	}
+	return sql, args
+}
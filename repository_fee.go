@@ -0,0 +1,178 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewFeeRepository(tx pgx.Tx) *FeeRepository {
+	return &FeeRepository{
+		tx: tx,
+	}
+}
+
+type FeeRepository struct {
+	tx pgx.Tx
+}
+
+func (r *FeeRepository) Create(ctx context.Context, fee *Fee) (int64, error) {
+	sql := `
+		insert into public.fees ("name", kind, amount, currency, cinema_id, is_active)
+		values (@name, @kind, @amount, @currency, nullif(@cinema_id, 0), @is_active)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"name":      fee.Name,
+		"kind":      fee.Kind,
+		"amount":    fee.Amount,
+		"currency":  fee.Currency,
+		"cinema_id": fee.CinemaID,
+		"is_active": fee.IsActive,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *FeeRepository) UpdateByID(ctx context.Context, ID int64, input FeeInput) error {
+	sql := `
+		update public.fees
+		set
+			updated_at = now(),
+			"name" = @name,
+			kind = @kind,
+			amount = @amount,
+			currency = @currency,
+			cinema_id = nullif(@cinema_id, 0),
+			is_active = @is_active
+		where id = @id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":        ID,
+		"name":      input.Name,
+		"kind":      input.Kind,
+		"amount":    input.Amount,
+		"currency":  input.Currency,
+		"cinema_id": input.CinemaID,
+		"is_active": input.IsActive,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *FeeRepository) DeleteByID(ctx context.Context, ID int64) error {
+	sql := `delete from public.fees where id = @id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *FeeRepository) FindOne(ctx context.Context, filter FeeFilter) (*Fee, error) {
+	fees, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(fees) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "fee not found")
+	}
+	return &fees[0], nil
+}
+
+func (r *FeeRepository) Find(ctx context.Context, filter FeeFilter) ([]Fee, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				f.id,
+				f."name",
+				f.kind,
+				f.amount,
+				f.currency,
+				coalesce(f.cinema_id, 0),
+				f.is_active,
+				f.created_at,
+				f.updated_at
+			from
+				public.fees f
+			where
+				f.id in (%s)
+			order by
+				f.id asc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var fees []Fee
+	for rows.Next() {
+		var fee Fee
+		err := rows.Scan(
+			&fee.ID,
+			&fee.Name,
+			&fee.Kind,
+			&fee.Amount,
+			&fee.Currency,
+			&fee.CinemaID,
+			&fee.IsActive,
+			&fee.CreatedAt,
+			&fee.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		fees = append(fees, fee)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	return fees, nil
+}
+
+func (r *FeeRepository) getFilterSQL(_ context.Context, filter FeeFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _f.id
+		from public.fees _f
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_f.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_cinema_ids::int[], 1) > 0 then
+					_f.cinema_id = any(@_cinema_ids)
+				else
+					true
+			end
+			and
+			case
+				when @_is_active::bool is not null then
+					_f.is_active = @_is_active
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":        filter.IDs,
+		"_cinema_ids": filter.CinemaIDs,
+		"_is_active":  filter.IsActive,
+	}
+	return sql, args
+}
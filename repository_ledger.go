@@ -0,0 +1,243 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewLedgerRepository(tx pgx.Tx) *LedgerRepository {
+	return &LedgerRepository{
+		tx: tx,
+	}
+}
+
+type LedgerRepository struct {
+	tx pgx.Tx
+}
+
+// CreateJournal post a balanced journal, accounts are created when first used
+func (r *LedgerRepository) CreateJournal(ctx context.Context, input LedgerJournalInput) (int64, error) {
+	err := input.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	accountIDs := make([]int64, 0, len(input.Entries))
+	for _, entry := range input.Entries {
+		ID, err := r.ensureAccount(ctx, entry.AccountCode)
+		if err != nil {
+			return 0, err
+		}
+		accountIDs = append(accountIDs, ID)
+	}
+
+	sql := `
+		insert into public.ledger_journals (kind, description, reference, reservation_id, gift_card_id)
+		values (@kind, @description, @reference, nullif(@reservation_id, 0), nullif(@gift_card_id, 0))
+		returning id
+	`
+	var journalID int64
+	err = r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"kind":           input.Kind,
+		"description":    input.Description,
+		"reference":      input.Reference,
+		"reservation_id": input.ReservationID,
+		"gift_card_id":   input.GiftCardID,
+	}).Scan(&journalID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+
+	sql = `
+		insert into public.ledger_entries (journal_id, account_id, amount)
+		values (@journal_id, @account_id, @amount)
+	`
+	batch := pgx.Batch{}
+	for i, entry := range input.Entries {
+		batch.Queue(sql, pgx.NamedArgs{
+			"journal_id": journalID,
+			"account_id": accountIDs[i],
+			"amount":     entry.Amount,
+		})
+	}
+	br := r.tx.SendBatch(ctx, &batch)
+
+	for range input.Entries {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return 0, NewSQLErr(err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return journalID, nil
+}
+
+func (r *LedgerRepository) ensureAccount(ctx context.Context, code string) (int64, error) {
+	sql := `
+		insert into public.ledger_accounts (code, "name", "type")
+		values (@code, @code, @type::public.ledger_account_type)
+		on conflict (code) do update set code = excluded.code
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"code": code,
+		"type": LedgerAccountTypeOf(code),
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// Balance is the sum of entries of the account, positive means debit balance
+func (r *LedgerRepository) Balance(ctx context.Context, code string) (int64, error) {
+	sql := `
+		select coalesce(sum(le.amount), 0)
+		from public.ledger_entries le
+		join public.ledger_accounts la on la.id = le.account_id
+		where la.code = @code
+	`
+	var balance int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"code": code}).Scan(&balance)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return balance, nil
+}
+
+func (r *LedgerRepository) Pagination(ctx context.Context, filter LedgerEntryFilter, page PaginateInput) (*Paginate[LedgerEntry], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]LedgerEntry{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				le.id,
+				le.journal_id,
+				lj.kind,
+				lj.description,
+				lj.reference,
+				coalesce(lj.reservation_id, 0),
+				coalesce(lj.gift_card_id, 0),
+				la.code,
+				le.amount,
+				le.created_at
+			from
+				public.ledger_entries le
+			join public.ledger_journals lj on
+				lj.id = le.journal_id
+			join public.ledger_accounts la on
+				la.id = le.account_id
+			where
+				le.id in (%s)
+			order by
+				le.id desc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var entries []LedgerEntry
+	for rows.Next() {
+		var entry LedgerEntry
+		err := rows.Scan(
+			&entry.ID,
+			&entry.JournalID,
+			&entry.Kind,
+			&entry.Description,
+			&entry.Reference,
+			&entry.ReservationID,
+			&entry.GiftCardID,
+			&entry.AccountCode,
+			&entry.Amount,
+			&entry.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		entries = append(entries, entry)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = entries
+	return p, nil
+}
+
+func (r *LedgerRepository) getFilterSQL(_ context.Context, filter LedgerEntryFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _le.id
+		from public.ledger_entries _le
+		join public.ledger_journals _lj on _lj.id = _le.journal_id
+		join public.ledger_accounts _la on _la.id = _le.account_id
+		where
+			case
+				when array_length(@_account_codes::text[], 1) > 0 then
+					_la.code = any(@_account_codes)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_reservation_ids::int[], 1) > 0 then
+					_lj.reservation_id = any(@_reservation_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_gift_card_ids::int[], 1) > 0 then
+					_lj.gift_card_id = any(@_gift_card_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_kinds::text[], 1) > 0 then
+					_lj.kind = any(@_kinds)
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_account_codes":   filter.AccountCodes,
+		"_reservation_ids": filter.ReservationIDs,
+		"_gift_card_ids":   filter.GiftCardIDs,
+		"_kinds":           filter.Kinds,
+	}
+	return sql, args
+}
@@ -0,0 +1,259 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewLoginAttemptRepository(tx pgx.Tx) *LoginAttemptRepository {
+	return &LoginAttemptRepository{
+		tx: tx,
+	}
+}
+
+type LoginAttemptRepository struct {
+	tx pgx.Tx
+}
+
+func (r *LoginAttemptRepository) Create(ctx context.Context, attempt *LoginAttempt) (int64, error) {
+	sql := `
+		insert into public.login_attempts (user_id, email, ip, user_agent, step, success, reason)
+		values (@user_id, @email, @ip, @user_agent, @step, @success, @reason)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"user_id":    attempt.UserID,
+		"email":      attempt.Email,
+		"ip":         attempt.IP,
+		"user_agent": attempt.UserAgent,
+		"step":       attempt.Step,
+		"success":    attempt.Success,
+		"reason":     attempt.Reason,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// Fail count a failed login of the key and return the consecutive failures,
+// the count start over when the previous failure is older than window
+func (r *LoginAttemptRepository) Fail(ctx context.Context, scope LoginThrottleScope, key string, window time.Duration) (int64, error) {
+	sql := `
+		insert into public.login_throttles as lt (scope, key, failures, last_failed_at)
+		values (@scope, @key, 1, now())
+		on conflict (scope, key) do update
+		set
+			failures = case
+				when lt.last_failed_at < now() - make_interval(secs => @window) then 1
+				else lt.failures + 1
+			end,
+			last_failed_at = now()
+		returning failures
+	`
+	var failures int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"scope":  scope,
+		"key":    key,
+		"window": window.Seconds(),
+	}).Scan(&failures)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return failures, nil
+}
+
+func (r *LoginAttemptRepository) Lock(ctx context.Context, scope LoginThrottleScope, key string, until time.Time) error {
+	sql := `update public.login_throttles set locked_until=@until where scope=@scope and key=@key`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"scope": scope, "key": key, "until": until})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// Reset forget the failures of the key, e.g. after successful login or unlocked by admin
+func (r *LoginAttemptRepository) Reset(ctx context.Context, scope LoginThrottleScope, key string) error {
+	sql := `delete from public.login_throttles where scope=@scope and key=@key`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"scope": scope, "key": key})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// Acquire lock the throttle of the key until the transaction end so attempts of the same key are counted one by one,
+// throttle is created without failure when the key never failed
+func (r *LoginAttemptRepository) Acquire(ctx context.Context, scope LoginThrottleScope, key string) (*LoginThrottle, error) {
+	sql := `
+		insert into public.login_throttles (scope, key)
+		values (@scope, @key)
+		on conflict (scope, key) do nothing
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"scope": scope, "key": key})
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	sql = `
+		select scope, key, failures, last_failed_at, locked_until
+		from public.login_throttles
+		where scope = @scope and key = @key
+		for update
+	`
+	var throttle LoginThrottle
+	err = r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"scope": scope, "key": key}).Scan(
+		&throttle.Scope,
+		&throttle.Key,
+		&throttle.Failures,
+		&throttle.LastFailedAt,
+		&throttle.LockedUntil,
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return &throttle, nil
+}
+
+// Forgive take back one failure counted before the login succeeded,
+// the lock it caused is lifted when the failures drop below maxFailures
+func (r *LoginAttemptRepository) Forgive(ctx context.Context, scope LoginThrottleScope, key string, maxFailures int64) error {
+	sql := `
+		update public.login_throttles
+		set
+			failures = greatest(failures - 1, 0),
+			locked_until = case when failures - 1 < @max_failures then null else locked_until end
+		where scope=@scope and key=@key
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"scope": scope, "key": key, "max_failures": maxFailures})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *LoginAttemptRepository) Pagination(ctx context.Context, filter LoginAttemptFilter, page PaginateInput) (*Paginate[LoginAttempt], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]LoginAttempt{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				la.id,
+				la.user_id,
+				la.email,
+				la.ip,
+				la.user_agent,
+				la.step,
+				la.success,
+				la.reason,
+				la.created_at
+			from
+				public.login_attempts la
+			where
+				la.id in (%s)
+			order by
+				la.id desc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var attempts []LoginAttempt
+	for rows.Next() {
+		var attempt LoginAttempt
+		err := rows.Scan(
+			&attempt.ID,
+			&attempt.UserID,
+			&attempt.Email,
+			&attempt.IP,
+			&attempt.UserAgent,
+			&attempt.Step,
+			&attempt.Success,
+			&attempt.Reason,
+			&attempt.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		attempts = append(attempts, attempt)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = attempts
+	return p, nil
+}
+
+func (r *LoginAttemptRepository) getFilterSQL(_ context.Context, filter LoginAttemptFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _la.id
+		from public.login_attempts _la
+		where
+			case
+				when array_length(@_user_ids::int[], 1) > 0 then
+					_la.user_id = any(@_user_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_emails::text[], 1) > 0 then
+					_la.email = any(@_emails)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_ips::text[], 1) > 0 then
+					_la.ip = any(@_ips)
+				else
+					true
+			end
+			and
+			case
+				when @_success::bool is not null then
+					_la.success = @_success
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_user_ids": filter.UserIDs,
+		"_emails":   filter.Emails,
+		"_ips":      filter.IPs,
+		"_success":  filter.Success,
+	}
+	return sql, args
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewMFARepository(tx pgx.Tx) *MFARepository {
+	return &MFARepository{
+		tx: tx,
+	}
+}
+
+type MFARepository struct {
+	tx pgx.Tx
+}
+
+// SetSecret start enrollment, two-factor is not enabled until confirmed
+func (r *MFARepository) SetSecret(ctx context.Context, userID int64, secret string) error {
+	sql := `update public.users set updated_at=now(), mfa_secret=@secret, mfa_enabled_at=null, mfa_last_step=0 where id=@id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": userID, "secret": secret})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *MFARepository) Enable(ctx context.Context, userID int64) error {
+	sql := `update public.users set updated_at=now(), mfa_enabled_at=now() where id=@id and mfa_secret is not null`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": userID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *MFARepository) Disable(ctx context.Context, userID int64) error {
+	sql := `update public.users set updated_at=now(), mfa_secret=null, mfa_enabled_at=null, mfa_last_step=0 where id=@id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": userID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return r.DeleteBackupCodes(ctx, userID)
+}
+
+// UseStep record the accepted TOTP time step, it fails when the step or a later one was already used
+func (r *MFARepository) UseStep(ctx context.Context, userID, step int64) error {
+	sql := `update public.users set mfa_last_step=@step where id=@id and mfa_last_step < @step returning id`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"id": userID, "step": step}).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "code already used")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// ReplaceBackupCodes remove the old codes, used or not, and store the new hashes
+func (r *MFARepository) ReplaceBackupCodes(ctx context.Context, userID int64, hashes []string) error {
+	err := r.DeleteBackupCodes(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sql := `
+		insert into public.mfa_backup_codes (user_id, code_hash)
+		select @user_id, unnest(@code_hashes::text[])
+	`
+	_, err = r.tx.Exec(ctx, sql, pgx.NamedArgs{"user_id": userID, "code_hashes": hashes})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *MFARepository) DeleteBackupCodes(ctx context.Context, userID int64) error {
+	sql := `delete from public.mfa_backup_codes where user_id=@user_id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// UseBackupCode mark the code as used, false when the code does not exist or already used
+func (r *MFARepository) UseBackupCode(ctx context.Context, userID int64, hash string) (bool, error) {
+	sql := `
+		update public.mfa_backup_codes
+		set used_at = now()
+		where user_id = @user_id and code_hash = @code_hash and used_at is null
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"user_id": userID, "code_hash": hash}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, NewSQLErr(err)
+	}
+	return true, nil
+}
+
+func (r *MFARepository) CountBackupCodes(ctx context.Context, userID int64) (int64, error) {
+	sql := `select count(*) from public.mfa_backup_codes where user_id=@user_id and used_at is null`
+	var count int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"user_id": userID}).Scan(&count)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return count, nil
+}
@@ -23,8 +23,8 @@ func (r *MovieRepository) Create(ctx context.Context, movie *Movie) (int64, erro
 	}
 
 	sql := `
-		insert into public.movies (title, release_date, director, duration, poster_url, description)
-		values (@title, @release_date, @director, @duration, @poster_url, @description)
+		insert into public.movies (title, release_date, director, duration, poster_url, description, original_language, min_age)
+		values (@title, @release_date, @director, @duration, @poster_url, @description, @original_language, @min_age)
 		returning id
 	`
 	var ID int64
@@ -32,12 +32,14 @@ func (r *MovieRepository) Create(ctx context.Context, movie *Movie) (int64, erro
 		ctx,
 		sql,
 		pgx.NamedArgs{
-			"title":        movie.Title,
-			"release_date": movie.ReleaseDate,
-			"director":     movie.Director,
-			"duration":     movie.Duration,
-			"poster_url":   movie.PosterURL,
-			"description":  movie.Description,
+			"title":             movie.Title,
+			"release_date":      movie.ReleaseDate,
+			"director":          movie.Director,
+			"duration":          movie.Duration,
+			"poster_url":        movie.PosterURL,
+			"description":       movie.Description,
+			"original_language": movie.OriginalLanguage,
+			"min_age":           movie.MinAge,
 		},
 	).Scan(&ID)
 	if err != nil {
@@ -66,6 +68,8 @@ func (r *MovieRepository) UpdateByID(ctx context.Context, ID int64, input MovieI
 			duration=@duration,
 			poster_url=@poster_url,
 			description=@description,
+			original_language=@original_language,
+			min_age=@min_age,
 			updated_at=NOW()
 		where id=@id
 	`
@@ -73,13 +77,15 @@ func (r *MovieRepository) UpdateByID(ctx context.Context, ID int64, input MovieI
 		ctx,
 		sql,
 		pgx.NamedArgs{
-			"id":           ID,
-			"title":        input.Title,
-			"release_date": input.ReleaseDate,
-			"director":     input.Director,
-			"duration":     input.Duration,
-			"poster_url":   input.PosterURL,
-			"description":  input.Description,
+			"id":                ID,
+			"title":             input.Title,
+			"release_date":      input.ReleaseDate,
+			"director":          input.Director,
+			"duration":          input.Duration,
+			"poster_url":        input.PosterURL,
+			"description":       input.Description,
+			"original_language": input.OriginalLanguage,
+			"min_age":           input.MinAge,
 		},
 	)
 	if err != nil {
@@ -144,6 +150,8 @@ func (r *MovieRepository) Find(ctx context.Context, filter MovieFilter) ([]Movie
 			m.duration,
 			m.poster_url,
 			m.description,
+			m.original_language,
+			m.min_age,
 			m.created_at,
 			m.updated_at
 		from
@@ -172,6 +180,8 @@ func (r *MovieRepository) Find(ctx context.Context, filter MovieFilter) ([]Movie
 			&movie.Duration,
 			&movie.PosterURL,
 			&movie.Description,
+			&movie.OriginalLanguage,
+			&movie.MinAge,
 			&movie.CreatedAt,
 			&movie.UpdatedAt,
 		)
@@ -266,6 +276,8 @@ func (r *MovieRepository) Paginate(ctx context.Context, filter MovieFilter, page
 			m.duration,
 			m.poster_url,
 			m.description,
+			m.original_language,
+			m.min_age,
 			m.created_at,
 			m.updated_at
 		from
@@ -301,6 +313,8 @@ func (r *MovieRepository) Paginate(ctx context.Context, filter MovieFilter, page
 			&movie.Duration,
 			&movie.PosterURL,
 			&movie.Description,
+			&movie.OriginalLanguage,
+			&movie.MinAge,
 			&movie.CreatedAt,
 			&movie.UpdatedAt,
 		)
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewOIDCRepository(tx pgx.Tx) *OIDCRepository {
+	return &OIDCRepository{
+		tx: tx,
+	}
+}
+
+type OIDCRepository struct {
+	tx pgx.Tx
+}
+
+func (r *OIDCRepository) CreateState(ctx context.Context, state *OIDCState) (int64, error) {
+	sql := `
+		insert into public.oidc_states (provider, state_hash, nonce, code_verifier, expires_at)
+		values (@provider, @state_hash, @nonce, @code_verifier, @expires_at)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"provider":      state.Provider,
+		"state_hash":    state.StateHash,
+		"nonce":         state.Nonce,
+		"code_verifier": state.CodeVerifier,
+		"expires_at":    state.ExpiresAt,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// UseState mark the state of the provider as used and return it, it only succeed once so the callback cannot be replayed
+func (r *OIDCRepository) UseState(ctx context.Context, provider, stateHash string) (*OIDCState, error) {
+	sql := `
+		update public.oidc_states
+		set used_at = now()
+		where provider = @provider and state_hash = @state_hash and used_at is null and expires_at > now()
+		returning id, provider, state_hash, nonce, code_verifier, expires_at, used_at, created_at
+	`
+	var state OIDCState
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"provider": provider, "state_hash": stateHash}).Scan(
+		&state.ID,
+		&state.Provider,
+		&state.StateHash,
+		&state.Nonce,
+		&state.CodeVerifier,
+		&state.ExpiresAt,
+		&state.UsedAt,
+		&state.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, NewErr(ErrInput, nil, "state invalid or expired, sign in again")
+	}
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return &state, nil
+}
+
+func (r *OIDCRepository) CreateIdentity(ctx context.Context, identity *UserIdentity) (int64, error) {
+	sql := `
+		insert into public.user_identities (user_id, provider, subject, email)
+		values (@user_id, @provider, @subject, @email)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"user_id":  identity.UserID,
+		"provider": identity.Provider,
+		"subject":  identity.Subject,
+		"email":    identity.Email,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *OIDCRepository) FindIdentity(ctx context.Context, provider, subject string) (*UserIdentity, error) {
+	sql := `
+		select id, user_id, provider, subject, email, created_at
+		from public.user_identities
+		where provider = @provider and subject = @subject
+	`
+	var identity UserIdentity
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"provider": provider, "subject": subject}).Scan(
+		&identity.ID,
+		&identity.UserID,
+		&identity.Provider,
+		&identity.Subject,
+		&identity.Email,
+		&identity.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, NewErr(ErrNotFound, nil, "identity not found")
+	}
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return &identity, nil
+}
+
+func (r *OIDCRepository) FindIdentitiesByUserID(ctx context.Context, userID int64) ([]UserIdentity, error) {
+	sql := `
+		select id, user_id, provider, subject, email, created_at
+		from public.user_identities
+		where user_id = @user_id
+		order by id
+	`
+	rows, err := r.tx.Query(ctx, sql, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	identities := []UserIdentity{}
+	for rows.Next() {
+		var identity UserIdentity
+		err := rows.Scan(
+			&identity.ID,
+			&identity.UserID,
+			&identity.Provider,
+			&identity.Subject,
+			&identity.Email,
+			&identity.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		identities = append(identities, identity)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return identities, nil
+}
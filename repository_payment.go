@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func NewPaymentRepository(tx pgx.Tx) *PaymentRepository {
+	return &PaymentRepository{
+		tx: tx,
+	}
+}
+
+type PaymentRepository struct {
+	tx pgx.Tx
+}
+
+func (r *PaymentRepository) Create(ctx context.Context, payment *Payment) (int64, error) {
+	sql := `
+		insert into public.payments (kind, status, user_id, reservation_id, amount, description, idempotency_key, reference)
+		values (@kind, @status, nullif(@user_id, 0), nullif(@reservation_id, 0), @amount, @description, @idempotency_key, @reference)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"kind":            payment.Kind,
+		"status":          payment.Status,
+		"user_id":         payment.UserID,
+		"reservation_id":  payment.ReservationID,
+		"amount":          payment.Amount,
+		"description":     payment.Description,
+		"idempotency_key": payment.IdempotencyKey,
+		"reference":       payment.Reference,
+	}).Scan(&ID)
+	var p *pgconn.PgError
+	if errors.As(err, &p) && p.Code == pgerrcode.UniqueViolation {
+		return 0, NewErr(ErrInput, err, "payment of the reservation is in progress")
+	}
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// Finish move pending payment to its final status, a payment is only finished once
+func (r *PaymentRepository) Finish(ctx context.Context, ID int64, status PaymentStatus, reference string) error {
+	sql := `
+		update public.payments
+		set status = @status, reference = @reference, updated_at = now()
+		where id = @id and status = @pending
+		returning id
+	`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"id":        ID,
+		"status":    status,
+		"reference": reference,
+		"pending":   PaymentPending,
+	}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "payment is not pending")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// SetReference keep the gateway reference of payment that could not be finished
+func (r *PaymentRepository) SetReference(ctx context.Context, ID int64, reference string) error {
+	sql := `update public.payments set reference = @reference, updated_at = now() where id = @id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":        ID,
+		"reference": reference,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
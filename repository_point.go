@@ -0,0 +1,370 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewPointRepository(tx pgx.Tx) *PointRepository {
+	return &PointRepository{
+		tx: tx,
+	}
+}
+
+type PointRepository struct {
+	tx pgx.Tx
+}
+
+func (r *PointRepository) CreateTransaction(ctx context.Context, transaction *PointTransaction) (int64, error) {
+	sql := `
+		insert into public.point_transactions (user_id, reservation_id, kind, points, remaining, expires_at, description)
+		values (@user_id, nullif(@reservation_id, 0), @kind::public.point_transaction_kind, @points, @remaining, @expires_at, @description)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"user_id":        transaction.UserID,
+		"reservation_id": transaction.ReservationID,
+		"kind":           transaction.Kind,
+		"points":         transaction.Points,
+		"remaining":      transaction.Remaining,
+		"expires_at":     transaction.ExpiresAt,
+		"description":    transaction.Description,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// AddRemaining change unconsumed points of an earn lot, negative to consume
+func (r *PointRepository) AddRemaining(ctx context.Context, lotID, points int64) error {
+	sql := `update public.point_transactions set remaining = remaining + @points where id = @id and kind = 'earn'`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":     lotID,
+		"points": points,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// FindLots lock earn lots of the user that still have remaining points,
+// so concurrent redemption cannot consume the same points twice
+func (r *PointRepository) FindLots(ctx context.Context, userID int64) ([]PointTransaction, error) {
+	return r.findLots(ctx, userID, "pt.remaining > 0")
+}
+
+// FindDebts lock earn lots of the user reversed after their points were spent, negative remaining is owed
+func (r *PointRepository) FindDebts(ctx context.Context, userID int64) ([]PointTransaction, error) {
+	return r.findLots(ctx, userID, "pt.remaining < 0")
+}
+
+func (r *PointRepository) findLots(ctx context.Context, userID int64, condition string) ([]PointTransaction, error) {
+	sql := fmt.Sprintf(
+		`
+			select
+				pt.id,
+				pt.user_id,
+				coalesce(pt.reservation_id, 0),
+				pt.kind,
+				pt.points,
+				pt.remaining,
+				pt.expires_at,
+				pt.description,
+				pt.created_at
+			from
+				public.point_transactions pt
+			where
+				pt.user_id = @user_id
+				and pt.kind = 'earn'
+				and %s
+			order by
+				pt.expires_at asc nulls last,
+				pt.id asc
+			for update
+		`,
+		condition,
+	)
+	rows, err := r.tx.Query(ctx, sql, pgx.NamedArgs{"user_id": userID})
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var lots []PointTransaction
+	for rows.Next() {
+		var lot PointTransaction
+		err := rows.Scan(
+			&lot.ID,
+			&lot.UserID,
+			&lot.ReservationID,
+			&lot.Kind,
+			&lot.Points,
+			&lot.Remaining,
+			&lot.ExpiresAt,
+			&lot.Description,
+			&lot.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		lots = append(lots, lot)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return lots, nil
+}
+
+func (r *PointRepository) CreateAllocations(ctx context.Context, allocations []PointAllocation) error {
+	sql := `
+		insert into public.point_allocations (transaction_id, lot_id, points)
+		values (@transaction_id, @lot_id, @points)
+	`
+	batch := pgx.Batch{}
+	for _, allocation := range allocations {
+		batch.Queue(sql, pgx.NamedArgs{
+			"transaction_id": allocation.TransactionID,
+			"lot_id":         allocation.LotID,
+			"points":         allocation.Points,
+		})
+	}
+	br := r.tx.SendBatch(ctx, &batch)
+
+	for range allocations {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return NewSQLErr(err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// AllocatedPoints is the points of the lot spent, by redemption still standing or to repay other lot
+func (r *PointRepository) AllocatedPoints(ctx context.Context, lotID int64) (int64, error) {
+	sql := `select coalesce(sum(points), 0) from public.point_allocations where lot_id = @lot_id`
+	var points int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"lot_id": lotID}).Scan(&points)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return points, nil
+}
+
+// DeleteAllocations remove and return lots consumed by redeem transactions of the reservation
+func (r *PointRepository) DeleteAllocations(ctx context.Context, reservationID int64) ([]PointAllocation, error) {
+	sql := `
+		delete from public.point_allocations pa
+		using public.point_transactions pt
+		where
+			pa.transaction_id = pt.id
+			and pt.reservation_id = @reservation_id
+			and pt.kind = 'redeem'
+		returning pa.id, pa.transaction_id, pa.lot_id, pa.points
+	`
+	rows, err := r.tx.Query(ctx, sql, pgx.NamedArgs{"reservation_id": reservationID})
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var allocations []PointAllocation
+	for rows.Next() {
+		var allocation PointAllocation
+		err := rows.Scan(
+			&allocation.ID,
+			&allocation.TransactionID,
+			&allocation.LotID,
+			&allocation.Points,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		allocations = append(allocations, allocation)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return allocations, nil
+}
+
+func (r *PointRepository) Find(ctx context.Context, filter PointTransactionFilter) ([]PointTransaction, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				pt.id,
+				pt.user_id,
+				coalesce(pt.reservation_id, 0),
+				pt.kind,
+				pt.points,
+				pt.remaining,
+				pt.expires_at,
+				pt.description,
+				pt.created_at
+			from
+				public.point_transactions pt
+			where
+				pt.id in (%s)
+			order by
+				pt.id desc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var transactions []PointTransaction
+	for rows.Next() {
+		var transaction PointTransaction
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.UserID,
+			&transaction.ReservationID,
+			&transaction.Kind,
+			&transaction.Points,
+			&transaction.Remaining,
+			&transaction.ExpiresAt,
+			&transaction.Description,
+			&transaction.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return transactions, nil
+}
+
+func (r *PointRepository) Pagination(ctx context.Context, filter PointTransactionFilter, page PaginateInput) (*Paginate[PointTransaction], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]PointTransaction{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				pt.id,
+				pt.user_id,
+				coalesce(pt.reservation_id, 0),
+				pt.kind,
+				pt.points,
+				pt.remaining,
+				pt.expires_at,
+				pt.description,
+				pt.created_at
+			from
+				public.point_transactions pt
+			where
+				pt.id in (%s)
+			order by
+				pt.id desc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var transactions []PointTransaction
+	for rows.Next() {
+		var transaction PointTransaction
+		err := rows.Scan(
+			&transaction.ID,
+			&transaction.UserID,
+			&transaction.ReservationID,
+			&transaction.Kind,
+			&transaction.Points,
+			&transaction.Remaining,
+			&transaction.ExpiresAt,
+			&transaction.Description,
+			&transaction.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		transactions = append(transactions, transaction)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = transactions
+	return p, nil
+}
+
+func (r *PointRepository) getFilterSQL(_ context.Context, filter PointTransactionFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _pt.id
+		from public.point_transactions _pt
+		where
+			case
+				when array_length(@_user_ids::int[], 1) > 0 then
+					_pt.user_id = any(@_user_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_reservation_ids::int[], 1) > 0 then
+					_pt.reservation_id = any(@_reservation_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_kinds::text[], 1) > 0 then
+					_pt.kind::text = any(@_kinds)
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_user_ids":        filter.UserIDs,
+		"_reservation_ids": filter.ReservationIDs,
+		"_kinds":           filter.Kinds,
+	}
+	return sql, args
+}
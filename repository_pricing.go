@@ -0,0 +1,355 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewPricingRuleRepository(tx pgx.Tx) *PricingRuleRepository {
+	return &PricingRuleRepository{
+		tx: tx,
+	}
+}
+
+type PricingRuleRepository struct {
+	tx pgx.Tx
+}
+
+func (r *PricingRuleRepository) Create(ctx context.Context, rule *PricingRule) (int64, error) {
+	sql := `
+		insert into public.pricing_rules (
+			"name",
+			kind,
+			"percent",
+			amount,
+			currency,
+			priority,
+			is_active,
+			timezone,
+			weekdays,
+			start_clock,
+			end_clock,
+			dates,
+			min_days_before,
+			min_occupancy,
+			max_occupancy
+		)
+		values (
+			@name,
+			@kind::public.pricing_rule_kind,
+			@percent,
+			@amount,
+			@currency,
+			@priority,
+			@is_active,
+			@timezone,
+			@weekdays,
+			@start_clock,
+			@end_clock,
+			@dates,
+			@min_days_before,
+			@min_occupancy,
+			@max_occupancy
+		)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"name":            rule.Name,
+		"kind":            rule.Kind,
+		"percent":         rule.Percent,
+		"amount":          rule.Amount,
+		"currency":        rule.Currency,
+		"priority":        rule.Priority,
+		"is_active":       rule.IsActive,
+		"timezone":        rule.Timezone,
+		"weekdays":        rule.Weekdays,
+		"start_clock":     rule.StartClock,
+		"end_clock":       rule.EndClock,
+		"dates":           rule.Dates,
+		"min_days_before": rule.MinDaysBefore,
+		"min_occupancy":   rule.MinOccupancy,
+		"max_occupancy":   rule.MaxOccupancy,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *PricingRuleRepository) UpdateByID(ctx context.Context, ID int64, input PricingRuleInput) error {
+	sql := `
+		update public.pricing_rules
+		set
+			updated_at = now(),
+			"name" = @name,
+			kind = @kind::public.pricing_rule_kind,
+			"percent" = @percent,
+			amount = @amount,
+			currency = @currency,
+			priority = @priority,
+			is_active = @is_active,
+			timezone = @timezone,
+			weekdays = @weekdays,
+			start_clock = @start_clock,
+			end_clock = @end_clock,
+			dates = @dates,
+			min_days_before = @min_days_before,
+			min_occupancy = @min_occupancy,
+			max_occupancy = @max_occupancy
+		where id = @id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":              ID,
+		"name":            input.Name,
+		"kind":            input.Kind,
+		"percent":         input.Percent,
+		"amount":          input.Amount,
+		"currency":        input.Currency,
+		"priority":        input.Priority,
+		"is_active":       input.IsActive,
+		"timezone":        input.Timezone,
+		"weekdays":        input.Weekdays,
+		"start_clock":     input.StartClock,
+		"end_clock":       input.EndClock,
+		"dates":           input.Dates,
+		"min_days_before": input.MinDaysBefore,
+		"min_occupancy":   input.MinOccupancy,
+		"max_occupancy":   input.MaxOccupancy,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *PricingRuleRepository) DeleteByID(ctx context.Context, ID int64) error {
+	sql := `delete from public.pricing_rules where id = @id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *PricingRuleRepository) FindOne(ctx context.Context, filter PricingRuleFilter) (*PricingRule, error) {
+	rules, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "pricing rule not found")
+	}
+	return &rules[0], nil
+}
+
+func (r *PricingRuleRepository) Find(ctx context.Context, filter PricingRuleFilter) ([]PricingRule, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				pr.id,
+				pr."name",
+				pr.kind,
+				pr."percent",
+				pr.amount,
+				pr.currency,
+				pr.priority,
+				pr.is_active,
+				pr.timezone,
+				pr.weekdays,
+				pr.start_clock,
+				pr.end_clock,
+				pr.dates,
+				pr.min_days_before,
+				pr.min_occupancy,
+				pr.max_occupancy,
+				pr.created_at,
+				pr.updated_at
+			from
+				public.pricing_rules pr
+			where
+				pr.id in (%s)
+			order by
+				pr.priority desc,
+				pr.id asc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var rules []PricingRule
+	for rows.Next() {
+		var rule PricingRule
+		err := rows.Scan(
+			&rule.ID,
+			&rule.Name,
+			&rule.Kind,
+			&rule.Percent,
+			&rule.Amount,
+			&rule.Currency,
+			&rule.Priority,
+			&rule.IsActive,
+			&rule.Timezone,
+			&rule.Weekdays,
+			&rule.StartClock,
+			&rule.EndClock,
+			&rule.Dates,
+			&rule.MinDaysBefore,
+			&rule.MinOccupancy,
+			&rule.MaxOccupancy,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		rules = append(rules, rule)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	return rules, nil
+}
+
+func (r *PricingRuleRepository) Pagination(ctx context.Context, filter PricingRuleFilter, page PaginateInput) (*Paginate[PricingRule], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]PricingRule{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				pr.id,
+				pr."name",
+				pr.kind,
+				pr."percent",
+				pr.amount,
+				pr.currency,
+				pr.priority,
+				pr.is_active,
+				pr.timezone,
+				pr.weekdays,
+				pr.start_clock,
+				pr.end_clock,
+				pr.dates,
+				pr.min_days_before,
+				pr.min_occupancy,
+				pr.max_occupancy,
+				pr.created_at,
+				pr.updated_at
+			from
+				public.pricing_rules pr
+			where
+				pr.id in (%s)
+			order by
+				pr.priority desc,
+				pr.id asc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var rules []PricingRule
+	for rows.Next() {
+		var rule PricingRule
+		err := rows.Scan(
+			&rule.ID,
+			&rule.Name,
+			&rule.Kind,
+			&rule.Percent,
+			&rule.Amount,
+			&rule.Currency,
+			&rule.Priority,
+			&rule.IsActive,
+			&rule.Timezone,
+			&rule.Weekdays,
+			&rule.StartClock,
+			&rule.EndClock,
+			&rule.Dates,
+			&rule.MinDaysBefore,
+			&rule.MinOccupancy,
+			&rule.MaxOccupancy,
+			&rule.CreatedAt,
+			&rule.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		rules = append(rules, rule)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = rules
+	return p, nil
+}
+
+func (r *PricingRuleRepository) getFilterSQL(_ context.Context, filter PricingRuleFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _pr.id
+		from public.pricing_rules _pr
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_pr.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_kinds::public.pricing_rule_kind[], 1) > 0 then
+					_pr.kind = any(@_kinds::public.pricing_rule_kind[])
+				else
+					true
+			end
+			and
+			case
+				when @_is_active::bool is not null then
+					_pr.is_active = @_is_active
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":       filter.IDs,
+		"_kinds":     filter.Kinds,
+		"_is_active": filter.IsActive,
+	}
+	return sql, args
+}
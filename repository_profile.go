@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewProfileRepository(tx pgx.Tx) *ProfileRepository {
+	return &ProfileRepository{
+		tx: tx,
+	}
+}
+
+type ProfileRepository struct {
+	tx pgx.Tx
+}
+
+// FindByUserID return empty profile when the user never saved one
+func (r *ProfileRepository) FindByUserID(ctx context.Context, userID int64) (*UserProfile, error) {
+	sql := `
+		select
+			p.user_id,
+			p.display_name,
+			p.phone,
+			coalesce(to_char(p.birth_date, 'YYYY-MM-DD'), ''),
+			p.preferred_cinema_id,
+			p.language,
+			p.marketing_consent,
+			p.marketing_consent_at,
+			p.updated_at
+		from
+			public.user_profiles p
+		where
+			p.user_id = @user_id
+	`
+	var profile UserProfile
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"user_id": userID}).Scan(
+		&profile.UserID,
+		&profile.DisplayName,
+		&profile.Phone,
+		&profile.BirthDate,
+		&profile.PreferredCinemaID,
+		&profile.Language,
+		&profile.MarketingConsent,
+		&profile.MarketingConsentAt,
+		&profile.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &UserProfile{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return &profile, nil
+}
+
+func (r *ProfileRepository) Save(ctx context.Context, profile *UserProfile) error {
+	sql := `
+		insert into public.user_profiles (
+			user_id, display_name, phone, birth_date, preferred_cinema_id, language, marketing_consent, marketing_consent_at, updated_at
+		)
+		values (
+			@user_id, @display_name, @phone, nullif(@birth_date, '')::date, @preferred_cinema_id, @language, @marketing_consent, @marketing_consent_at, @updated_at
+		)
+		on conflict (user_id) do update set
+			display_name=excluded.display_name,
+			phone=excluded.phone,
+			birth_date=excluded.birth_date,
+			preferred_cinema_id=excluded.preferred_cinema_id,
+			language=excluded.language,
+			marketing_consent=excluded.marketing_consent,
+			marketing_consent_at=excluded.marketing_consent_at,
+			updated_at=excluded.updated_at
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"user_id":              profile.UserID,
+		"display_name":         profile.DisplayName,
+		"phone":                profile.Phone,
+		"birth_date":           profile.BirthDate,
+		"preferred_cinema_id":  profile.PreferredCinemaID,
+		"language":             profile.Language,
+		"marketing_consent":    profile.MarketingConsent,
+		"marketing_consent_at": profile.MarketingConsentAt,
+		"updated_at":           profile.UpdatedAt,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *ProfileRepository) CreateChange(ctx context.Context, change *UserProfileChange) (int64, error) {
+	sql := `
+		insert into public.user_profile_changes (user_id, field, old_value, new_value, created_at)
+		values (@user_id, @field, @old_value, @new_value, @created_at)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"user_id":    change.UserID,
+		"field":      change.Field,
+		"old_value":  change.OldValue,
+		"new_value":  change.NewValue,
+		"created_at": change.CreatedAt,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *ProfileRepository) PaginateChanges(ctx context.Context, userID int64, page PaginateInput) (*Paginate[UserProfileChange], error) {
+	var totalItems int64
+	sql := `select count(*) from public.user_profile_changes where user_id = @user_id`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"user_id": userID}).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]UserProfileChange{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = `
+		select id, user_id, field, old_value, new_value, created_at
+		from public.user_profile_changes
+		where user_id = @user_id
+		order by id desc
+		limit @page_size offset (@page - 1) * @page_size
+	`
+	rows, err := r.tx.Query(ctx, sql, pgx.NamedArgs{
+		"user_id":   userID,
+		"page":      page.Page,
+		"page_size": page.Size,
+	})
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var changes []UserProfileChange
+	for rows.Next() {
+		var change UserProfileChange
+		err := rows.Scan(&change.ID, &change.UserID, &change.Field, &change.OldValue, &change.NewValue, &change.CreatedAt)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		changes = append(changes, change)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	p.Items = changes
+	return p, nil
+}
@@ -0,0 +1,440 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewPromoCodeRepository(tx pgx.Tx) *PromoCodeRepository {
+	return &PromoCodeRepository{
+		tx: tx,
+	}
+}
+
+type PromoCodeRepository struct {
+	tx pgx.Tx
+}
+
+func (r *PromoCodeRepository) Create(ctx context.Context, promoCode *PromoCode) (int64, error) {
+	sql := `
+		insert into public.promo_codes (
+			code,
+			description,
+			discount_type,
+			discount_value,
+			max_discount,
+			min_total_price,
+			currency,
+			start_at,
+			end_at,
+			usage_limit,
+			per_user_limit,
+			is_active,
+			movie_ids,
+			genre_ids,
+			showtime_ids,
+			seat_categories
+		)
+		values (
+			@code,
+			@description,
+			@discount_type::public.promo_discount_type,
+			@discount_value,
+			@max_discount,
+			@min_total_price,
+			@currency,
+			@start_at,
+			@end_at,
+			@usage_limit,
+			@per_user_limit,
+			@is_active,
+			@movie_ids,
+			@genre_ids,
+			@showtime_ids,
+			@seat_categories
+		)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"code":            promoCode.Code,
+		"description":     promoCode.Description,
+		"discount_type":   promoCode.DiscountType,
+		"discount_value":  promoCode.DiscountValue,
+		"max_discount":    promoCode.MaxDiscount,
+		"min_total_price": promoCode.MinTotalPrice,
+		"currency":        promoCode.Currency,
+		"start_at":        promoCode.StartAt,
+		"end_at":          promoCode.EndAt,
+		"usage_limit":     promoCode.UsageLimit,
+		"per_user_limit":  promoCode.PerUserLimit,
+		"is_active":       promoCode.IsActive,
+		"movie_ids":       promoCode.MovieIDs,
+		"genre_ids":       promoCode.GenreIDs,
+		"showtime_ids":    promoCode.ShowtimeIDs,
+		"seat_categories": promoCode.SeatCategories,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *PromoCodeRepository) UpdateByID(ctx context.Context, ID int64, input PromoCodeInput) error {
+	sql := `
+		update public.promo_codes
+		set
+			updated_at = now(),
+			code = @code,
+			description = @description,
+			discount_type = @discount_type::public.promo_discount_type,
+			discount_value = @discount_value,
+			max_discount = @max_discount,
+			min_total_price = @min_total_price,
+			currency = @currency,
+			start_at = @start_at,
+			end_at = @end_at,
+			usage_limit = @usage_limit,
+			per_user_limit = @per_user_limit,
+			is_active = @is_active,
+			movie_ids = @movie_ids,
+			genre_ids = @genre_ids,
+			showtime_ids = @showtime_ids,
+			seat_categories = @seat_categories
+		where id = @id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":              ID,
+		"code":            input.Code,
+		"description":     input.Description,
+		"discount_type":   input.DiscountType,
+		"discount_value":  input.DiscountValue,
+		"max_discount":    input.MaxDiscount,
+		"min_total_price": input.MinTotalPrice,
+		"currency":        input.Currency,
+		"start_at":        input.StartAt,
+		"end_at":          input.EndAt,
+		"usage_limit":     input.UsageLimit,
+		"per_user_limit":  input.PerUserLimit,
+		"is_active":       input.IsActive,
+		"movie_ids":       input.MovieIDs,
+		"genre_ids":       input.GenreIDs,
+		"showtime_ids":    input.ShowtimeIDs,
+		"seat_categories": input.SeatCategories,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *PromoCodeRepository) DeleteByID(ctx context.Context, ID int64) error {
+	sql := `delete from public.promo_codes where id = @id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// IncrementUsage take one slot of the usage limit.
+// The conditional update lock the row, so concurrent checkout wait
+// for each other and the limit can never be oversubscribed
+func (r *PromoCodeRepository) IncrementUsage(ctx context.Context, ID int64) error {
+	sql := `
+		update public.promo_codes
+		set used_count = used_count + 1, updated_at = now()
+		where id = @id and (usage_limit = 0 or used_count < usage_limit)
+		returning id
+	`
+	var updatedID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"id": ID}).Scan(&updatedID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "promo code usage limit reached")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// ReleaseUsage delete usage of the reservation and give back its slot,
+// releasing twice is a no-op because the usage is already gone
+func (r *PromoCodeRepository) ReleaseUsage(ctx context.Context, reservationID int64) error {
+	sql := `
+		with deleted as (
+			delete from public.promo_code_usages
+			where reservation_id = @reservation_id
+			returning promo_code_id
+		)
+		update public.promo_codes
+		set used_count = greatest(used_count - 1, 0), updated_at = now()
+		where id in (select promo_code_id from deleted)
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"reservation_id": reservationID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// CreateUsage record the usage only when the user is below perUserLimit, 0 is unlimited.
+// Count and insert are one statement and the caller hold the promo code row lock from IncrementUsage,
+// so concurrent checkout of the same user cannot both pass the count
+func (r *PromoCodeRepository) CreateUsage(ctx context.Context, usage PromoCodeUsage, perUserLimit int64) error {
+	sql := `
+		insert into public.promo_code_usages (promo_code_id, user_id, reservation_id, discount)
+		select @promo_code_id, @user_id, @reservation_id, @discount
+		where
+			@per_user_limit::bigint = 0
+			or (
+				select count(*)
+				from public.promo_code_usages
+				where promo_code_id = @promo_code_id and user_id = @user_id
+			) < @per_user_limit::bigint
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"promo_code_id":  usage.PromoCodeID,
+		"user_id":        usage.UserID,
+		"reservation_id": usage.ReservationID,
+		"discount":       usage.Discount,
+		"per_user_limit": perUserLimit,
+	}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "promo code usage limit per user reached")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *PromoCodeRepository) FindOne(ctx context.Context, filter PromoCodeFilter) (*PromoCode, error) {
+	promoCodes, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(promoCodes) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "promo code not found")
+	}
+	return &promoCodes[0], nil
+}
+
+func (r *PromoCodeRepository) Find(ctx context.Context, filter PromoCodeFilter) ([]PromoCode, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				pc.id,
+				pc.code,
+				pc.description,
+				pc.discount_type,
+				pc.discount_value,
+				pc.max_discount,
+				pc.min_total_price,
+				pc.currency,
+				pc.start_at,
+				pc.end_at,
+				pc.usage_limit,
+				pc.per_user_limit,
+				pc.used_count,
+				pc.is_active,
+				pc.movie_ids,
+				pc.genre_ids,
+				pc.showtime_ids,
+				pc.seat_categories,
+				pc.created_at,
+				pc.updated_at
+			from
+				public.promo_codes pc
+			where
+				pc.id in (%s)
+			order by
+				pc.id desc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var promoCodes []PromoCode
+	for rows.Next() {
+		var promoCode PromoCode
+		err := rows.Scan(
+			&promoCode.ID,
+			&promoCode.Code,
+			&promoCode.Description,
+			&promoCode.DiscountType,
+			&promoCode.DiscountValue,
+			&promoCode.MaxDiscount,
+			&promoCode.MinTotalPrice,
+			&promoCode.Currency,
+			&promoCode.StartAt,
+			&promoCode.EndAt,
+			&promoCode.UsageLimit,
+			&promoCode.PerUserLimit,
+			&promoCode.UsedCount,
+			&promoCode.IsActive,
+			&promoCode.MovieIDs,
+			&promoCode.GenreIDs,
+			&promoCode.ShowtimeIDs,
+			&promoCode.SeatCategories,
+			&promoCode.CreatedAt,
+			&promoCode.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		promoCodes = append(promoCodes, promoCode)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return promoCodes, nil
+}
+
+func (r *PromoCodeRepository) Pagination(ctx context.Context, filter PromoCodeFilter, page PaginateInput) (*Paginate[PromoCode], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]PromoCode{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				pc.id,
+				pc.code,
+				pc.description,
+				pc.discount_type,
+				pc.discount_value,
+				pc.max_discount,
+				pc.min_total_price,
+				pc.currency,
+				pc.start_at,
+				pc.end_at,
+				pc.usage_limit,
+				pc.per_user_limit,
+				pc.used_count,
+				pc.is_active,
+				pc.movie_ids,
+				pc.genre_ids,
+				pc.showtime_ids,
+				pc.seat_categories,
+				pc.created_at,
+				pc.updated_at
+			from
+				public.promo_codes pc
+			where
+				pc.id in (%s)
+			order by
+				pc.id desc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var promoCodes []PromoCode
+	for rows.Next() {
+		var promoCode PromoCode
+		err := rows.Scan(
+			&promoCode.ID,
+			&promoCode.Code,
+			&promoCode.Description,
+			&promoCode.DiscountType,
+			&promoCode.DiscountValue,
+			&promoCode.MaxDiscount,
+			&promoCode.MinTotalPrice,
+			&promoCode.Currency,
+			&promoCode.StartAt,
+			&promoCode.EndAt,
+			&promoCode.UsageLimit,
+			&promoCode.PerUserLimit,
+			&promoCode.UsedCount,
+			&promoCode.IsActive,
+			&promoCode.MovieIDs,
+			&promoCode.GenreIDs,
+			&promoCode.ShowtimeIDs,
+			&promoCode.SeatCategories,
+			&promoCode.CreatedAt,
+			&promoCode.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		promoCodes = append(promoCodes, promoCode)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = promoCodes
+	return p, nil
+}
+
+func (r *PromoCodeRepository) getFilterSQL(_ context.Context, filter PromoCodeFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _pc.id
+		from public.promo_codes _pc
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_pc.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_codes::text[], 1) > 0 then
+					_pc.code = any(@_codes)
+				else
+					true
+			end
+			and
+			case
+				when @_is_active::bool is not null then
+					_pc.is_active = @_is_active
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":       filter.IDs,
+		"_codes":     filter.Codes,
+		"_is_active": filter.IsActive,
+	}
+	return sql, args
+}
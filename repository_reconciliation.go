@@ -0,0 +1,219 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewReconciliationRepository(tx pgx.Tx) *ReconciliationRepository {
+	return &ReconciliationRepository{
+		tx: tx,
+	}
+}
+
+type ReconciliationRepository struct {
+	tx pgx.Tx
+}
+
+// ReservationLedgers sum the receivable entries of every reservation
+func (r *ReconciliationRepository) ReservationLedgers(ctx context.Context) ([]ReservationLedger, error) {
+	sql := `
+		select
+			r.id,
+			r.status,
+			r.total_price,
+			coalesce(sum(le.amount) filter (where lj.kind in (@sale, @sale_reversal)), 0),
+			coalesce(-sum(le.amount) filter (where lj.kind = @payment), 0),
+			coalesce(sum(le.amount) filter (where lj.kind = @refund), 0)
+		from
+			public.reservations r
+		left join public.ledger_journals lj on
+			lj.reservation_id = r.id
+		left join public.ledger_entries le on
+			le.journal_id = lj.id
+			and le.account_id = (select id from public.ledger_accounts where code = @receivable)
+		group by
+			r.id
+		order by
+			r.id
+	`
+	rows, err := r.tx.Query(ctx, sql, pgx.NamedArgs{
+		"sale":          JournalSale,
+		"sale_reversal": JournalSaleReversal,
+		"payment":       JournalPayment,
+		"refund":        JournalRefund,
+		"receivable":    AccountReceivable,
+	})
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var ledgers []ReservationLedger
+	for rows.Next() {
+		var ledger ReservationLedger
+		err := rows.Scan(
+			&ledger.ReservationID,
+			&ledger.Status,
+			&ledger.TotalPrice,
+			&ledger.Sold,
+			&ledger.Collected,
+			&ledger.Refunded,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		ledgers = append(ledgers, ledger)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return ledgers, nil
+}
+
+// CreateIssues store the issues, issue already detected on the same day is skipped
+func (r *ReconciliationRepository) CreateIssues(ctx context.Context, issues []ReconciliationIssue) error {
+	sql := `
+		insert into public.reconciliation_issues (reservation_id, status, issue, total_price, sold, collected, refunded, detected_on)
+		values (@reservation_id, @status::public.reservation_status, @issue, @total_price, @sold, @collected, @refunded, @detected_on)
+		on conflict (reservation_id, detected_on, issue) do nothing
+	`
+	batch := pgx.Batch{}
+	for _, issue := range issues {
+		batch.Queue(sql, pgx.NamedArgs{
+			"reservation_id": issue.ReservationID,
+			"status":         issue.Status,
+			"issue":          issue.Issue,
+			"total_price":    issue.TotalPrice,
+			"sold":           issue.Sold,
+			"collected":      issue.Collected,
+			"refunded":       issue.Refunded,
+			"detected_on":    issue.DetectedOn,
+		})
+	}
+	br := r.tx.SendBatch(ctx, &batch)
+
+	for range issues {
+		_, err := br.Exec()
+		if err != nil {
+			br.Close()
+			return NewSQLErr(err)
+		}
+	}
+
+	if err := br.Close(); err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *ReconciliationRepository) Pagination(ctx context.Context, filter ReconciliationIssueFilter, page PaginateInput) (*Paginate[ReconciliationIssue], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]ReconciliationIssue{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				ri.id,
+				ri.reservation_id,
+				ri.status,
+				ri.issue,
+				ri.total_price,
+				ri.sold,
+				ri.collected,
+				ri.refunded,
+				ri.detected_on,
+				ri.created_at
+			from
+				public.reconciliation_issues ri
+			where
+				ri.id in (%s)
+			order by
+				ri.id desc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var issues []ReconciliationIssue
+	for rows.Next() {
+		var issue ReconciliationIssue
+		err := rows.Scan(
+			&issue.ID,
+			&issue.ReservationID,
+			&issue.Status,
+			&issue.Issue,
+			&issue.TotalPrice,
+			&issue.Sold,
+			&issue.Collected,
+			&issue.Refunded,
+			&issue.DetectedOn,
+			&issue.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		issues = append(issues, issue)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = issues
+	return p, nil
+}
+
+func (r *ReconciliationRepository) getFilterSQL(_ context.Context, filter ReconciliationIssueFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _ri.id
+		from public.reconciliation_issues _ri
+		where
+			case
+				when array_length(@_reservation_ids::int[], 1) > 0 then
+					_ri.reservation_id = any(@_reservation_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_statuses::public.reservation_status[], 1) > 0 then
+					_ri.status = any(@_statuses::public.reservation_status[])
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_reservation_ids": filter.ReservationIDs,
+		"_statuses":        filter.Statuses,
+	}
+	return sql, args
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"github.com/jackc/pgx/v5"
@@ -19,25 +20,34 @@ type ReservationRepository struct {
 
 func (r *ReservationRepository) Create(ctx context.Context, reservation *Reservation) (int64, error) {
 	sql := `
-		insert into public.reservations (user_id, total_price)
-		values (@user_id, @total_price)
+		insert into public.reservations (user_id, cinema_id, currency, subtotal_price, total_price, fees, fee_items, tax_rate)
+		values (@user_id, nullif(@cinema_id, 0), @currency, @total_price, @total_price, @fees, @fee_items, @tax_rate)
 		returning id
 	`
 	var ID int64
 	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
 		"user_id":     reservation.UserID,
+		"cinema_id":   reservation.CinemaID,
+		"currency":    reservation.Currency,
 		"total_price": reservation.TotalPrice,
+		"fees":        reservation.Fees,
+		"fee_items":   reservation.FeeItems,
+		"tax_rate":    reservation.TaxRate,
 	}).Scan(&ID)
 	if err != nil {
 		return 0, NewSQLErr(err)
 	}
+	err = r.reprice(ctx, ID)
+	if err != nil {
+		return 0, err
+	}
 	return ID, nil
 }
 
 func (r *ReservationRepository) CreateItem(ctx context.Context, item *ReservationItem) (int64, error) {
 	sql := `
-		insert into public.reservation_items (user_id, showtime_id, seat_id, reservation_id, total_price)
-		values (@user_id, @showtime_id, @seat_id, @reservation_id, @total_price)
+		insert into public.reservation_items (user_id, showtime_id, seat_id, reservation_id, total_price, ticket_type_id, pricing)
+		values (@user_id, @showtime_id, @seat_id, @reservation_id, @total_price, nullif(@ticket_type_id, 0), @pricing)
 		returning id
 	`
 	var ID int64
@@ -47,6 +57,8 @@ func (r *ReservationRepository) CreateItem(ctx context.Context, item *Reservatio
 		"seat_id":        item.SeatID,
 		"reservation_id": item.ReservationID,
 		"total_price":    item.TotalPrice,
+		"ticket_type_id": item.TicketTypeID,
+		"pricing":        item.Pricing,
 	}).Scan(&ID)
 	if err != nil {
 		return 0, NewSQLErr(err)
@@ -72,6 +84,226 @@ func (r *ReservationRepository) UpdateByID(ctx context.Context, ID int64, input
 	return nil
 }
 
+// SetPromoCode store the promo code used and recompute the price to pay,
+// zero promo code id remove the discount.
+// Only unpaid reservation is changed, it may be paid after the caller read it
+func (r *ReservationRepository) SetPromoCode(ctx context.Context, ID, promoCodeID, discount int64) error {
+	sql := `
+		update public.reservations
+		set
+			updated_at = now(),
+			promo_code_id = nullif(@promo_code_id, 0),
+			discount = @discount
+		where id = @id and status = 'unpaid'::public.reservation_status
+		returning id
+	`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"id":            ID,
+		"promo_code_id": promoCodeID,
+		"discount":      discount,
+	}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "reservation is no longer unpaid")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return r.reprice(ctx, ID)
+}
+
+// SetPoints store redeemed points and recompute the price to pay, only unpaid reservation is changed
+func (r *ReservationRepository) SetPoints(ctx context.Context, ID, points, discount int64) error {
+	sql := `
+		update public.reservations
+		set
+			updated_at = now(),
+			points_redeemed = @points_redeemed,
+			points_discount = @points_discount
+		where id = @id and status = 'unpaid'::public.reservation_status
+		returning id
+	`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"id":              ID,
+		"points_redeemed": points,
+		"points_discount": discount,
+	}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "reservation is no longer unpaid")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return r.reprice(ctx, ID)
+}
+
+// reprice recompute tax and the price to pay after discount, redeemed points or fees changed,
+// price of paid reservation is settled and never changed
+func (r *ReservationRepository) reprice(ctx context.Context, ID int64) error {
+	sql := `
+		select subtotal_price - discount - points_discount + fees, tax_rate
+		from public.reservations
+		where id = @id and status = 'unpaid'::public.reservation_status
+	`
+	var taxable, taxRate int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"id": ID}).Scan(&taxable, &taxRate)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "reservation is no longer unpaid")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+
+	tax := Tax(taxable, taxRate)
+	totalPrice, err := AddAmount(taxable, tax)
+	if err != nil {
+		return err
+	}
+	sql = `
+		update public.reservations
+		set updated_at = now(), tax = @tax, total_price = @total_price
+		where id = @id and status = 'unpaid'::public.reservation_status
+	`
+	_, err = r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":          ID,
+		"tax":         tax,
+		"total_price": totalPrice,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// LockUnpaid lock the unpaid reservation until the transaction end,
+// concurrent payment of the same reservation wait then find it no longer unpaid
+func (r *ReservationRepository) LockUnpaid(ctx context.Context, ID int64) error {
+	sql := `
+		select id
+		from public.reservations
+		where id = @id and status = 'unpaid'::public.reservation_status
+		for update
+	`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"id": ID}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "reservation is no longer unpaid")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// IssueReceipt give the paid reservation the next receipt number of its cinema, it is kept when called again.
+// The counter row is locked until the transaction end, so the number is not skipped when the transaction fail
+func (r *ReservationRepository) IssueReceipt(ctx context.Context, ID int64) error {
+	sql := `
+		select coalesce(cinema_id, 0), receipt_number is not null
+		from public.reservations
+		where id = @id
+	`
+	var series int64
+	var issued bool
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"id": ID}).Scan(&series, &issued)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrNotFound, nil, "reservation not found")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	if issued {
+		return nil
+	}
+
+	sql = `
+		insert into public.receipt_counters (series)
+		values (@series)
+		on conflict (series) do nothing
+	`
+	_, err = r.tx.Exec(ctx, sql, pgx.NamedArgs{"series": series})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+
+	sql = `
+		update public.receipt_counters
+		set "last" = "last" + 1
+		where series = @series
+		returning "last"
+	`
+	var number int64
+	err = r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"series": series}).Scan(&number)
+	if err != nil {
+		return NewSQLErr(err)
+	}
+
+	sql = `
+		update public.reservations
+		set receipt_series = @series, receipt_number = @number, receipt_issued_at = now()
+		where id = @id
+	`
+	_, err = r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":     ID,
+		"series": series,
+		"number": number,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// SetPayment store how the reservation is paid, split between wallet and payment gateway
+func (r *ReservationRepository) SetPayment(ctx context.Context, ID, paidWallet, paidGateway int64, reference string) error {
+	sql := `
+		update public.reservations
+		set
+			updated_at = now(),
+			paid_wallet = @paid_wallet,
+			paid_gateway = @paid_gateway,
+			payment_reference = @payment_reference
+		where id = @id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":                ID,
+		"paid_wallet":       paidWallet,
+		"paid_gateway":      paidGateway,
+		"payment_reference": reference,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// CountOpenByUserID count reservation of the user still in progress,
+// unpaid or paid with showtime that is not over yet
+func (r *ReservationRepository) CountOpenByUserID(ctx context.Context, userID int64) (int64, error) {
+	sql := `
+		select count(*)
+		from public.reservations rv
+		where
+			rv.user_id = @user_id
+			and (
+				rv.status = 'unpaid'::public.reservation_status
+				or (
+					rv.status = 'paid'::public.reservation_status
+					and exists (
+						select 1
+						from public.reservation_items ri
+						join public.showtimes s on s.id = ri.showtime_id
+						where ri.reservation_id = rv.id and s.end_at > now()
+					)
+				)
+			)
+	`
+	var count int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"user_id": userID}).Scan(&count)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return count, nil
+}
+
 func (r *ReservationRepository) DeleteByID(ctx context.Context, ID int64) error {
 	sql := `delete from public.reservations where id=@id`
 	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
@@ -101,11 +333,31 @@ func (r *ReservationRepository) Find(ctx context.Context, filter ReservationFilt
 				r.id,
 				r.user_id,
 				r.status,
+				r.subtotal_price,
+				r.discount,
+				r.fees,
+				r.fee_items,
+				r.tax_rate,
+				r.currency,
+				r.tax,
 				r.total_price,
+				coalesce(r.promo_code_id, 0),
+				coalesce(pc.code, ''),
+				r.points_redeemed,
+				r.points_discount,
+				r.paid_wallet,
+				r.paid_gateway,
+				r.payment_reference,
+				coalesce(r.cinema_id, 0),
+				coalesce(r.receipt_series, 0),
+				coalesce(r.receipt_number, 0),
+				r.receipt_issued_at,
 				r.created_at,
 				r.updated_at
 			from
 				public.reservations r
+			left join public.promo_codes pc on
+				pc.id = r.promo_code_id
 			where
 				r.id in (%s)
 		`,
@@ -125,7 +377,25 @@ func (r *ReservationRepository) Find(ctx context.Context, filter ReservationFilt
 			&reservation.ID,
 			&reservation.UserID,
 			&reservation.Status,
+			&reservation.SubtotalPrice,
+			&reservation.Discount,
+			&reservation.Fees,
+			&reservation.FeeItems,
+			&reservation.TaxRate,
+			&reservation.Currency,
+			&reservation.Tax,
 			&reservation.TotalPrice,
+			&reservation.PromoCodeID,
+			&reservation.PromoCode,
+			&reservation.PointsRedeemed,
+			&reservation.PointsDiscount,
+			&reservation.PaidWallet,
+			&reservation.PaidGateway,
+			&reservation.PaymentReference,
+			&reservation.CinemaID,
+			&reservation.ReceiptSeries,
+			&reservation.ReceiptNumber,
+			&reservation.ReceiptIssuedAt,
 			&reservation.CreatedAt,
 			&reservation.UpdatedAt,
 		)
@@ -187,11 +457,31 @@ func (r *ReservationRepository) Pagination(ctx context.Context, filter Reservati
 				r.id,
 				r.user_id,
 				r.status,
+				r.subtotal_price,
+				r.discount,
+				r.fees,
+				r.fee_items,
+				r.tax_rate,
+				r.currency,
+				r.tax,
 				r.total_price,
+				coalesce(r.promo_code_id, 0),
+				coalesce(pc.code, ''),
+				r.points_redeemed,
+				r.points_discount,
+				r.paid_wallet,
+				r.paid_gateway,
+				r.payment_reference,
+				coalesce(r.cinema_id, 0),
+				coalesce(r.receipt_series, 0),
+				coalesce(r.receipt_number, 0),
+				r.receipt_issued_at,
 				r.created_at,
 				r.updated_at
 			from
 				public.reservations r
+			left join public.promo_codes pc on
+				pc.id = r.promo_code_id
 			where
 				r.id in (%s)
 			limit @page_size offset (@page - 1) * @page_size
@@ -217,7 +507,25 @@ func (r *ReservationRepository) Pagination(ctx context.Context, filter Reservati
 			&reservation.ID,
 			&reservation.UserID,
 			&reservation.Status,
+			&reservation.SubtotalPrice,
+			&reservation.Discount,
+			&reservation.Fees,
+			&reservation.FeeItems,
+			&reservation.TaxRate,
+			&reservation.Currency,
+			&reservation.Tax,
 			&reservation.TotalPrice,
+			&reservation.PromoCodeID,
+			&reservation.PromoCode,
+			&reservation.PointsRedeemed,
+			&reservation.PointsDiscount,
+			&reservation.PaidWallet,
+			&reservation.PaidGateway,
+			&reservation.PaymentReference,
+			&reservation.CinemaID,
+			&reservation.ReceiptSeries,
+			&reservation.ReceiptNumber,
+			&reservation.ReceiptIssuedAt,
 			&reservation.CreatedAt,
 			&reservation.UpdatedAt,
 		)
@@ -280,6 +588,8 @@ func (r *ReservationRepository) FindItem(ctx context.Context, filter Reservation
 				rvi.showtime_id,
 				rvi.seat_id,
 				rvi.total_price,
+				coalesce(rvi.ticket_type_id, 0),
+				rvi.pricing,
 				rvi.created_at,
 				rvi.updated_at,
 				m.title as movie,
@@ -314,6 +624,8 @@ func (r *ReservationRepository) FindItem(ctx context.Context, filter Reservation
 			&item.ShowtimeID,
 			&item.SeatID,
 			&item.TotalPrice,
+			&item.TicketTypeID,
+			&item.Pricing,
 			&item.CreatedAt,
 			&item.UpdatedAt,
 			&item.Movie,
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewRoleRepository(tx pgx.Tx) *RoleRepository {
+	return &RoleRepository{
+		tx: tx,
+	}
+}
+
+type RoleRepository struct {
+	tx pgx.Tx
+}
+
+func (r *RoleRepository) Create(ctx context.Context, name string) (int64, error) {
+	sql := `insert into public.roles (name) values (@name) returning id`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"name": name}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *RoleRepository) DeleteByID(ctx context.Context, ID int64) error {
+	sql := `delete from public.roles where id=@id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// SetPermissions replace every permission of the role
+func (r *RoleRepository) SetPermissions(ctx context.Context, roleID int64, permissions []string) error {
+	sql := `delete from public.role_permissions where role_id=@role_id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"role_id": roleID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	sql = `
+		insert into public.role_permissions (role_id, permission)
+		select @role_id, unnest(@permissions::text[])
+	`
+	_, err = r.tx.Exec(ctx, sql, pgx.NamedArgs{"role_id": roleID, "permissions": permissions})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *RoleRepository) FindPermissions(ctx context.Context) ([]PermissionInfo, error) {
+	sql := `select name, description from public.permissions order by name`
+	rows, err := r.tx.Query(ctx, sql)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	permissions := []PermissionInfo{}
+	for rows.Next() {
+		var permission PermissionInfo
+		err := rows.Scan(&permission.Name, &permission.Description)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		permissions = append(permissions, permission)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return permissions, nil
+}
+
+func (r *RoleRepository) FindPermissionsByRoleName(ctx context.Context, name string) ([]string, error) {
+	sql := `
+		select rp.permission
+		from public.role_permissions rp
+		join public.roles r on r.id = rp.role_id
+		where r.name = @name
+		order by rp.permission
+	`
+	rows, err := r.tx.Query(ctx, sql, pgx.NamedArgs{"name": name})
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		err := rows.Scan(&permission)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		permissions = append(permissions, permission)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return permissions, nil
+}
+
+func (r *RoleRepository) CountUsers(ctx context.Context, roleID int64) (int64, error) {
+	sql := `select count(*) from public.users where role_id=@role_id`
+	var count int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"role_id": roleID}).Scan(&count)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return count, nil
+}
@@ -18,9 +18,9 @@ type RoomRepository struct {
 }
 
 func (r *RoomRepository) Create(ctx context.Context, room *Room) (int64, error) {
-	sql := `insert into public.rooms (name) values (@name) returning id`
+	sql := `insert into public.rooms (name, cinema_id) values (@name, nullif(@cinema_id, 0)) returning id`
 	var ID int64
-	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"name": room.Name}).Scan(&ID)
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"name": room.Name, "cinema_id": room.CinemaID}).Scan(&ID)
 	if err != nil {
 		return 0, NewSQLErr(err)
 	}
@@ -28,10 +28,11 @@ func (r *RoomRepository) Create(ctx context.Context, room *Room) (int64, error)
 }
 
 func (r *RoomRepository) UpdateByID(ctx context.Context, ID int64, input RoomInput) error {
-	sql := `update public.rooms SET updated_at=now(), name=@name where id=@id`
+	sql := `update public.rooms SET updated_at=now(), name=@name, cinema_id=nullif(@cinema_id, 0) where id=@id`
 	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
-		"id":   ID,
-		"name": input.Name,
+		"id":        ID,
+		"name":      input.Name,
+		"cinema_id": input.CinemaID,
 	})
 	if err != nil {
 		return NewSQLErr(err)
@@ -64,7 +65,7 @@ func (r *RoomRepository) Find(ctx context.Context, filter RoomFilter) ([]Room, e
 	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
 	sql := fmt.Sprintf(
 		`
-			select r.id, r.name, r.created_at, r.updated_at, count(s.id) as capacity
+			select r.id, r.name, coalesce(r.cinema_id, 0), r.created_at, r.updated_at, count(s.id) as capacity
 			from public.rooms r
 			left join public.seats s on r.id = s.room_id
 			where r.id in (%s)
@@ -83,7 +84,7 @@ func (r *RoomRepository) Find(ctx context.Context, filter RoomFilter) ([]Room, e
 	var rooms []Room
 	for rows.Next() {
 		var room Room
-		err := rows.Scan(&room.ID, &room.Name, &room.CreatedAt, &room.UpdatedAt, &room.Capacity)
+		err := rows.Scan(&room.ID, &room.Name, &room.CinemaID, &room.CreatedAt, &room.UpdatedAt, &room.Capacity)
 		if err != nil {
 			return nil, NewSQLErr(err)
 		}
@@ -119,7 +120,7 @@ func (r *RoomRepository) Pagination(ctx context.Context, filter RoomFilter, page
 
 	sql = fmt.Sprintf(
 		`
-			select r.id, r.name, r.created_at, r.updated_at, count(s.*) as capacity
+			select r.id, r.name, coalesce(r.cinema_id, 0), r.created_at, r.updated_at, count(s.*) as capacity
 			from public.rooms r
 			left join public.seats s on r.id = s.room_id
 			where r.id in (%s)
@@ -144,7 +145,7 @@ func (r *RoomRepository) Pagination(ctx context.Context, filter RoomFilter, page
 	var rooms []Room
 	for rows.Next() {
 		var room Room
-		err := rows.Scan(&room.ID, &room.Name, &room.CreatedAt, &room.UpdatedAt, &room.Capacity)
+		err := rows.Scan(&room.ID, &room.Name, &room.CinemaID, &room.CreatedAt, &room.UpdatedAt, &room.Capacity)
 		if err != nil {
 			return nil, NewSQLErr(err)
 		}
@@ -177,6 +178,13 @@ func (r *RoomRepository) getFilterSQL(_ context.Context, filter RoomFilter) (sql
 				else
 					true
 			end
+			and
+			case
+				when array_length(@_cinema_ids::int[], 1) > 0 then
+					_r.cinema_id = any(@_cinema_ids)
+				else
+					true
+			end
 		group by _r.id
 		having
 			case
@@ -187,9 +195,10 @@ func (r *RoomRepository) getFilterSQL(_ context.Context, filter RoomFilter) (sql
 			end
 	`
 	args = pgx.NamedArgs{
-		"_ids":       filter.IDs,
-		"_names":     filter.Names,
-		"_is_usable": filter.IsUsable,
+		"_ids":        filter.IDs,
+		"_names":      filter.Names,
+		"_cinema_ids": filter.CinemaIDs,
+		"_is_usable":  filter.IsUsable,
 	}
 	return sql, args
 }
@@ -223,10 +232,11 @@ func (r *RoomRepository) SetSeats(ctx context.Context, roomID int64, inputs []Se
 	for _, item := range inputs {
 		oldSeat, ok := oldSeatMap[item.Name]
 		if ok {
-			if oldSeat.AdditionalPrice == item.AdditionalPrice {
+			if oldSeat.AdditionalPrice == item.AdditionalPrice && oldSeat.Category == item.Category {
 				continue
 			}
 			oldSeat.AdditionalPrice = item.AdditionalPrice
+			oldSeat.Category = item.Category
 			updateRows = append(updateRows, oldSeat)
 			continue
 		}
@@ -241,7 +251,7 @@ func (r *RoomRepository) SetSeats(ctx context.Context, roomID int64, inputs []Se
 	}
 
 	if len(updateRows) > 0 {
-		err = r.BulkUpdateSeat(ctx, newRows)
+		err = r.BulkUpdateSeat(ctx, updateRows)
 		if err != nil {
 			return err
 		}
@@ -262,7 +272,7 @@ func (r *RoomRepository) FilterSeats(ctx context.Context, filter SeatFilter) ([]
 
 	sql := fmt.Sprintf(
 		`
-			select s.id, s.room_id, s.name, s.additional_price, s.created_at, s.updated_at
+			select s.id, s.room_id, s.name, s.additional_price, s.category, s.created_at, s.updated_at
 			from public.seats s
 			where s.id in (%s)
 			order by s.name asc, s.room_id asc, s.id asc
@@ -283,6 +293,7 @@ func (r *RoomRepository) FilterSeats(ctx context.Context, filter SeatFilter) ([]
 			&seat.RoomID,
 			&seat.Name,
 			&seat.AdditionalPrice,
+			&seat.Category,
 			&seat.CreatedAt,
 			&seat.UpdatedAt,
 		)
@@ -306,13 +317,13 @@ func (r *RoomRepository) BulkCreateSeat(ctx context.Context, newSeats []Seat) er
 
 	newRows := make([][]any, 0, len(newSeats))
 	for _, item := range newSeats {
-		newRows = append(newRows, []any{item.RoomID, item.Name, item.AdditionalPrice})
+		newRows = append(newRows, []any{item.RoomID, item.Name, item.AdditionalPrice, item.Category})
 	}
 
 	_, err := r.tx.CopyFrom(
 		ctx,
 		pgx.Identifier{"seats"},
-		[]string{"room_id", "name", "additional_price"},
+		[]string{"room_id", "name", "additional_price", "category"},
 		pgx.CopyFromRows(newRows),
 	)
 	if err != nil {
@@ -329,7 +340,7 @@ func (r *RoomRepository) BulkUpdateSeat(ctx context.Context, updateSeats []Seat)
 
 	sql := `
 		update public.seats
-		set room_id=@room_id, "name"=@name, additional_price=@additional_price, updated_at=now()
+		set room_id=@room_id, "name"=@name, additional_price=@additional_price, category=@category, updated_at=now()
 		where id=@id
 	`
 
@@ -340,6 +351,7 @@ func (r *RoomRepository) BulkUpdateSeat(ctx context.Context, updateSeats []Seat)
 			"name":             seat.Name,
 			"room_id":          seat.RoomID,
 			"additional_price": seat.AdditionalPrice,
+			"category":         seat.Category,
 		})
 	}
 	br := r.tx.SendBatch(ctx, &batch)
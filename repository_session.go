@@ -0,0 +1,236 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewSessionRepository(tx pgx.Tx) *SessionRepository {
+	return &SessionRepository{
+		tx: tx,
+	}
+}
+
+type SessionRepository struct {
+	tx pgx.Tx
+}
+
+func (r *SessionRepository) Create(ctx context.Context, session *Session) (int64, error) {
+	sql := `
+		insert into public.sessions (user_id, refresh_token_hash, user_agent, mfa, expires_at)
+		values (@user_id, @refresh_token_hash, @user_agent, @mfa, @expires_at)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"user_id":            session.UserID,
+		"refresh_token_hash": session.RefreshTokenHash,
+		"user_agent":         session.UserAgent,
+		"mfa":                session.MFA,
+		"expires_at":         session.ExpiresAt,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// Rotate replace the refresh token only when the old one is still the current token of an active session,
+// so the same refresh token cannot be exchanged twice by concurrent requests
+func (r *SessionRepository) Rotate(ctx context.Context, ID int64, oldHash string, session *Session) error {
+	sql := `
+		update public.sessions
+		set
+			previous_token_hash = refresh_token_hash,
+			refresh_token_hash = @refresh_token_hash,
+			user_agent = @user_agent,
+			expires_at = @expires_at,
+			updated_at = now()
+		where
+			id = @id
+			and refresh_token_hash = @old_hash
+			and revoked_at is null
+			and expires_at > now()
+		returning id
+	`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"id":                 ID,
+		"old_hash":           oldHash,
+		"refresh_token_hash": session.RefreshTokenHash,
+		"user_agent":         session.UserAgent,
+		"expires_at":         session.ExpiresAt,
+	}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "refresh token invalid or expired")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *SessionRepository) RevokeByID(ctx context.Context, ID int64) error {
+	sql := `update public.sessions set revoked_at = now(), updated_at = now() where id = @id and revoked_at is null`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// RevokeByUserID revoke every session of the user, except the session with exceptID when it is not zero
+func (r *SessionRepository) RevokeByUserID(ctx context.Context, userID, exceptID int64) error {
+	sql := `update public.sessions set revoked_at = now(), updated_at = now() where user_id = @user_id and id <> @except_id and revoked_at is null`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"user_id": userID, "except_id": exceptID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// IsValid check the access token claims against the session, the current token version of the user and that the user is not disabled
+func (r *SessionRepository) IsValid(ctx context.Context, ID, userID, tokenVersion int64) (bool, error) {
+	sql := `
+		select exists(
+			select 1
+			from public.sessions s
+			join public.users u on u.id = s.user_id
+			where
+				s.id = @id
+				and s.user_id = @user_id
+				and s.revoked_at is null
+				and u.token_version = @token_version
+				and u.disabled_at is null
+		)
+	`
+	var valid bool
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"id":            ID,
+		"user_id":       userID,
+		"token_version": tokenVersion,
+	}).Scan(&valid)
+	if err != nil {
+		return false, NewSQLErr(err)
+	}
+	return valid, nil
+}
+
+func (r *SessionRepository) FindOne(ctx context.Context, filter SessionFilter) (*Session, error) {
+	sessions, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(sessions) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "session not found")
+	}
+	return &sessions[0], nil
+}
+
+func (r *SessionRepository) Find(ctx context.Context, filter SessionFilter) ([]Session, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				s.id,
+				s.user_id,
+				s.refresh_token_hash,
+				s.user_agent,
+				s.mfa,
+				s.expires_at,
+				s.revoked_at,
+				s.created_at,
+				s.updated_at
+			from
+				public.sessions s
+			where
+				s.id in (%s)
+			order by
+				s.id desc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var sessions []Session
+	for rows.Next() {
+		var session Session
+		err := rows.Scan(
+			&session.ID,
+			&session.UserID,
+			&session.RefreshTokenHash,
+			&session.UserAgent,
+			&session.MFA,
+			&session.ExpiresAt,
+			&session.RevokedAt,
+			&session.CreatedAt,
+			&session.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		sessions = append(sessions, session)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return sessions, nil
+}
+
+func (r *SessionRepository) getFilterSQL(_ context.Context, filter SessionFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _s.id
+		from public.sessions _s
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_s.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_user_ids::int[], 1) > 0 then
+					_s.user_id = any(@_user_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_refresh_token_hashes::text[], 1) > 0 then
+					_s.refresh_token_hash = any(@_refresh_token_hashes)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_previous_token_hashes::text[], 1) > 0 then
+					_s.previous_token_hash = any(@_previous_token_hashes)
+				else
+					true
+			end
+			and
+			case
+				when @_is_active::bool is not null then
+					(_s.revoked_at is null and _s.expires_at > now()) = @_is_active
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":                   filter.IDs,
+		"_user_ids":              filter.UserIDs,
+		"_refresh_token_hashes":  filter.RefreshTokenHashes,
+		"_previous_token_hashes": filter.PreviousTokenHashes,
+		"_is_active":             filter.IsActive,
+	}
+	return sql, args
+}
@@ -19,8 +19,30 @@ type ShowtimeRepository struct {
 
 func (r *ShowtimeRepository) Create(ctx context.Context, showtime *Showtime) (int64, error) {
 	sql := `
-		insert into public.showtimes (movie_id, room_id, start_at, end_at, price)
-		values (@movie_id, @room_id, @start_at, @end_at, @price)
+		insert into public.showtimes (
+			movie_id,
+			room_id,
+			start_at,
+			end_at,
+			price,
+			format,
+			audio_language,
+			subtitle_language,
+			open_captions,
+			audio_description
+		)
+		values (
+			@movie_id,
+			@room_id,
+			@start_at,
+			@end_at,
+			@price,
+			@format::public.showtime_format,
+			@audio_language,
+			@subtitle_language,
+			@open_captions,
+			@audio_description
+		)
 		returning id
 	`
 	var ID int64
@@ -33,6 +55,12 @@ func (r *ShowtimeRepository) Create(ctx context.Context, showtime *Showtime) (in
 			"start_at": showtime.StartAt,
 			"end_at":   showtime.EndAt,
 			"price":    showtime.Price,
+
+			"format":            showtime.Format,
+			"audio_language":    showtime.AudioLanguage,
+			"subtitle_language": showtime.SubtitleLanguage,
+			"open_captions":     showtime.OpenCaptions,
+			"audio_description": showtime.AudioDescription,
 		},
 	).Scan(&ID)
 	if err != nil {
@@ -51,7 +79,12 @@ func (r *ShowtimeRepository) UpdateByID(ctx context.Context, ID int64, input Sho
 			room_id = @room_id,
 			start_at = @start_at,
 			end_at = @end_at,
-			price = @price
+			price = @price,
+			format = @format::public.showtime_format,
+			audio_language = @audio_language,
+			subtitle_language = @subtitle_language,
+			open_captions = @open_captions,
+			audio_description = @audio_description
 		where
 			id = @id
 	`
@@ -62,6 +95,12 @@ func (r *ShowtimeRepository) UpdateByID(ctx context.Context, ID int64, input Sho
 		"start_at": input.StartAt,
 		"end_at":   input.EndAt,
 		"price":    input.Price,
+
+		"format":            input.Format,
+		"audio_language":    input.AudioLanguage,
+		"subtitle_language": input.SubtitleLanguage,
+		"open_captions":     input.OpenCaptions,
+		"audio_description": input.AudioDescription,
 	})
 	if err != nil {
 		return NewSQLErr(err)
@@ -127,10 +166,17 @@ func (r *ShowtimeRepository) Find(ctx context.Context, filter ShowtimeFilter) ([
 				s.price,
 				s.created_at,
 				s.updated_at,
+				s.format,
+				s.audio_language,
+				s.subtitle_language,
+				s.open_captions,
+				s.audio_description,
 				m.title as movie_title,
 				r.name as room_name,
 				coalesce(sc.total, 0) as total_seat,
-				coalesce(sc.total, 0) - coalesce(rc.total, 0) as available_seat
+				coalesce(sc.total, 0) - coalesce(rc.total, 0) as available_seat,
+				coalesce(fs.amount, 0) as surcharge,
+				coalesce(cn.currency, @default_currency) as currency
 			from
 				showtimes s
 			left join reserved_count rc on
@@ -141,6 +187,11 @@ func (r *ShowtimeRepository) Find(ctx context.Context, filter ShowtimeFilter) ([
 				m.id = s.movie_id
 			join rooms r on
 				r.id = s.room_id
+			left join cinemas cn on
+				cn.id = r.cinema_id
+			left join format_surcharges fs on
+				fs.format = s.format
+				and fs.currency = coalesce(cn.currency, @default_currency)
 			where s.id in (%s)
 			order by
 				s.start_at asc,
@@ -148,7 +199,10 @@ func (r *ShowtimeRepository) Find(ctx context.Context, filter ShowtimeFilter) ([
 		`,
 		filterSQL,
 	)
-	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{"default_currency": DefaultCurrency}),
+	)
 	if err != nil {
 		return nil, NewSQLErr(err)
 	}
@@ -166,10 +220,17 @@ func (r *ShowtimeRepository) Find(ctx context.Context, filter ShowtimeFilter) ([
 			&showtime.Price,
 			&showtime.CreatedAt,
 			&showtime.UpdatedAt,
+			&showtime.Format,
+			&showtime.AudioLanguage,
+			&showtime.SubtitleLanguage,
+			&showtime.OpenCaptions,
+			&showtime.AudioDescription,
 			&showtime.MovieTitle,
 			&showtime.RoomName,
 			&showtime.TotalSeat,
 			&showtime.AvailableSeat,
+			&showtime.Surcharge,
+			&showtime.Currency,
 		)
 		if err != nil {
 			return nil, NewSQLErr(err)
@@ -238,10 +299,17 @@ func (r *ShowtimeRepository) Pagination(ctx context.Context, filter ShowtimeFilt
 				s.price,
 				s.created_at,
 				s.updated_at,
+				s.format,
+				s.audio_language,
+				s.subtitle_language,
+				s.open_captions,
+				s.audio_description,
 				m.title as movie_title,
 				r.name as room_name,
 				coalesce(sc.total, 0) as total_seat,
-				coalesce(sc.total, 0) - coalesce(rc.total, 0) as available_seat
+				coalesce(sc.total, 0) - coalesce(rc.total, 0) as available_seat,
+				coalesce(fs.amount, 0) as surcharge,
+				coalesce(cn.currency, @default_currency) as currency
 			from
 				showtimes s
 			left join reserved_count rc on
@@ -252,6 +320,11 @@ func (r *ShowtimeRepository) Pagination(ctx context.Context, filter ShowtimeFilt
 				m.id = s.movie_id
 			join rooms r on
 				r.id = s.room_id
+			left join cinemas cn on
+				cn.id = r.cinema_id
+			left join format_surcharges fs on
+				fs.format = s.format
+				and fs.currency = coalesce(cn.currency, @default_currency)
 			where s.id in (%s)
 			order by
 				s.start_at asc,
@@ -263,8 +336,9 @@ func (r *ShowtimeRepository) Pagination(ctx context.Context, filter ShowtimeFilt
 	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
 		filterArgs,
 		pgx.NamedArgs{
-			"page":      page.Page,
-			"page_size": page.Size,
+			"page":             page.Page,
+			"page_size":        page.Size,
+			"default_currency": DefaultCurrency,
 		}),
 	)
 	if err != nil {
@@ -284,10 +358,17 @@ func (r *ShowtimeRepository) Pagination(ctx context.Context, filter ShowtimeFilt
 			&showtime.Price,
 			&showtime.CreatedAt,
 			&showtime.UpdatedAt,
+			&showtime.Format,
+			&showtime.AudioLanguage,
+			&showtime.SubtitleLanguage,
+			&showtime.OpenCaptions,
+			&showtime.AudioDescription,
 			&showtime.MovieTitle,
 			&showtime.RoomName,
 			&showtime.TotalSeat,
 			&showtime.AvailableSeat,
+			&showtime.Surcharge,
+			&showtime.Currency,
 		)
 		if err != nil {
 			return nil, NewSQLErr(err)
@@ -328,17 +409,90 @@ func (r *ShowtimeRepository) getFilterSQL(_ context.Context, filter ShowtimeFilt
 					true
 			end
 			and
+			case
+				when array_length(@_cinema_ids::int[], 1) > 0 then
+					_s.room_id in (select _r.id from public.rooms _r where _r.cinema_id = any(@_cinema_ids))
+				else
+					true
+			end
+			and
 			case
 				when @_after::timestamptz is not null then
 					@_after <= _s.start_at
 				else
 					true
 			end
+			and
+			case
+				when array_length(@_formats::text[], 1) > 0 then
+					_s.format::text = any(@_formats)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_audio_languages::text[], 1) > 0 then
+					_s.audio_language = any(@_audio_languages)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_subtitle_languages::text[], 1) > 0 then
+					_s.subtitle_language = any(@_subtitle_languages)
+				else
+					true
+			end
+			and
+			case
+				when @_has_subtitle::bool is not null then
+					(_s.subtitle_language <> '') = @_has_subtitle
+				else
+					true
+			end
+			and
+			case
+				when @_is_original_language::bool is not null then
+					(
+						_s.audio_language <> ''
+						and _s.audio_language = (
+							select _m.original_language from public.movies _m where _m.id = _s.movie_id
+						)
+					) = @_is_original_language
+				else
+					true
+			end
+			and
+			case
+				when @_open_captions::bool is not null then
+					_s.open_captions = @_open_captions
+				else
+					true
+			end
+			and
+			case
+				when @_audio_description::bool is not null then
+					_s.audio_description = @_audio_description
+				else
+					true
+			end
 	`
+	formats := make([]string, 0, len(filter.Formats))
+	for _, format := range filter.Formats {
+		formats = append(formats, string(format))
+	}
 	args = pgx.NamedArgs{
-		"_ids":       filter.IDs,
-		"_movie_ids": filter.MovieIDs,
-		"_room_ids":  filter.RoomIDs,
+		"_ids":                  filter.IDs,
+		"_movie_ids":            filter.MovieIDs,
+		"_room_ids":             filter.RoomIDs,
+		"_cinema_ids":           filter.CinemaIDs,
+		"_formats":              formats,
+		"_audio_languages":      filter.AudioLanguages,
+		"_subtitle_languages":   filter.SubtitleLanguages,
+		"_has_subtitle":         filter.HasSubtitle,
+		"_is_original_language": filter.IsOriginalLanguage,
+		"_open_captions":        filter.OpenCaptions,
+		"_audio_description":    filter.AudioDescription,
 	}
 	if !filter.After.IsZero() {
 		args["_after"] = filter.After
@@ -346,6 +500,54 @@ func (r *ShowtimeRepository) getFilterSQL(_ context.Context, filter ShowtimeFilt
 	return sql, args
 }
 
+// format surcharges
+
+func (r *ShowtimeRepository) FindFormatSurcharges(ctx context.Context) ([]FormatSurcharge, error) {
+	sql := `
+		select format, currency, amount, updated_at
+		from public.format_surcharges
+		order by format, currency
+	`
+	rows, err := r.tx.Query(ctx, sql)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	surcharges := []FormatSurcharge{}
+	for rows.Next() {
+		var surcharge FormatSurcharge
+		err := rows.Scan(&surcharge.Format, &surcharge.Currency, &surcharge.Amount, &surcharge.UpdatedAt)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		surcharges = append(surcharges, surcharge)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return surcharges, nil
+}
+
+func (r *ShowtimeRepository) SetFormatSurcharge(ctx context.Context, format ShowtimeFormat, input FormatSurchargeInput) error {
+	sql := `
+		insert into public.format_surcharges (format, currency, amount)
+		values (@format::public.showtime_format, @currency, @amount)
+		on conflict (format, currency) do update
+		set amount = excluded.amount, updated_at = now()
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"format":   format,
+		"currency": input.Currency,
+		"amount":   input.Amount,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
 // showtime seats
 
 func (r *ShowtimeRepository) GetShowtimeSeats(ctx context.Context, showtimeID int64) ([]Seat, error) {
@@ -367,6 +569,7 @@ func (r *ShowtimeRepository) GetShowtimeSeats(ctx context.Context, showtimeID in
 			s.room_id,
 			st.additional_price,
 			st."name",
+			st.category,
 			st.created_at,
 			st.updated_at,
 			rt.id is null as is_available
@@ -397,6 +600,7 @@ func (r *ShowtimeRepository) GetShowtimeSeats(ctx context.Context, showtimeID in
 			&seat.RoomID,
 			&seat.AdditionalPrice,
 			&seat.Name,
+			&seat.Category,
 			&seat.CreatedAt,
 			&seat.UpdatedAt,
 			&seat.IsAvailable,
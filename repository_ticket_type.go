@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewTicketTypeRepository(tx pgx.Tx) *TicketTypeRepository {
+	return &TicketTypeRepository{
+		tx: tx,
+	}
+}
+
+type TicketTypeRepository struct {
+	tx pgx.Tx
+}
+
+func (r *TicketTypeRepository) Create(ctx context.Context, ticketType *TicketType) (int64, error) {
+	sql := `
+		insert into public.ticket_types (code, "name", "percent", amount, currency, require_ticket_type_id, is_default, is_active)
+		values (@code, @name, @percent, @amount, @currency, nullif(@require_ticket_type_id, 0), @is_default, @is_active)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"code":                   ticketType.Code,
+		"name":                   ticketType.Name,
+		"percent":                ticketType.Percent,
+		"amount":                 ticketType.Amount,
+		"currency":               ticketType.Currency,
+		"require_ticket_type_id": ticketType.RequireTicketTypeID,
+		"is_default":             ticketType.IsDefault,
+		"is_active":              ticketType.IsActive,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+func (r *TicketTypeRepository) UpdateByID(ctx context.Context, ID int64, input TicketTypeInput) error {
+	sql := `
+		update public.ticket_types
+		set
+			updated_at = now(),
+			code = @code,
+			"name" = @name,
+			"percent" = @percent,
+			amount = @amount,
+			currency = @currency,
+			require_ticket_type_id = nullif(@require_ticket_type_id, 0),
+			is_default = @is_default,
+			is_active = @is_active
+		where id = @id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"id":                     ID,
+		"code":                   input.Code,
+		"name":                   input.Name,
+		"percent":                input.Percent,
+		"amount":                 input.Amount,
+		"currency":               input.Currency,
+		"require_ticket_type_id": input.RequireTicketTypeID,
+		"is_default":             input.IsDefault,
+		"is_active":              input.IsActive,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// ClearDefault unset current default ticket type so another can be the default
+func (r *TicketTypeRepository) ClearDefault(ctx context.Context) error {
+	sql := `update public.ticket_types set is_default = false, updated_at = now() where is_default`
+	_, err := r.tx.Exec(ctx, sql)
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *TicketTypeRepository) DeleteByID(ctx context.Context, ID int64) error {
+	sql := `delete from public.ticket_types where id = @id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *TicketTypeRepository) FindOne(ctx context.Context, filter TicketTypeFilter) (*TicketType, error) {
+	ticketTypes, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(ticketTypes) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "ticket type not found")
+	}
+	return &ticketTypes[0], nil
+}
+
+func (r *TicketTypeRepository) Find(ctx context.Context, filter TicketTypeFilter) ([]TicketType, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				tt.id,
+				tt.code,
+				tt."name",
+				tt."percent",
+				tt.amount,
+				tt.currency,
+				coalesce(tt.require_ticket_type_id, 0),
+				tt.is_default,
+				tt.is_active,
+				tt.created_at,
+				tt.updated_at
+			from
+				public.ticket_types tt
+			where
+				tt.id in (%s)
+			order by
+				tt.is_default desc,
+				tt.id asc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var ticketTypes []TicketType
+	for rows.Next() {
+		var ticketType TicketType
+		err := rows.Scan(
+			&ticketType.ID,
+			&ticketType.Code,
+			&ticketType.Name,
+			&ticketType.Percent,
+			&ticketType.Amount,
+			&ticketType.Currency,
+			&ticketType.RequireTicketTypeID,
+			&ticketType.IsDefault,
+			&ticketType.IsActive,
+			&ticketType.CreatedAt,
+			&ticketType.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		ticketTypes = append(ticketTypes, ticketType)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	return ticketTypes, nil
+}
+
+func (r *TicketTypeRepository) getFilterSQL(_ context.Context, filter TicketTypeFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _tt.id
+		from public.ticket_types _tt
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_tt.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_codes::text[], 1) > 0 then
+					_tt.code = any(@_codes)
+				else
+					true
+			end
+			and
+			case
+				when @_is_active::bool is not null then
+					_tt.is_active = @_is_active
+				else
+					true
+			end
+			and
+			case
+				when @_is_default::bool is not null then
+					_tt.is_default = @_is_default
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":        filter.IDs,
+		"_codes":      filter.Codes,
+		"_is_active":  filter.IsActive,
+		"_is_default": filter.IsDefault,
+	}
+	return sql, args
+}
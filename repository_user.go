@@ -36,7 +36,7 @@ func (r *UserRepository) Create(ctx context.Context, user *User) (int64, error)
 }
 
 func (r *UserRepository) UpdatePasswordByID(ctx context.Context, ID int64, user *User) error {
-	sql := `update public.users set updated_at=now(), password=@password where id=@id`
+	sql := `update public.users set updated_at=now(), password=@password, token_version=token_version+1 where id=@id`
 	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID, "password": user.PasswordHash})
 	if err != nil {
 		return NewSQLErr(err)
@@ -45,7 +45,7 @@ func (r *UserRepository) UpdatePasswordByID(ctx context.Context, ID int64, user
 }
 
 func (r *UserRepository) UpdateRoleByID(ctx context.Context, ID int64, input UserInput) error {
-	sql := `update public.users set updated_at=now(), role_id=@role_id where id=@id`
+	sql := `update public.users set updated_at=now(), role_id=@role_id, token_version=token_version+1 where id=@id`
 	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID, "role_id": input.RoleID})
 	if err != nil {
 		return NewSQLErr(err)
@@ -53,6 +53,25 @@ func (r *UserRepository) UpdateRoleByID(ctx context.Context, ID int64, input Use
 	return nil
 }
 
+// IncrementTokenVersionByID invalidate every access token issued to the user
+func (r *UserRepository) IncrementTokenVersionByID(ctx context.Context, ID int64) error {
+	sql := `update public.users set updated_at=now(), token_version=token_version+1 where id=@id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *UserRepository) VerifyEmailByID(ctx context.Context, ID int64) error {
+	sql := `update public.users set updated_at=now(), email_verified_at=now() where id=@id and email_verified_at is null`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
 func (r *UserRepository) FindOne(ctx context.Context, filter UserFilter) (*User, error) {
 	users, err := r.Find(ctx, filter)
 	if err != nil {
@@ -75,6 +94,13 @@ func (r *UserRepository) Find(ctx context.Context, filter UserFilter) ([]User, e
 				u.password,
 				u.created_at,
 				u.updated_at,
+				u.token_version,
+				u.email_verified_at,
+				coalesce(u.mfa_secret, ''),
+				u.mfa_enabled_at,
+				u.mfa_last_step,
+				u.disabled_at,
+				coalesce(u.disabled_reason, ''),
 				r.name
 			from
 				public.users u
@@ -94,7 +120,7 @@ func (r *UserRepository) Find(ctx context.Context, filter UserFilter) ([]User, e
 	var users []User
 	for rows.Next() {
 		var user User
-		err := rows.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &user.Role)
+		err := rows.Scan(&user.ID, &user.Email, &user.PasswordHash, &user.CreatedAt, &user.UpdatedAt, &user.TokenVersion, &user.EmailVerifiedAt, &user.MFASecret, &user.MFAEnabledAt, &user.MFALastStep, &user.DisabledAt, &user.DisabledReason, &user.Role)
 		if err != nil {
 			return nil, NewSQLErr(err)
 		}
@@ -108,12 +134,162 @@ func (r *UserRepository) Find(ctx context.Context, filter UserFilter) ([]User, e
 	return users, nil
 }
 
+func (r *UserRepository) Paginate(ctx context.Context, filter UserFilter, page PaginateInput) (*Paginate[User], error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf("select count(*) from (%s)", filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]User{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				u.id,
+				u.email,
+				u.created_at,
+				u.updated_at,
+				u.email_verified_at,
+				u.mfa_enabled_at,
+				u.disabled_at,
+				coalesce(u.disabled_reason, ''),
+				r.name
+			from
+				public.users u
+			join public.roles r on
+				u.role_id = r.id
+			where
+				u.id in (%s)
+			order by
+				u.id desc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var users []User
+	for rows.Next() {
+		var user User
+		err := rows.Scan(
+			&user.ID,
+			&user.Email,
+			&user.CreatedAt,
+			&user.UpdatedAt,
+			&user.EmailVerifiedAt,
+			&user.MFAEnabledAt,
+			&user.DisabledAt,
+			&user.DisabledReason,
+			&user.Role,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		users = append(users, user)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+
+	p.Items = users
+	return p, nil
+}
+
+// DisableByID block login of the user, every access token issued is invalidated
+func (r *UserRepository) DisableByID(ctx context.Context, ID int64, reason string) error {
+	sql := `
+		update public.users
+		set updated_at=now(), disabled_at=now(), disabled_reason=nullif(@reason, ''), token_version=token_version+1
+		where id=@id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID, "reason": reason})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *UserRepository) EnableByID(ctx context.Context, ID int64) error {
+	sql := `update public.users set updated_at=now(), disabled_at=null, disabled_reason=null where id=@id`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// AnonymizeByID erase personal data of the user and block login,
+// the row is kept so reservations, receipts and ledger of the user stay intact
+func (r *UserRepository) AnonymizeByID(ctx context.Context, ID int64) error {
+	sql := `
+		update public.users
+		set
+			updated_at = now(),
+			deleted_at = now(),
+			email = concat('deleted-', id, '@deleted.invalid'),
+			password = '',
+			token_version = token_version + 1,
+			email_verified_at = null,
+			mfa_secret = null,
+			mfa_enabled_at = null,
+			disabled_at = coalesce(disabled_at, now()),
+			disabled_reason = 'deleted'
+		where id = @id
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+
+	tables := []string{
+		"public.sessions",
+		"public.user_tokens",
+		"public.mfa_backup_codes",
+		"public.user_identities",
+		"public.user_profile_changes",
+		"public.user_profiles",
+		"public.carts",
+	}
+	for _, table := range tables {
+		sql = fmt.Sprintf(`delete from %s where user_id = @id`, table)
+		_, err = r.tx.Exec(ctx, sql, pgx.NamedArgs{"id": ID})
+		if err != nil {
+			return NewSQLErr(err)
+		}
+	}
+	return nil
+}
+
 func (r *UserRepository) getFilterSQL(_ context.Context, filter UserFilter) (sql string, args pgx.NamedArgs) {
 	sql = `
 		select distinct _u.id
 		from public.users _u
 		join public.roles _r on _u.role_id = _r.id
 		where
+			_u.deleted_at is null
+			and
 			case
 				when array_length(@_ids::int[], 1) > 0 then
 					_u.id = any(@_ids)
@@ -141,12 +317,28 @@ func (r *UserRepository) getFilterSQL(_ context.Context, filter UserFilter) (sql
 				else
 					true
 			end
+			and
+			case
+				when @_search::text <> '' then
+					strpos(lower(_u.email), lower(@_search)) > 0
+				else
+					true
+			end
+			and
+			case
+				when @_is_disabled::bool is not null then
+					(_u.disabled_at is not null) = @_is_disabled
+				else
+					true
+			end
 	`
 	args = pgx.NamedArgs{
-		"_ids":      filter.IDs,
-		"_emails":   filter.Emails,
-		"_role_ids": filter.RoleIDs,
-		"_roles":    filter.Roles,
+		"_ids":         filter.IDs,
+		"_emails":      filter.Emails,
+		"_role_ids":    filter.RoleIDs,
+		"_roles":       filter.Roles,
+		"_search":      filter.Search,
+		"_is_disabled": filter.IsDisabled,
 	}
 	return sql, args
 }
@@ -169,7 +361,8 @@ func (r *UserRepository) FindRole(ctx context.Context, filter RoleFilter) ([]Rol
 		`
 			select
 				r.id,
-				r.name
+				r.name,
+				array(select rp.permission from public.role_permissions rp where rp.role_id = r.id order by rp.permission)
 			from
 				public.roles r
 			where
@@ -186,7 +379,7 @@ func (r *UserRepository) FindRole(ctx context.Context, filter RoleFilter) ([]Rol
 	var roles []Role
 	for rows.Next() {
 		var role Role
-		err := rows.Scan(&role.ID, &role.Name)
+		err := rows.Scan(&role.ID, &role.Name, &role.Permissions)
 		if err != nil {
 			return nil, NewSQLErr(err)
 		}
@@ -222,7 +415,8 @@ func (r *UserRepository) PaginateRole(ctx context.Context, filter RoleFilter, pa
 	sql = fmt.Sprintf(`
 		select
 			r.id,
-			r.name
+			r.name,
+			array(select rp.permission from public.role_permissions rp where rp.role_id = r.id order by rp.permission)
 		from
 			public.roles r
 		where
@@ -251,6 +445,7 @@ func (r *UserRepository) PaginateRole(ctx context.Context, filter RoleFilter, pa
 		err := rows.Scan(
 			&role.ID,
 			&role.Name,
+			&role.Permissions,
 		)
 		if err != nil {
 			return nil, NewSQLErr(err)
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewUserTokenRepository(tx pgx.Tx) *UserTokenRepository {
+	return &UserTokenRepository{
+		tx: tx,
+	}
+}
+
+type UserTokenRepository struct {
+	tx pgx.Tx
+}
+
+func (r *UserTokenRepository) Create(ctx context.Context, token *UserToken) (int64, error) {
+	sql := `
+		insert into public.user_tokens (user_id, purpose, token_hash, expires_at)
+		values (@user_id, @purpose, @token_hash, @expires_at)
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"user_id":    token.UserID,
+		"purpose":    token.Purpose,
+		"token_hash": token.TokenHash,
+		"expires_at": token.ExpiresAt,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// Use mark the token as used, it only succeed once so the same token cannot be used concurrently
+func (r *UserTokenRepository) Use(ctx context.Context, ID int64) error {
+	sql := `
+		update public.user_tokens
+		set used_at = now()
+		where id = @id and used_at is null and expires_at > now()
+		returning id
+	`
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"id": ID}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "token invalid or expired")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// Expire make the usable tokens of the user for the purpose unusable, e.g. when a new one is sent
+func (r *UserTokenRepository) Expire(ctx context.Context, userID int64, purpose UserTokenPurpose) error {
+	sql := `
+		update public.user_tokens
+		set expires_at = now()
+		where user_id = @user_id and purpose = @purpose and used_at is null and expires_at > now()
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{"user_id": userID, "purpose": purpose})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+func (r *UserTokenRepository) FindOne(ctx context.Context, filter UserTokenFilter) (*UserToken, error) {
+	tokens, err := r.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "token not found")
+	}
+	return &tokens[0], nil
+}
+
+func (r *UserTokenRepository) Find(ctx context.Context, filter UserTokenFilter) ([]UserToken, error) {
+	filterSQL, filterArgs := r.getFilterSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				ut.id,
+				ut.user_id,
+				ut.purpose,
+				ut.token_hash,
+				ut.expires_at,
+				ut.used_at,
+				ut.created_at
+			from
+				public.user_tokens ut
+			where
+				ut.id in (%s)
+			order by
+				ut.id desc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var tokens []UserToken
+	for rows.Next() {
+		var token UserToken
+		err := rows.Scan(
+			&token.ID,
+			&token.UserID,
+			&token.Purpose,
+			&token.TokenHash,
+			&token.ExpiresAt,
+			&token.UsedAt,
+			&token.CreatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		tokens = append(tokens, token)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return tokens, nil
+}
+
+func (r *UserTokenRepository) getFilterSQL(_ context.Context, filter UserTokenFilter) (sql string, args pgx.NamedArgs) {
+	purposes := make([]string, 0, len(filter.Purposes))
+	for _, purpose := range filter.Purposes {
+		purposes = append(purposes, string(purpose))
+	}
+
+	sql = `
+		select _ut.id
+		from public.user_tokens _ut
+		where
+			case
+				when array_length(@_user_ids::int[], 1) > 0 then
+					_ut.user_id = any(@_user_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_purposes::text[], 1) > 0 then
+					_ut.purpose = any(@_purposes)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_token_hashes::text[], 1) > 0 then
+					_ut.token_hash = any(@_token_hashes)
+				else
+					true
+			end
+			and
+			case
+				when @_created_after::timestamptz is not null then
+					_ut.created_at > @_created_after
+				else
+					true
+			end
+			and
+			case
+				when @_is_usable::bool is not null then
+					(_ut.used_at is null and _ut.expires_at > now()) = @_is_usable
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_user_ids":      filter.UserIDs,
+		"_purposes":      purposes,
+		"_token_hashes":  filter.TokenHashes,
+		"_is_usable":     filter.IsUsable,
+		"_created_after": filter.CreatedAfter,
+	}
+	return sql, args
+}
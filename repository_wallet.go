@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func NewWalletRepository(tx pgx.Tx) *WalletRepository {
+	return &WalletRepository{
+		tx: tx,
+	}
+}
+
+type WalletRepository struct {
+	tx pgx.Tx
+}
+
+// wallets
+
+func (r *WalletRepository) Get(ctx context.Context, userID int64) (*Wallet, error) {
+	sql := `select user_id, balance, updated_at from public.wallets where user_id = @user_id`
+	var wallet Wallet
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{"user_id": userID}).Scan(
+		&wallet.UserID,
+		&wallet.Balance,
+		&wallet.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return &Wallet{UserID: userID}, nil
+	}
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return &wallet, nil
+}
+
+func (r *WalletRepository) Credit(ctx context.Context, userID, amount int64) error {
+	sql := `
+		insert into public.wallets (user_id, balance)
+		values (@user_id, @amount)
+		on conflict (user_id) do update
+		set balance = wallets.balance + excluded.balance, updated_at = now()
+	`
+	_, err := r.tx.Exec(ctx, sql, pgx.NamedArgs{
+		"user_id": userID,
+		"amount":  amount,
+	})
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// Debit take money from wallet only when the balance is enough,
+// the conditional update lock the row so concurrent payment cannot overdraw
+func (r *WalletRepository) Debit(ctx context.Context, userID, amount int64) error {
+	sql := `
+		update public.wallets
+		set balance = balance - @amount, updated_at = now()
+		where user_id = @user_id and balance >= @amount
+		returning user_id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"user_id": userID,
+		"amount":  amount,
+	}).Scan(&ID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return NewErr(ErrInput, nil, "insufficient wallet balance")
+	}
+	if err != nil {
+		return NewSQLErr(err)
+	}
+	return nil
+}
+
+// gift cards
+
+func (r *WalletRepository) CreateGiftCard(ctx context.Context, giftCard *GiftCard) (int64, error) {
+	sql := `
+		insert into public.gift_cards (code, initial_amount, balance, "source", purchaser_id)
+		values (@code, @initial_amount, @balance, @source, nullif(@purchaser_id, 0))
+		returning id
+	`
+	var ID int64
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"code":           giftCard.Code,
+		"initial_amount": giftCard.InitialAmount,
+		"balance":        giftCard.Balance,
+		"source":         giftCard.Source,
+		"purchaser_id":   giftCard.PurchaserID,
+	}).Scan(&ID)
+	if err != nil {
+		return 0, NewSQLErr(err)
+	}
+	return ID, nil
+}
+
+// RedeemGiftCard empty the gift card balance for the user, a gift card can only be redeemed once
+func (r *WalletRepository) RedeemGiftCard(ctx context.Context, code string, userID int64) (*GiftCard, error) {
+	sql := `
+		update public.gift_cards gc
+		set balance = 0, redeemed_by_id = @user_id, redeemed_at = now(), updated_at = now()
+		from (select id, balance from public.gift_cards where code = @code for update) old
+		where gc.id = old.id and old.balance > 0
+		returning gc.id, old.balance
+	`
+	var giftCard GiftCard
+	err := r.tx.QueryRow(ctx, sql, pgx.NamedArgs{
+		"code":    code,
+		"user_id": userID,
+	}).Scan(&giftCard.ID, &giftCard.Balance)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, NewErr(ErrInput, nil, "gift card is invalid or already redeemed")
+	}
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return &giftCard, nil
+}
+
+func (r *WalletRepository) FindOneGiftCard(ctx context.Context, filter GiftCardFilter) (*GiftCard, error) {
+	giftCards, err := r.FindGiftCard(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if len(giftCards) == 0 {
+		return nil, NewErr(ErrNotFound, nil, "gift card not found")
+	}
+	return &giftCards[0], nil
+}
+
+func (r *WalletRepository) FindGiftCard(ctx context.Context, filter GiftCardFilter) ([]GiftCard, error) {
+	filterSQL, filterArgs := r.getFilterGiftCardSQL(ctx, filter)
+
+	sql := fmt.Sprintf(
+		`
+			select
+				gc.id,
+				gc.code,
+				gc.initial_amount,
+				gc.balance,
+				gc."source",
+				coalesce(gc.purchaser_id, 0),
+				coalesce(gc.redeemed_by_id, 0),
+				gc.redeemed_at,
+				gc.created_at,
+				gc.updated_at
+			from
+				public.gift_cards gc
+			where
+				gc.id in (%s)
+			order by
+				gc.id desc
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, filterArgs)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var giftCards []GiftCard
+	for rows.Next() {
+		var giftCard GiftCard
+		err := rows.Scan(
+			&giftCard.ID,
+			&giftCard.Code,
+			&giftCard.InitialAmount,
+			&giftCard.Balance,
+			&giftCard.Source,
+			&giftCard.PurchaserID,
+			&giftCard.RedeemedByID,
+			&giftCard.RedeemedAt,
+			&giftCard.CreatedAt,
+			&giftCard.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		giftCards = append(giftCards, giftCard)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	return giftCards, nil
+}
+
+func (r *WalletRepository) PaginationGiftCard(ctx context.Context, filter GiftCardFilter, page PaginateInput) (*Paginate[GiftCard], error) {
+	filterSQL, filterArgs := r.getFilterGiftCardSQL(ctx, filter)
+
+	var totalItems int64
+	sql := fmt.Sprintf(`select count(*) from (%s)`, filterSQL)
+	err := r.tx.QueryRow(ctx, sql, filterArgs).Scan(&totalItems)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p := NewPaginate([]GiftCard{}, totalItems, page.Page, page.Size)
+	if totalItems == 0 {
+		return p, nil
+	}
+
+	if page.Page > p.TotalPage {
+		page.Page = p.TotalPage
+		p.CurrentPage = page.Page
+	}
+
+	sql = fmt.Sprintf(
+		`
+			select
+				gc.id,
+				gc.code,
+				gc.initial_amount,
+				gc.balance,
+				gc."source",
+				coalesce(gc.purchaser_id, 0),
+				coalesce(gc.redeemed_by_id, 0),
+				gc.redeemed_at,
+				gc.created_at,
+				gc.updated_at
+			from
+				public.gift_cards gc
+			where
+				gc.id in (%s)
+			order by
+				gc.id desc
+			limit @page_size offset (@page - 1) * @page_size
+		`,
+		filterSQL,
+	)
+	rows, err := r.tx.Query(ctx, sql, mergeNamedArgs(
+		filterArgs,
+		pgx.NamedArgs{
+			"page":      page.Page,
+			"page_size": page.Size,
+		}),
+	)
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	defer rows.Close()
+
+	var giftCards []GiftCard
+	for rows.Next() {
+		var giftCard GiftCard
+		err := rows.Scan(
+			&giftCard.ID,
+			&giftCard.Code,
+			&giftCard.InitialAmount,
+			&giftCard.Balance,
+			&giftCard.Source,
+			&giftCard.PurchaserID,
+			&giftCard.RedeemedByID,
+			&giftCard.RedeemedAt,
+			&giftCard.CreatedAt,
+			&giftCard.UpdatedAt,
+		)
+		if err != nil {
+			return nil, NewSQLErr(err)
+		}
+		giftCards = append(giftCards, giftCard)
+	}
+	err = rows.Err()
+	if err != nil {
+		return nil, NewSQLErr(err)
+	}
+	p.Items = giftCards
+	return p, nil
+}
+
+func (r *WalletRepository) getFilterGiftCardSQL(_ context.Context, filter GiftCardFilter) (sql string, args pgx.NamedArgs) {
+	sql = `
+		select _gc.id
+		from public.gift_cards _gc
+		where
+			case
+				when array_length(@_ids::int[], 1) > 0 then
+					_gc.id = any(@_ids)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_codes::text[], 1) > 0 then
+					_gc.code = any(@_codes)
+				else
+					true
+			end
+			and
+			case
+				when array_length(@_purchaser_ids::int[], 1) > 0 then
+					_gc.purchaser_id = any(@_purchaser_ids)
+				else
+					true
+			end
+			and
+			case
+				when @_is_redeemed::bool is not null then
+					(_gc.redeemed_at is not null) = @_is_redeemed
+				else
+					true
+			end
+	`
+	args = pgx.NamedArgs{
+		"_ids":           filter.IDs,
+		"_codes":         filter.Codes,
+		"_purchaser_ids": filter.PurchaserIDs,
+		"_is_redeemed":   filter.IsRedeemed,
+	}
+	return sql, args
+}
@@ -1,22 +1,58 @@
 package main
 
 type ServiceRegistry struct {
-	User        *UserService
-	Movie       *MovieService
-	Room        *RoomService
-	Showtime    *ShowtimeService
-	Reservation *ReservationService
-	Cart        *CartService
+	User           *UserService
+	Movie          *MovieService
+	Room           *RoomService
+	Showtime       *ShowtimeService
+	Reservation    *ReservationService
+	Cart           *CartService
+	Planner        *PlannerService
+	Cinema         *CinemaService
+	Blackout       *BlackoutService
+	PricingRule    *PricingRuleService
+	TicketType     *TicketTypeService
+	Fee            *FeeService
+	PromoCode      *PromoCodeService
+	Point          *PointService
+	Wallet         *WalletService
+	Reconciliation *ReconciliationService
+	Ticket         *TicketService
+	Calendar       *CalendarService
+	MFA            *MFAService
+	LoginAttempt   *LoginAttemptService
+	OIDC           *OIDCService
+	Role           *RoleService
+	Profile        *ProfileService
+	Payment        *PaymentService
 }
 
 func NewService(config *Config, repo *RepositoryRegistry) *ServiceRegistry {
 	service := ServiceRegistry{
-		User:        NewUserService(config, repo),
-		Movie:       NewMovieService(config, repo),
-		Room:        NewRoomService(config, repo),
-		Showtime:    NewShowtimeService(config, repo),
-		Reservation: NewReservationService(config, repo),
-		Cart:        NewCartService(config, repo),
+		User:           NewUserService(config, repo),
+		Movie:          NewMovieService(config, repo),
+		Room:           NewRoomService(config, repo),
+		Showtime:       NewShowtimeService(config, repo),
+		Reservation:    NewReservationService(config, repo),
+		Cart:           NewCartService(config, repo),
+		Planner:        NewPlannerService(config, repo),
+		Cinema:         NewCinemaService(config, repo),
+		Blackout:       NewBlackoutService(config, repo),
+		PricingRule:    NewPricingRuleService(config, repo),
+		TicketType:     NewTicketTypeService(config, repo),
+		Fee:            NewFeeService(config, repo),
+		PromoCode:      NewPromoCodeService(config, repo),
+		Point:          NewPointService(config, repo),
+		Wallet:         NewWalletService(config, repo),
+		Reconciliation: NewReconciliationService(config, repo),
+		Ticket:         NewTicketService(config, repo),
+		Calendar:       NewCalendarService(config, repo),
+		MFA:            NewMFAService(config, repo),
+		LoginAttempt:   NewLoginAttemptService(config, repo),
+		OIDC:           NewOIDCService(config, repo),
+		Role:           NewRoleService(config, repo),
+		Profile:        NewProfileService(config, repo),
+		Payment:        NewPaymentService(config, repo),
 	}
 	return &service
 }
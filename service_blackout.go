@@ -0,0 +1,119 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+func NewBlackoutService(config *Config, repo *RepositoryRegistry) *BlackoutService {
+	return &BlackoutService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type BlackoutService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *BlackoutService) Create(ctx context.Context, input BlackoutInput) (*Blackout, error) {
+	newBlackout, err := NewBlackout(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.validateTarget(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := s.repo.Blackout.Create(ctx, newBlackout)
+	if err != nil {
+		return nil, err
+	}
+
+	blackout, err := s.repo.Blackout.FindOne(ctx, BlackoutFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return blackout, nil
+}
+
+func (s *BlackoutService) UpdateByID(ctx context.Context, ID int64, input BlackoutInput) (*Blackout, error) {
+	_, err := s.repo.Blackout.FindOne(ctx, BlackoutFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.validateTarget(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Blackout.UpdateByID(ctx, ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	blackout, err := s.repo.Blackout.FindOne(ctx, BlackoutFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return blackout, nil
+}
+
+func (s *BlackoutService) GetByID(ctx context.Context, ID int64) (*Blackout, error) {
+	return s.repo.Blackout.FindOne(ctx, BlackoutFilter{IDs: []int64{ID}})
+}
+
+func (s *BlackoutService) DeleteByID(ctx context.Context, ID int64) error {
+	err := s.repo.Blackout.DeleteByID(ctx, ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *BlackoutService) Pagination(ctx context.Context, filter BlackoutFilter, page PaginateInput) (*Paginate[Blackout], error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Blackout.Pagination(ctx, filter, page)
+}
+
+// ValidateRoomAvailable make sure no blackout of the room or its cinema overlap the time range
+func (s *BlackoutService) ValidateRoomAvailable(ctx context.Context, roomID int64, startAt, endAt time.Time) error {
+	blackouts, err := s.repo.Blackout.Find(ctx, BlackoutFilter{
+		AffectRoomIDs: []int64{roomID},
+		After:         startAt,
+		Before:        endAt,
+	})
+	if err != nil {
+		return err
+	}
+	for _, blackout := range blackouts {
+		err = blackout.ValidateOverlapping(startAt, endAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *BlackoutService) validateTarget(ctx context.Context, input BlackoutInput) error {
+	if input.RoomID > 0 {
+		_, err := s.repo.Room.FindOne(ctx, RoomFilter{IDs: []int64{input.RoomID}})
+		return err
+	}
+	_, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{input.CinemaID}})
+	return err
+}
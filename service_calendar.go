@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func NewCalendarService(config *Config, repo *RepositoryRegistry) *CalendarService {
+	return &CalendarService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type CalendarService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *CalendarService) Reservation(ctx context.Context, userID, ID int64) ([]byte, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{ID}, UserIDs: []int64{userID}, WithItems: true})
+	if err != nil {
+		return nil, err
+	}
+	location := ""
+	if reservation.CinemaID > 0 {
+		cinema, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{reservation.CinemaID}})
+		if err != nil {
+			return nil, err
+		}
+		location = cinemaLocation(cinema)
+	}
+	events := NewReservationEvents(reservation, location)
+	return RenderCalendar(fmt.Sprintf("Reservation #%d", reservation.ID), events), nil
+}
+
+// Cinema is the feed of upcoming showtimes in every room of the cinema
+func (s *CalendarService) Cinema(ctx context.Context, cinemaID int64) ([]byte, error) {
+	cinema, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{cinemaID}})
+	if err != nil {
+		return nil, err
+	}
+	rooms, err := s.repo.Room.Find(ctx, RoomFilter{CinemaIDs: []int64{cinemaID}})
+	if err != nil {
+		return nil, err
+	}
+	// empty room filter would list showtimes of every cinema
+	if len(rooms) == 0 {
+		return RenderCalendar(cinema.Name, []CalendarEvent{}), nil
+	}
+	roomIDs := make([]int64, 0, len(rooms))
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	showtimes, err := s.repo.Showtime.Find(ctx, ShowtimeFilter{RoomIDs: roomIDs, After: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	events := make([]CalendarEvent, 0, len(showtimes))
+	for _, showtime := range showtimes {
+		events = append(events, NewShowtimeEvent(&showtime, cinemaLocation(cinema)))
+	}
+	return RenderCalendar(cinema.Name, events), nil
+}
+
+// Movie is the feed of upcoming showtimes of the movie in every cinema
+func (s *CalendarService) Movie(ctx context.Context, movieID int64) ([]byte, error) {
+	movie, err := s.repo.Movie.FindOne(ctx, MovieFilter{IDs: []int64{movieID}})
+	if err != nil {
+		return nil, err
+	}
+	showtimes, err := s.repo.Showtime.Find(ctx, ShowtimeFilter{MovieIDs: []int64{movieID}, After: time.Now()})
+	if err != nil {
+		return nil, err
+	}
+	locations, err := s.roomLocations(ctx, showtimes)
+	if err != nil {
+		return nil, err
+	}
+	events := make([]CalendarEvent, 0, len(showtimes))
+	for _, showtime := range showtimes {
+		events = append(events, NewShowtimeEvent(&showtime, locations[showtime.RoomID]))
+	}
+	return RenderCalendar(movie.Title, events), nil
+}
+
+// roomLocations map room of the showtimes to the cinema location
+func (s *CalendarService) roomLocations(ctx context.Context, showtimes []Showtime) (map[int64]string, error) {
+	locations := map[int64]string{}
+	if len(showtimes) == 0 {
+		return locations, nil
+	}
+	roomIDs := make([]int64, 0, len(showtimes))
+	for _, showtime := range showtimes {
+		roomIDs = append(roomIDs, showtime.RoomID)
+	}
+	rooms, err := s.repo.Room.Find(ctx, RoomFilter{IDs: roomIDs})
+	if err != nil {
+		return nil, err
+	}
+	cinemaIDs := []int64{}
+	for _, room := range rooms {
+		if room.CinemaID > 0 {
+			cinemaIDs = append(cinemaIDs, room.CinemaID)
+		}
+	}
+	if len(cinemaIDs) == 0 {
+		return locations, nil
+	}
+	cinemas, err := s.repo.Cinema.Find(ctx, CinemaFilter{IDs: cinemaIDs})
+	if err != nil {
+		return nil, err
+	}
+	cinemaMap := map[int64]*Cinema{}
+	for i := range cinemas {
+		cinemaMap[cinemas[i].ID] = &cinemas[i]
+	}
+	for _, room := range rooms {
+		if cinema, ok := cinemaMap[room.CinemaID]; ok {
+			locations[room.ID] = cinemaLocation(cinema)
+		}
+	}
+	return locations, nil
+}
+
+func cinemaLocation(cinema *Cinema) string {
+	if cinema.Address == "" {
+		return cinema.Name
+	}
+	return fmt.Sprintf("%s, %s", cinema.Name, cinema.Address)
+}
@@ -4,14 +4,16 @@ import "context"
 
 func NewCartService(config *Config, repo *RepositoryRegistry) *CartService {
 	return &CartService{
-		config: config,
-		repo:   repo,
+		config:     config,
+		repo:       repo,
+		ticketType: NewTicketTypeService(config, repo),
 	}
 }
 
 type CartService struct {
-	config *Config
-	repo   *RepositoryRegistry
+	config     *Config
+	repo       *RepositoryRegistry
+	ticketType *TicketTypeService
 }
 
 func (s *CartService) Create(ctx context.Context, input CartInput) (*Cart, error) {
@@ -21,6 +23,12 @@ func (s *CartService) Create(ctx context.Context, input CartInput) (*Cart, error
 		return nil, err
 	}
 
+	ticketType, err := s.ticketType.Resolve(ctx, input.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	newCart.TicketTypeID = ticketType.ID
+
 	ID, err := s.repo.Cart.Create(ctx, newCart)
 	if err != nil {
 		return nil, err
@@ -41,6 +49,12 @@ func (s *CartService) UserUpdateByID(ctx context.Context, userID, ID int64, inpu
 		return nil, err
 	}
 
+	ticketType, err := s.ticketType.Resolve(ctx, input.TicketTypeID)
+	if err != nil {
+		return nil, err
+	}
+	input.TicketTypeID = ticketType.ID
+
 	err = s.repo.Cart.UpdateByID(ctx, ID, input)
 	if err != nil {
 		return nil, err
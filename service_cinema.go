@@ -0,0 +1,92 @@
+package main
+
+import "context"
+
+func NewCinemaService(config *Config, repo *RepositoryRegistry) *CinemaService {
+	return &CinemaService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type CinemaService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *CinemaService) Create(ctx context.Context, input CinemaInput) (*Cinema, error) {
+	newCinema, err := NewCinema(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := s.repo.Cinema.Create(ctx, newCinema)
+	if err != nil {
+		return nil, err
+	}
+
+	cinema, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return cinema, nil
+}
+
+func (s *CinemaService) UpdateByID(ctx context.Context, ID int64, input CinemaInput) (*Cinema, error) {
+	current, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	// fees, showtime and seat prices of the cinema are priced in its currency
+	if input.Currency != current.Currency {
+		if current.TotalRoom > 0 {
+			return nil, NewErr(ErrInput, nil, "currency cannot be changed while the cinema has rooms")
+		}
+		fees, err := s.repo.Fee.Find(ctx, FeeFilter{CinemaIDs: []int64{ID}})
+		if err != nil {
+			return nil, err
+		}
+		if len(fees) > 0 {
+			return nil, NewErr(ErrInput, nil, "currency cannot be changed while the cinema has fees")
+		}
+	}
+
+	err = s.repo.Cinema.UpdateByID(ctx, ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	cinema, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return cinema, nil
+}
+
+func (s *CinemaService) GetByID(ctx context.Context, ID int64) (*Cinema, error) {
+	return s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{ID}})
+}
+
+func (s *CinemaService) DeleteByID(ctx context.Context, ID int64) error {
+	err := s.repo.Cinema.DeleteByID(ctx, ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *CinemaService) Pagination(ctx context.Context, filter CinemaFilter, page PaginateInput) (*Paginate[Cinema], error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Cinema.Pagination(ctx, filter, page)
+}
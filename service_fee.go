@@ -0,0 +1,99 @@
+package main
+
+import "context"
+
+func NewFeeService(config *Config, repo *RepositoryRegistry) *FeeService {
+	return &FeeService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type FeeService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *FeeService) Create(ctx context.Context, input FeeInput) (*Fee, error) {
+	newFee, err := NewFee(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.prepare(ctx, newFee.CinemaID, newFee.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := s.repo.Fee.Create(ctx, newFee)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Fee.FindOne(ctx, FeeFilter{IDs: []int64{ID}})
+}
+
+func (s *FeeService) UpdateByID(ctx context.Context, ID int64, input FeeInput) (*Fee, error) {
+	_, err := s.repo.Fee.FindOne(ctx, FeeFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.prepare(ctx, input.CinemaID, input.Currency)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Fee.UpdateByID(ctx, ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Fee.FindOne(ctx, FeeFilter{IDs: []int64{ID}})
+}
+
+func (s *FeeService) GetByID(ctx context.Context, ID int64) (*Fee, error) {
+	return s.repo.Fee.FindOne(ctx, FeeFilter{IDs: []int64{ID}})
+}
+
+func (s *FeeService) DeleteByID(ctx context.Context, ID int64) error {
+	_, err := s.repo.Fee.FindOne(ctx, FeeFilter{IDs: []int64{ID}})
+	if err != nil {
+		return err
+	}
+	return s.repo.Fee.DeleteByID(ctx, ID)
+}
+
+func (s *FeeService) List(ctx context.Context, filter FeeFilter) ([]Fee, error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	fees, err := s.repo.Fee.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if fees == nil {
+		fees = []Fee{}
+	}
+	return fees, nil
+}
+
+// prepare check the cinema of the fee exists and charge in the same currency
+func (s *FeeService) prepare(ctx context.Context, cinemaID int64, currency Currency) error {
+	if cinemaID > 0 {
+		cinema, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{cinemaID}})
+		if err != nil {
+			return err
+		}
+		if cinema.Currency != currency {
+			return NewErr(ErrInput, nil, "fee currency %s differs from cinema currency %s", currency, cinema.Currency)
+		}
+	}
+	return nil
+}
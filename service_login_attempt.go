@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+func NewLoginAttemptService(config *Config, repo *RepositoryRegistry) *LoginAttemptService {
+	return &LoginAttemptService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type LoginAttemptService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+// Attempt count the login as failed before the password is checked, while the throttles of the account and the ip are locked,
+// so parallel guesses are counted one by one and cannot all pass before any failure is stored.
+// It return how long until login is allowed again when locked, the refused attempt is not counted
+func (s *LoginAttemptService) Attempt(ctx context.Context, email, ip string) (time.Duration, error) {
+	limits := []loginLimit{{LoginThrottleIP, ip, s.config.LoginIPMaxFailures}}
+	email = normalizeLoginEmail(email)
+	if email != "" {
+		// always locked in the same order, account before ip, so two attempts never wait for each other
+		limits = append([]loginLimit{{LoginThrottleAccount, email, s.config.LoginMaxFailures}}, limits...)
+	}
+
+	var wait time.Duration
+	for _, limit := range limits {
+		throttle, err := s.repo.LoginAttempt.Acquire(ctx, limit.scope, limit.key)
+		if err != nil {
+			return 0, err
+		}
+		if throttle.LockedUntil != nil {
+			wait = max(wait, time.Until(*throttle.LockedUntil))
+		}
+	}
+	if wait > 0 {
+		return wait, nil
+	}
+
+	for _, limit := range limits {
+		err := s.fail(ctx, limit.scope, limit.key, limit.maxFailures)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return 0, nil
+}
+
+type loginLimit struct {
+	scope       LoginThrottleScope
+	key         string
+	maxFailures int64
+}
+
+// Record audit the attempt, the failure was already counted by Attempt and is taken back on success.
+// Failures of the account are reset only when signed in, not when the password is correct but second factor is still needed
+func (s *LoginAttemptService) Record(ctx context.Context, attempt *LoginAttempt, signedIn bool) error {
+	if attempt.Email != "" {
+		user, err := s.repo.User.FindOne(ctx, UserFilter{Emails: []string{attempt.Email}})
+		if err != nil && !ErrIs(err, ErrNotFound) {
+			return err
+		}
+		if user != nil {
+			attempt.UserID = &user.ID
+		}
+	}
+	ID, err := s.repo.LoginAttempt.Create(ctx, attempt)
+	if err != nil {
+		return err
+	}
+	attempt.ID = ID
+
+	if !attempt.Success {
+		return nil
+	}
+	// ip only take back this attempt and is never reset by success, an attacker could sign in to own account to clear it
+	err = s.repo.LoginAttempt.Forgive(ctx, LoginThrottleIP, attempt.IP, s.config.LoginIPMaxFailures)
+	if err != nil {
+		return err
+	}
+	if attempt.Email == "" {
+		return nil
+	}
+	if signedIn {
+		return s.repo.LoginAttempt.Reset(ctx, LoginThrottleAccount, attempt.Email)
+	}
+	return s.repo.LoginAttempt.Forgive(ctx, LoginThrottleAccount, attempt.Email, s.config.LoginMaxFailures)
+}
+
+// Unlock clear the failed login of the user account
+func (s *LoginAttemptService) Unlock(ctx context.Context, userID int64) (*User, error) {
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.LoginAttempt.Reset(ctx, LoginThrottleAccount, normalizeLoginEmail(user.Email))
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *LoginAttemptService) Pagination(ctx context.Context, filter LoginAttemptFilter, page PaginateInput) (*Paginate[LoginAttempt], error) {
+	for i, email := range filter.Emails {
+		filter.Emails[i] = normalizeLoginEmail(email)
+	}
+	return s.repo.LoginAttempt.Pagination(ctx, filter, page)
+}
+
+func (s *LoginAttemptService) fail(ctx context.Context, scope LoginThrottleScope, key string, maxFailures int64) error {
+	failures, err := s.repo.LoginAttempt.Fail(ctx, scope, key, loginFailureWindow)
+	if err != nil {
+		return err
+	}
+	wait := LoginBackoff(
+		failures,
+		maxFailures,
+		time.Duration(s.config.LoginBackoffSeconds)*time.Second,
+		time.Duration(s.config.LoginLockoutMinutes)*time.Minute,
+	)
+	if wait == 0 {
+		return nil
+	}
+	return s.repo.LoginAttempt.Lock(ctx, scope, key, time.Now().Add(wait))
+}
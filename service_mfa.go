@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+func NewMFAService(config *Config, repo *RepositoryRegistry) *MFAService {
+	return &MFAService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type MFAService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *MFAService) Status(ctx context.Context, userID int64) (*MFAStatus, error) {
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	status := MFAStatus{Enabled: user.IsMFAEnabled(), EnabledAt: user.MFAEnabledAt}
+	if status.Enabled {
+		status.BackupCodes, err = s.repo.MFA.CountBackupCodes(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &status, nil
+}
+
+// Enroll create a new secret to add to authenticator app, it is enabled after Confirm
+func (s *MFAService) Enroll(ctx context.Context, userID int64) (*MFAEnrollment, error) {
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	if user.IsMFAEnabled() {
+		return nil, NewErr(ErrInput, nil, "two-factor already enabled, disable it first")
+	}
+
+	secret, uri, err := GenerateMFASecret(user.Email)
+	if err != nil {
+		return nil, err
+	}
+	qrCode, err := MFAQRCodeDataURI(uri)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.MFA.SetSecret(ctx, userID, secret)
+	if err != nil {
+		return nil, err
+	}
+	return &MFAEnrollment{Secret: secret, URI: uri, QRCode: qrCode}, nil
+}
+
+// Confirm enable two-factor once the app produce a valid code, the backup codes are only shown here
+func (s *MFAService) Confirm(ctx context.Context, userID int64, input MFACodeInput) (*MFABackupCodes, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	if user.IsMFAEnabled() {
+		return nil, NewErr(ErrInput, nil, "two-factor already enabled")
+	}
+	if user.MFASecret == "" {
+		return nil, NewErr(ErrInput, nil, "two-factor enrollment not started")
+	}
+
+	err = s.verifyTOTP(ctx, user, input.Code)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.MFA.Enable(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return s.newBackupCodes(ctx, userID)
+}
+
+// RegenerateBackupCodes replace every backup code, a TOTP code is needed
+func (s *MFAService) RegenerateBackupCodes(ctx context.Context, userID int64, input MFACodeInput) (*MFABackupCodes, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.enabledUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.verifyTOTP(ctx, user, input.Code)
+	if err != nil {
+		return nil, err
+	}
+	return s.newBackupCodes(ctx, userID)
+}
+
+// Disable two-factor using TOTP or backup code
+func (s *MFAService) Disable(ctx context.Context, userID int64, input MFACodeInput) error {
+	err := input.Validate()
+	if err != nil {
+		return err
+	}
+	user, err := s.enabledUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	err = s.Verify(ctx, user, input.Code)
+	if err != nil {
+		return err
+	}
+	return s.repo.MFA.Disable(ctx, userID)
+}
+
+// Verify the second factor of the user, either TOTP code or unused backup code
+func (s *MFAService) Verify(ctx context.Context, user *User, code string) error {
+	code = normalizeMFACode(code)
+	if len(code) == 6 {
+		return s.verifyTOTP(ctx, user, code)
+	}
+	used, err := s.repo.MFA.UseBackupCode(ctx, user.ID, HashToken(code))
+	if err != nil {
+		return err
+	}
+	if !used {
+		return NewErr(ErrInput, nil, "two-factor code invalid")
+	}
+	return nil
+}
+
+func (s *MFAService) verifyTOTP(ctx context.Context, user *User, code string) error {
+	step, ok := VerifyTOTP(user.MFASecret, code, user.MFALastStep, time.Now())
+	if !ok {
+		return NewErr(ErrInput, nil, "two-factor code invalid")
+	}
+	return s.repo.MFA.UseStep(ctx, user.ID, step)
+}
+
+func (s *MFAService) enabledUser(ctx context.Context, userID int64) (*User, error) {
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsMFAEnabled() {
+		return nil, NewErr(ErrInput, nil, "two-factor is not enabled")
+	}
+	return user, nil
+}
+
+func (s *MFAService) newBackupCodes(ctx context.Context, userID int64) (*MFABackupCodes, error) {
+	codes, hashes := NewMFABackupCodes()
+	err := s.repo.MFA.ReplaceBackupCodes(ctx, userID, hashes)
+	if err != nil {
+		return nil, err
+	}
+	return &MFABackupCodes{Codes: codes}, nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"slices"
+)
+
+func NewOIDCService(config *Config, repo *RepositoryRegistry) *OIDCService {
+	return &OIDCService{
+		config: config,
+		repo:   repo,
+		user:   NewUserService(config, repo),
+	}
+}
+
+type OIDCService struct {
+	config *Config
+	repo   *RepositoryRegistry
+	user   *UserService
+}
+
+// Providers is the name of the configured identity providers
+func (s *OIDCService) Providers() []string {
+	names := make([]string, 0, len(s.config.OIDCProviders))
+	for name := range s.config.OIDCProviders {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Authorize start sign in with the provider using authorization code flow with PKCE
+func (s *OIDCService) Authorize(ctx context.Context, providerName string) (*OIDCAuthorization, error) {
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	oidcState, state := NewOIDCState(provider.Name)
+	url, err := provider.AuthCodeURL(ctx, state, oidcState.Nonce, oidcState.CodeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	_, err = s.repo.OIDC.CreateState(ctx, oidcState)
+	if err != nil {
+		return nil, err
+	}
+	return &OIDCAuthorization{URL: url, State: state}, nil
+}
+
+// Callback complete sign in with the code from the provider and issue our own token,
+// browserState is the state kept by the browser on authorize and has to match the state from the provider
+func (s *OIDCService) Callback(ctx context.Context, providerName string, input OIDCCallbackInput, browserState, userAgent string) (*AuthToken, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if subtle.ConstantTimeCompare([]byte(browserState), []byte(input.State)) != 1 {
+		return nil, NewErr(ErrInput, nil, "sign in was not started from this browser, sign in again")
+	}
+	provider, err := s.provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+	oidcState, err := s.repo.OIDC.UseState(ctx, provider.Name, HashToken(input.State))
+	if err != nil {
+		return nil, err
+	}
+	identity, err := provider.Exchange(ctx, input.Code, oidcState.CodeVerifier, oidcState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.linkUser(ctx, identity)
+	if err != nil {
+		return nil, err
+	}
+	return s.user.signIn(ctx, user, userAgent)
+}
+
+func (s *OIDCService) Identities(ctx context.Context, userID int64) ([]UserIdentity, error) {
+	return s.repo.OIDC.FindIdentitiesByUserID(ctx, userID)
+}
+
+// linkUser find the user of the identity, on first sign in it is linked to the user with the same email
+// or a new user is created, both only when the provider verified the email
+func (s *OIDCService) linkUser(ctx context.Context, identity *OIDCIdentity) (*User, error) {
+	linked, err := s.repo.OIDC.FindIdentity(ctx, identity.Provider, identity.Subject)
+	if err == nil {
+		return s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{linked.UserID}})
+	}
+	if !ErrIs(err, ErrNotFound) {
+		return nil, err
+	}
+
+	if identity.Email == "" || !identity.EmailVerified {
+		return nil, NewErr(ErrInput, nil, "email of %s account is not verified", identity.Provider)
+	}
+	user, err := s.repo.User.FindOne(ctx, UserFilter{Emails: []string{identity.Email}})
+	if err != nil && !ErrIs(err, ErrNotFound) {
+		return nil, err
+	}
+	if user == nil {
+		user, err = s.createUser(ctx, identity.Email)
+		if err != nil {
+			return nil, err
+		}
+	} else if !user.IsEmailVerified() {
+		// whoever registered the email without verifying it may not own it, linking would give them the account
+		return nil, NewErr(ErrInput, nil, "account with this email is not verified, verify the email first")
+	}
+
+	_, err = s.repo.OIDC.CreateIdentity(ctx, &UserIdentity{
+		UserID:   user.ID,
+		Provider: identity.Provider,
+		Subject:  identity.Subject,
+		Email:    identity.Email,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// createUser register the user of the verified email with a random password, it can be set by password reset
+func (s *OIDCService) createUser(ctx context.Context, email string) (*User, error) {
+	newUser, err := NewUser(UserInput{Email: email, Password: randomToken(10)})
+	if err != nil {
+		return nil, err
+	}
+	ID, err := s.repo.User.Create(ctx, newUser)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.User.VerifyEmailByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{ID}})
+}
+
+func (s *OIDCService) provider(name string) (*OIDCProvider, error) {
+	provider, ok := s.config.OIDCProviders[name]
+	if !ok {
+		return nil, NewErr(ErrNotFound, nil, "identity provider %s not found", name)
+	}
+	return provider, nil
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"log"
+)
+
+func NewPaymentService(config *Config, repo *RepositoryRegistry) *PaymentService {
+	return &PaymentService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type PaymentService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+// Start record pending payment, it has to be committed before the gateway is called
+func (s *PaymentService) Start(ctx context.Context, payment *Payment) (*Payment, error) {
+	ID, err := s.repo.Payment.Create(ctx, payment)
+	if err != nil {
+		return nil, err
+	}
+	payment.ID = ID
+	return payment, nil
+}
+
+func (s *PaymentService) Finish(ctx context.Context, ID int64, status PaymentStatus, reference string) error {
+	return s.repo.Payment.Finish(ctx, ID, status, reference)
+}
+
+func (s *PaymentService) SetReference(ctx context.Context, ID int64, reference string) error {
+	return s.repo.Payment.SetReference(ctx, ID, reference)
+}
+
+func NewPaymentProcessor(config *Config, trxProvider *TransactionProvider) *PaymentProcessor {
+	return &PaymentProcessor{
+		config:      config,
+		trxProvider: trxProvider,
+	}
+}
+
+// PaymentProcessor call the payment gateway between database transactions,
+// a transaction is never kept open while waiting for the gateway and never rolled back after money moved
+type PaymentProcessor struct {
+	config      *Config
+	trxProvider *TransactionProvider
+}
+
+// Charge commit the pending payment from start, charge it, then store the purchase with settle.
+// Start return nil payment when there is nothing to charge, settle then get empty receipt.
+// When settle fail the charge is refunded, so the customer is never charged for nothing
+func (p *PaymentProcessor) Charge(
+	ctx context.Context,
+	start func(service *ServiceRegistry) (*Payment, error),
+	settle func(service *ServiceRegistry, receipt *PaymentReceipt) error,
+) error {
+	var payment *Payment
+	var err error
+	err = p.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		payment, err = start(service)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if payment == nil {
+		return p.trxProvider.TransactReadCommitted(ctx, func(service *ServiceRegistry) error {
+			return settle(service, &PaymentReceipt{})
+		})
+	}
+
+	// outcome of the gateway has to be recorded even when the client is gone
+	ctx = context.WithoutCancel(ctx)
+
+	gateway, err := NewPaymentGateway(p.config.PaymentGateway)
+	if err != nil {
+		return errors.Join(NewErr(ErrInternal, err, "payment gateway unavailable"), p.finish(ctx, payment.ID, PaymentFailed, ""))
+	}
+	receipt, err := gateway.Charge(ctx, PaymentCharge{
+		UserID:         payment.UserID,
+		Amount:         payment.Amount,
+		Description:    payment.Description,
+		IdempotencyKey: payment.IdempotencyKey,
+	})
+	if err != nil {
+		return errors.Join(err, p.finish(ctx, payment.ID, PaymentFailed, ""))
+	}
+
+	// read committed, settle wait on counter row like the receipt number of the cinema,
+	// failing with serialization error would refund a good payment
+	err = p.trxProvider.TransactReadCommitted(ctx, func(service *ServiceRegistry) error {
+		err := settle(service, receipt)
+		if err != nil {
+			return err
+		}
+		return service.Payment.Finish(ctx, payment.ID, PaymentSettled, receipt.Reference)
+	})
+	if err == nil {
+		return nil
+	}
+
+	refundErr := gateway.Refund(ctx, PaymentRefund{
+		Reference:      receipt.Reference,
+		Amount:         receipt.Amount,
+		IdempotencyKey: payment.IdempotencyKey,
+	})
+	if refundErr != nil {
+		// stay pending with the reference so it can be refunded by hand
+		log.Printf("payment #%d charged %s but not stored and refund failed: %s", payment.ID, receipt.Reference, refundErr)
+		return errors.Join(err, p.setReference(ctx, payment.ID, receipt.Reference))
+	}
+	return errors.Join(err, p.finish(ctx, payment.ID, PaymentReversed, receipt.Reference))
+}
+
+// Refund send committed pending refund to the gateway,
+// failed refund stay pending to be retried, the refund is already promised to the customer
+func (p *PaymentProcessor) Refund(ctx context.Context, payment *Payment) error {
+	if payment == nil {
+		return nil
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	gateway, err := NewPaymentGateway(p.config.PaymentGateway)
+	if err != nil {
+		return NewErr(ErrInternal, err, "payment gateway unavailable")
+	}
+	err = gateway.Refund(ctx, PaymentRefund{
+		Reference:      payment.Reference,
+		Amount:         payment.Amount,
+		IdempotencyKey: payment.IdempotencyKey,
+	})
+	if err != nil {
+		log.Printf("refund payment #%d of %s failed: %s", payment.ID, payment.Reference, err)
+		return err
+	}
+	return p.finish(ctx, payment.ID, PaymentSettled, payment.Reference)
+}
+
+func (p *PaymentProcessor) finish(ctx context.Context, ID int64, status PaymentStatus, reference string) error {
+	return p.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.Payment.Finish(ctx, ID, status, reference)
+	})
+}
+
+func (p *PaymentProcessor) setReference(ctx context.Context, ID int64, reference string) error {
+	return p.trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+		return service.Payment.SetReference(ctx, ID, reference)
+	})
+}
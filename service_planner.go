@@ -0,0 +1,109 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+func NewPlannerService(config *Config, repo *RepositoryRegistry) *PlannerService {
+	return &PlannerService{
+		config:   config,
+		repo:     repo,
+		showtime: NewShowtimeService(config, repo),
+	}
+}
+
+type PlannerService struct {
+	config   *Config
+	repo     *RepositoryRegistry
+	showtime *ShowtimeService
+}
+
+func (s *PlannerService) Propose(ctx context.Context, input PlannerInput) (*PlannerProposal, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	movies, err := s.repo.Movie.Find(ctx, MovieFilter{IDs: input.MovieIDs()})
+	if err != nil {
+		return nil, err
+	}
+	movieMap := map[int64]Movie{}
+	for _, movie := range movies {
+		movieMap[movie.ID] = movie
+	}
+	for _, item := range input.Movies {
+		movie, ok := movieMap[item.MovieID]
+		if !ok {
+			return nil, NewErr(ErrNotFound, nil, "movie %d not found", item.MovieID)
+		}
+		if !movie.ReleaseDate.Before(input.EndDate()) {
+			return nil, NewErr(ErrInput, nil, "movie %d is not released yet on planned days", item.MovieID)
+		}
+	}
+
+	roomFilter := RoomFilter{IDs: input.RoomIDs}
+	if len(input.RoomIDs) == 0 {
+		usable := true
+		roomFilter.IsUsable = &usable
+	}
+	rooms, err := s.repo.Room.Find(ctx, roomFilter)
+	if err != nil {
+		return nil, err
+	}
+	if len(rooms) == 0 {
+		return nil, NewErr(ErrInput, nil, "no room available to plan")
+	}
+	if len(input.RoomIDs) > 0 && len(rooms) != len(input.RoomIDs) {
+		return nil, NewErr(ErrNotFound, nil, "some rooms not found")
+	}
+
+	roomIDs := make([]int64, 0, len(rooms))
+	for _, room := range rooms {
+		roomIDs = append(roomIDs, room.ID)
+	}
+	// showtime started a day before still may run into the first day
+	showtimes, err := s.repo.Showtime.Find(ctx, ShowtimeFilter{
+		RoomIDs: roomIDs,
+		After:   input.StartDate.Add(-24 * time.Hour),
+	})
+	if err != nil {
+		return nil, err
+	}
+	existing := []Showtime{}
+	for _, showtime := range showtimes {
+		if showtime.StartAt.After(input.EndDate().Add(24 * time.Hour)) {
+			continue
+		}
+		existing = append(existing, showtime)
+	}
+
+	blackouts, err := s.repo.Blackout.Find(ctx, BlackoutFilter{
+		AffectRoomIDs: roomIDs,
+		After:         input.StartDate,
+		Before:        input.EndDate().Add(24 * time.Hour),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return PlanSchedule(input, movies, rooms, existing, blackouts), nil
+}
+
+func (s *PlannerService) Commit(ctx context.Context, input PlannerCommitInput) ([]Showtime, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	showtimes := make([]Showtime, 0, len(input.Showtimes))
+	for _, item := range input.Showtimes {
+		showtime, err := s.showtime.Create(ctx, item)
+		if err != nil {
+			return nil, err
+		}
+		showtimes = append(showtimes, *showtime)
+	}
+	return showtimes, nil
+}
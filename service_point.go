@@ -0,0 +1,328 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+func NewPointService(config *Config, repo *RepositoryRegistry) *PointService {
+	return &PointService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type PointService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *PointService) Summary(ctx context.Context, userID int64, page PaginateInput) (*PointSummary, error) {
+	lots, err := s.expire(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	debts, err := s.repo.Point.FindDebts(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var balance int64
+	for _, lot := range append(lots, debts...) {
+		balance += lot.Remaining
+	}
+
+	transactions, err := s.repo.Point.Pagination(ctx, PointTransactionFilter{UserIDs: []int64{userID}}, page)
+	if err != nil {
+		return nil, err
+	}
+	return &PointSummary{Balance: balance, Transactions: transactions}, nil
+}
+
+// Earn credit points for paid reservation as a new lot, points owed are repaid from it first
+func (s *PointService) Earn(ctx context.Context, reservation *Reservation) error {
+	points := EarnedPoints(reservation.TotalPrice, s.config.PointEarnUnit)
+	if points <= 0 {
+		return nil
+	}
+	expiresAt := time.Now().AddDate(0, 0, int(s.config.PointExpiryDays))
+	_, err := s.repo.Point.CreateTransaction(ctx, &PointTransaction{
+		UserID:        reservation.UserID,
+		ReservationID: reservation.ID,
+		Kind:          PointEarn,
+		Points:        points,
+		Remaining:     points,
+		ExpiresAt:     &expiresAt,
+		Description:   fmt.Sprintf("earned from reservation #%d", reservation.ID),
+	})
+	if err != nil {
+		return err
+	}
+	return s.repay(ctx, reservation.UserID)
+}
+
+// Redeem points as discount or free tickets on unpaid reservation of the user,
+// points redeemed previously on the reservation are given back first
+func (s *PointService) Redeem(ctx context.Context, userID, reservationID int64, input RedeemPointInput) (*Reservation, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{
+		IDs:       []int64{reservationID},
+		UserIDs:   []int64{userID},
+		WithItems: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != ReservationUnpaid {
+		return nil, NewErr(ErrInput, nil, "points can only redeemed on unpaid reservation")
+	}
+
+	err = s.restore(ctx, reservation)
+	if err != nil {
+		return nil, err
+	}
+
+	payable := reservation.SubtotalPrice - reservation.Discount
+	prices := make([]int64, 0, len(reservation.Items))
+	for _, item := range reservation.Items {
+		prices = append(prices, item.TotalPrice)
+	}
+	discount, err := FreeTicketDiscount(prices, input.FreeTickets)
+	if err != nil {
+		return nil, err
+	}
+	// free ticket cannot be more than what is left to pay after other discount
+	discount = min(discount, payable)
+	discount += input.Points * s.config.PointRedeemValue
+	if discount > payable {
+		return nil, NewErr(ErrInput, nil, "redeemed points exceed the price to pay")
+	}
+	points := input.Points + input.FreeTickets*s.config.PointFreeTicketCost
+
+	lots, err := s.expire(ctx, userID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	allocations, err := AllocatePoints(lots, points)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := s.repo.Point.CreateTransaction(ctx, &PointTransaction{
+		UserID:        userID,
+		ReservationID: reservation.ID,
+		Kind:          PointRedeem,
+		Points:        -points,
+		Description:   fmt.Sprintf("redeemed on reservation #%d", reservation.ID),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i, allocation := range allocations {
+		allocations[i].TransactionID = ID
+		err = s.repo.Point.AddRemaining(ctx, allocation.LotID, -allocation.Points)
+		if err != nil {
+			return nil, err
+		}
+	}
+	err = s.repo.Point.CreateAllocations(ctx, allocations)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Reservation.SetPoints(ctx, reservation.ID, points, discount)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{reservation.ID}, WithItems: true})
+}
+
+// Unredeem give back redeemed points of unpaid reservation of the user
+func (s *PointService) Unredeem(ctx context.Context, userID, reservationID int64) (*Reservation, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{
+		IDs:     []int64{reservationID},
+		UserIDs: []int64{userID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != ReservationUnpaid {
+		return nil, NewErr(ErrInput, nil, "points can only given back from unpaid reservation")
+	}
+
+	err = s.restore(ctx, reservation)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Reservation.SetPoints(ctx, reservation.ID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{reservation.ID}, WithItems: true})
+}
+
+// Reverse undo every points movement of a cancelled reservation,
+// redeemed points are given back and earned points are taken back.
+// Earned points already spent are owed as negative remaining of the lot,
+// repaid from other lots of the user and from future earning
+func (s *PointService) Reverse(ctx context.Context, reservation *Reservation) error {
+	err := s.restore(ctx, reservation)
+	if err != nil {
+		return err
+	}
+
+	earned, err := s.repo.Point.Find(ctx, PointTransactionFilter{
+		ReservationIDs: []int64{reservation.ID},
+		Kinds:          []string{string(PointEarn)},
+	})
+	if err != nil {
+		return err
+	}
+	for _, lot := range earned {
+		// expired points are already written off, only unspent and spent points are taken back
+		spent, err := s.repo.Point.AllocatedPoints(ctx, lot.ID)
+		if err != nil {
+			return err
+		}
+		points := lot.Remaining + spent
+		if points <= 0 {
+			continue
+		}
+		err = s.repo.Point.AddRemaining(ctx, lot.ID, -points)
+		if err != nil {
+			return err
+		}
+		_, err = s.repo.Point.CreateTransaction(ctx, &PointTransaction{
+			UserID:        lot.UserID,
+			ReservationID: reservation.ID,
+			Kind:          PointReverse,
+			Points:        -points,
+			Description:   fmt.Sprintf("earned points reversed, reservation #%d cancelled", reservation.ID),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return s.repay(ctx, reservation.UserID)
+}
+
+// repay move points from usable lots of the user to the lots owing points,
+// the ledger is unchanged, the debt is already recorded when the lot is reversed.
+// The move is allocated to the owing lot, so reversing the paying lot later take it back as spent
+func (s *PointService) repay(ctx context.Context, userID int64) error {
+	debts, err := s.repo.Point.FindDebts(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(debts) == 0 {
+		return nil
+	}
+	lots, err := s.expire(ctx, userID, time.Now())
+	if err != nil {
+		return err
+	}
+	var available int64
+	for _, lot := range lots {
+		available += lot.Remaining
+	}
+
+	for _, debt := range debts {
+		points := min(-debt.Remaining, available)
+		if points <= 0 {
+			break
+		}
+		allocations, err := AllocatePoints(lots, points)
+		if err != nil {
+			return err
+		}
+		for i, allocation := range allocations {
+			allocations[i].TransactionID = debt.ID
+			err = s.repo.Point.AddRemaining(ctx, allocation.LotID, -allocation.Points)
+			if err != nil {
+				return err
+			}
+			for j := range lots {
+				if lots[j].ID == allocation.LotID {
+					lots[j].Remaining -= allocation.Points
+				}
+			}
+		}
+		err = s.repo.Point.CreateAllocations(ctx, allocations)
+		if err != nil {
+			return err
+		}
+		err = s.repo.Point.AddRemaining(ctx, debt.ID, points)
+		if err != nil {
+			return err
+		}
+		available -= points
+	}
+	return nil
+}
+
+// restore give back lots consumed by the reservation, it is a no-op when nothing redeemed.
+// Points given back repay what the user owe first
+func (s *PointService) restore(ctx context.Context, reservation *Reservation) error {
+	allocations, err := s.repo.Point.DeleteAllocations(ctx, reservation.ID)
+	if err != nil {
+		return err
+	}
+	var points int64
+	for _, allocation := range allocations {
+		err = s.repo.Point.AddRemaining(ctx, allocation.LotID, allocation.Points)
+		if err != nil {
+			return err
+		}
+		points += allocation.Points
+	}
+	if points <= 0 {
+		return nil
+	}
+	_, err = s.repo.Point.CreateTransaction(ctx, &PointTransaction{
+		UserID:        reservation.UserID,
+		ReservationID: reservation.ID,
+		Kind:          PointReverse,
+		Points:        points,
+		Description:   fmt.Sprintf("redeemed points given back from reservation #%d", reservation.ID),
+	})
+	if err != nil {
+		return err
+	}
+	return s.repay(ctx, reservation.UserID)
+}
+
+// expire write off lots passed their expiry and return the lots still usable
+func (s *PointService) expire(ctx context.Context, userID int64, now time.Time) ([]PointTransaction, error) {
+	lots, err := s.repo.Point.FindLots(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	usable := []PointTransaction{}
+	for _, lot := range lots {
+		if lot.ExpiresAt == nil || lot.ExpiresAt.After(now) {
+			usable = append(usable, lot)
+			continue
+		}
+		err = s.repo.Point.AddRemaining(ctx, lot.ID, -lot.Remaining)
+		if err != nil {
+			return nil, err
+		}
+		_, err = s.repo.Point.CreateTransaction(ctx, &PointTransaction{
+			UserID:      userID,
+			Kind:        PointExpire,
+			Points:      -lot.Remaining,
+			Description: fmt.Sprintf("points earned at %s expired", lot.CreatedAt.Format(time.DateOnly)),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return usable, nil
+}
@@ -0,0 +1,78 @@
+package main
+
+import "context"
+
+func NewPricingRuleService(config *Config, repo *RepositoryRegistry) *PricingRuleService {
+	return &PricingRuleService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type PricingRuleService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *PricingRuleService) Create(ctx context.Context, input PricingRuleInput) (*PricingRule, error) {
+	newRule, err := NewPricingRule(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := s.repo.PricingRule.Create(ctx, newRule)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.PricingRule.FindOne(ctx, PricingRuleFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *PricingRuleService) UpdateByID(ctx context.Context, ID int64, input PricingRuleInput) (*PricingRule, error) {
+	_, err := s.repo.PricingRule.FindOne(ctx, PricingRuleFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.PricingRule.UpdateByID(ctx, ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	rule, err := s.repo.PricingRule.FindOne(ctx, PricingRuleFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	return rule, nil
+}
+
+func (s *PricingRuleService) GetByID(ctx context.Context, ID int64) (*PricingRule, error) {
+	return s.repo.PricingRule.FindOne(ctx, PricingRuleFilter{IDs: []int64{ID}})
+}
+
+func (s *PricingRuleService) DeleteByID(ctx context.Context, ID int64) error {
+	err := s.repo.PricingRule.DeleteByID(ctx, ID)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+func (s *PricingRuleService) Pagination(ctx context.Context, filter PricingRuleFilter, page PaginateInput) (*Paginate[PricingRule], error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.PricingRule.Pagination(ctx, filter, page)
+}
@@ -0,0 +1,101 @@
+package main
+
+import "context"
+
+func NewProfileService(config *Config, repo *RepositoryRegistry) *ProfileService {
+	return &ProfileService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type ProfileService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *ProfileService) Get(ctx context.Context, userID int64) (*UserProfile, error) {
+	return s.repo.Profile.FindByUserID(ctx, userID)
+}
+
+// Update replace the profile, every changed field is kept in the history
+func (s *ProfileService) Update(ctx context.Context, userID int64, input UserProfileInput) (*UserProfile, error) {
+	next, err := NewUserProfile(userID, input)
+	if err != nil {
+		return nil, err
+	}
+	if next.PreferredCinemaID != nil {
+		_, err = s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{*next.PreferredCinemaID}})
+		if err != nil {
+			if ErrIs(err, ErrNotFound) {
+				return nil, NewErr(ErrInput, err, "preferred cinema not found")
+			}
+			return nil, err
+		}
+	}
+
+	current, err := s.repo.Profile.FindByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	changes := current.Changes(next)
+	if len(changes) == 0 {
+		return current, nil
+	}
+
+	// consent time is when it was given, not when other field changed
+	if next.MarketingConsent {
+		next.MarketingConsentAt = current.MarketingConsentAt
+		if !current.MarketingConsent {
+			next.MarketingConsentAt = &next.UpdatedAt
+		}
+	}
+	err = s.repo.Profile.Save(ctx, next)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		_, err = s.repo.Profile.CreateChange(ctx, &change)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.repo.Profile.FindByUserID(ctx, userID)
+}
+
+func (s *ProfileService) History(ctx context.Context, userID int64, page PaginateInput) (*Paginate[UserProfileChange], error) {
+	return s.repo.Profile.PaginateChanges(ctx, userID, page)
+}
+
+// CheckAge refuse showtime of movie rated above the age of the user
+func (s *ProfileService) CheckAge(ctx context.Context, userID, showtimeID int64) error {
+	showtime, err := s.repo.Showtime.FindOne(ctx, ShowtimeFilter{IDs: []int64{showtimeID}})
+	if err != nil {
+		return err
+	}
+	movie, err := s.repo.Movie.FindOne(ctx, MovieFilter{IDs: []int64{showtime.MovieID}})
+	if err != nil {
+		return err
+	}
+	profile, err := s.repo.Profile.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	return profile.CheckAge(movie, showtime.StartAt)
+}
+
+// DefaultShowtimeFilter narrow showtime listing to the preferred cinema of the user,
+// unless the filter already choose the cinema, room or showtime, or ask for all cinemas
+func (s *ProfileService) DefaultShowtimeFilter(ctx context.Context, userID int64, filter *ShowtimeFilter) error {
+	if userID <= 0 || filter.AllCinemas || len(filter.CinemaIDs) > 0 || len(filter.RoomIDs) > 0 || len(filter.IDs) > 0 {
+		return nil
+	}
+	profile, err := s.repo.Profile.FindByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if profile.PreferredCinemaID != nil {
+		filter.CinemaIDs = []int64{*profile.PreferredCinemaID}
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+func NewPromoCodeService(config *Config, repo *RepositoryRegistry) *PromoCodeService {
+	return &PromoCodeService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type PromoCodeService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *PromoCodeService) Create(ctx context.Context, input PromoCodeInput) (*PromoCode, error) {
+	newPromoCode, err := NewPromoCode(input)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := s.repo.PromoCode.Create(ctx, newPromoCode)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.PromoCode.FindOne(ctx, PromoCodeFilter{IDs: []int64{ID}})
+}
+
+func (s *PromoCodeService) UpdateByID(ctx context.Context, ID int64, input PromoCodeInput) (*PromoCode, error) {
+	old, err := s.repo.PromoCode.FindOne(ctx, PromoCodeFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if input.UsageLimit > 0 && input.UsageLimit < old.UsedCount {
+		return nil, NewErr(ErrInput, nil, "usage limit cannot be less than used count %d", old.UsedCount)
+	}
+
+	err = s.repo.PromoCode.UpdateByID(ctx, ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.PromoCode.FindOne(ctx, PromoCodeFilter{IDs: []int64{ID}})
+}
+
+func (s *PromoCodeService) GetByID(ctx context.Context, ID int64) (*PromoCode, error) {
+	return s.repo.PromoCode.FindOne(ctx, PromoCodeFilter{IDs: []int64{ID}})
+}
+
+func (s *PromoCodeService) DeleteByID(ctx context.Context, ID int64) error {
+	promoCode, err := s.repo.PromoCode.FindOne(ctx, PromoCodeFilter{IDs: []int64{ID}})
+	if err != nil {
+		return err
+	}
+	if promoCode.UsedCount > 0 {
+		return NewErr(ErrInput, nil, "promo code already used, deactivate it instead")
+	}
+	return s.repo.PromoCode.DeleteByID(ctx, ID)
+}
+
+func (s *PromoCodeService) Pagination(ctx context.Context, filter PromoCodeFilter, page PaginateInput) (*Paginate[PromoCode], error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.PromoCode.Pagination(ctx, filter, page)
+}
+
+// Apply use the promo code on unpaid reservation of the user,
+// usage of previous promo code of the reservation is released first
+func (s *PromoCodeService) Apply(ctx context.Context, userID, reservationID int64, input ApplyPromoCodeInput) (*Reservation, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{
+		IDs:       []int64{reservationID},
+		UserIDs:   []int64{userID},
+		WithItems: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != ReservationUnpaid {
+		return nil, NewErr(ErrInput, nil, "promo code can only applied to unpaid reservation")
+	}
+
+	promoCode, err := s.repo.PromoCode.FindOne(ctx, PromoCodeFilter{Codes: []string{input.Code}})
+	if err != nil {
+		return nil, err
+	}
+	err = promoCode.ValidateAt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	lines, err := s.lines(ctx, reservation.Items)
+	if err != nil {
+		return nil, err
+	}
+	discount, err := promoCode.Discount(reservation.Currency, reservation.SubtotalPrice, lines)
+	if err != nil {
+		return nil, err
+	}
+	// redeemed points already reduce the price to pay
+	discount = min(discount, reservation.SubtotalPrice-reservation.PointsDiscount)
+
+	err = s.repo.PromoCode.ReleaseUsage(ctx, reservation.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// lock the promo code row before the usage of the user is counted,
+	// so concurrent request of the same user wait for each other usage
+	err = s.repo.PromoCode.IncrementUsage(ctx, promoCode.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.PromoCode.CreateUsage(ctx, PromoCodeUsage{
+		PromoCodeID:   promoCode.ID,
+		UserID:        userID,
+		ReservationID: reservation.ID,
+		Discount:      discount,
+	}, promoCode.PerUserLimit)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Reservation.SetPromoCode(ctx, reservation.ID, promoCode.ID, discount)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{reservation.ID}, WithItems: true})
+}
+
+// Remove the promo code from unpaid reservation of the user
+func (s *PromoCodeService) Remove(ctx context.Context, userID, reservationID int64) (*Reservation, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{
+		IDs:     []int64{reservationID},
+		UserIDs: []int64{userID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != ReservationUnpaid {
+		return nil, NewErr(ErrInput, nil, "promo code can only removed from unpaid reservation")
+	}
+
+	err = s.repo.PromoCode.ReleaseUsage(ctx, reservation.ID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Reservation.SetPromoCode(ctx, reservation.ID, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{reservation.ID}, WithItems: true})
+}
+
+func (s *PromoCodeService) lines(ctx context.Context, items []ReservationItem) ([]PromoLine, error) {
+	showtimeIDs := []int64{}
+	seatIDs := []int64{}
+	for _, item := range items {
+		showtimeIDs = append(showtimeIDs, item.ShowtimeID)
+		seatIDs = append(seatIDs, item.SeatID)
+	}
+
+	showtimes, err := s.repo.Showtime.Find(ctx, ShowtimeFilter{IDs: showtimeIDs})
+	if err != nil {
+		return nil, err
+	}
+	showtimeMovie := map[int64]int64{}
+	movieIDs := []int64{}
+	for _, showtime := range showtimes {
+		showtimeMovie[showtime.ID] = showtime.MovieID
+		movieIDs = append(movieIDs, showtime.MovieID)
+	}
+
+	movies, err := s.repo.Movie.Find(ctx, MovieFilter{IDs: movieIDs})
+	if err != nil {
+		return nil, err
+	}
+	movieGenres := map[int64][]int64{}
+	for _, movie := range movies {
+		movieGenres[movie.ID] = movie.GenreIDs
+	}
+
+	seats, err := s.repo.Room.FilterSeats(ctx, SeatFilter{IDs: seatIDs})
+	if err != nil {
+		return nil, err
+	}
+	seatCategory := map[int64]string{}
+	for _, seat := range seats {
+		seatCategory[seat.ID] = seat.Category
+	}
+
+	lines := make([]PromoLine, 0, len(items))
+	for _, item := range items {
+		movieID := showtimeMovie[item.ShowtimeID]
+		lines = append(lines, PromoLine{
+			MovieID:      movieID,
+			GenreIDs:     movieGenres[movieID],
+			ShowtimeID:   item.ShowtimeID,
+			SeatCategory: seatCategory[item.SeatID],
+			Price:        item.TotalPrice,
+		})
+	}
+	return lines, nil
+}
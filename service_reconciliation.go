@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+func NewReconciliationService(config *Config, repo *RepositoryRegistry) *ReconciliationService {
+	return &ReconciliationService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type ReconciliationService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+// Run compare every reservation status with its ledger and store the disagreement found
+func (s *ReconciliationService) Run(ctx context.Context, now time.Time) ([]ReconciliationIssue, error) {
+	ledgers, err := s.repo.Reconciliation.ReservationLedgers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	detectedOn := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	issues := []ReconciliationIssue{}
+	for _, ledger := range ledgers {
+		for _, issue := range ledger.Issues() {
+			issues = append(issues, ReconciliationIssue{
+				ReservationID: ledger.ReservationID,
+				Status:        ledger.Status,
+				Issue:         issue,
+				TotalPrice:    ledger.TotalPrice,
+				Sold:          ledger.Sold,
+				Collected:     ledger.Collected,
+				Refunded:      ledger.Refunded,
+				DetectedOn:    detectedOn,
+			})
+		}
+	}
+
+	err = s.repo.Reconciliation.CreateIssues(ctx, issues)
+	if err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func (s *ReconciliationService) Pagination(ctx context.Context, filter ReconciliationIssueFilter, page PaginateInput) (*Paginate[ReconciliationIssue], error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Reconciliation.Pagination(ctx, filter, page)
+}
+
+// RunDailyReconciliation reconcile the ledger once on start then every day until the context is done
+func RunDailyReconciliation(ctx context.Context, trxProvider *TransactionProvider) {
+	ticker := time.NewTicker(24 * time.Hour)
+	defer ticker.Stop()
+
+	for {
+		err := trxProvider.Transact(ctx, func(service *ServiceRegistry) error {
+			issues, err := service.Reconciliation.Run(ctx, time.Now())
+			if err != nil {
+				return err
+			}
+			if len(issues) > 0 {
+				log.Printf("reconciliation found %d issue(s)", len(issues))
+			}
+			return nil
+		})
+		if err != nil {
+			log.Printf("reconciliation failed: %s", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
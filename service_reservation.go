@@ -7,14 +7,20 @@ import (
 
 func NewReservationService(config *Config, repo *RepositoryRegistry) *ReservationService {
 	return &ReservationService{
-		config: config,
-		repo:   repo,
+		config:  config,
+		repo:    repo,
+		point:   NewPointService(config, repo),
+		wallet:  NewWalletService(config, repo),
+		profile: NewProfileService(config, repo),
 	}
 }
 
 type ReservationService struct {
-	config *Config
-	repo   *RepositoryRegistry
+	config  *Config
+	repo    *RepositoryRegistry
+	point   *PointService
+	wallet  *WalletService
+	profile *ProfileService
 }
 
 func (s *ReservationService) Create(ctx context.Context, input ReservationInput) (*Reservation, error) {
@@ -24,6 +30,16 @@ func (s *ReservationService) Create(ctx context.Context, input ReservationInput)
 		return nil, err
 	}
 
+	if s.config.RequireVerifiedEmail {
+		user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{input.UserID}})
+		if err != nil {
+			return nil, err
+		}
+		if !user.IsEmailVerified() {
+			return nil, NewErr(ErrInput, nil, "verify your email before making reservation")
+		}
+	}
+
 	carts, err := s.repo.Cart.Find(ctx, CartFilter{IDs: input.CartIDs, UserIDs: []int64{input.UserID}})
 	if err != nil {
 		return nil, err
@@ -32,21 +48,56 @@ func (s *ReservationService) Create(ctx context.Context, input ReservationInput)
 		return nil, NewErr(ErrInput, nil, "no cart exists based on input")
 	}
 
-	var totalPrice int64
+	var showtimeID int64
+	totalPrice := NewMoney(0, carts[0].Currency)
 	showtimeSet := map[int64]struct{}{}
 	for _, cart := range carts {
+		showtimeID = cart.ShowtimeID
 		showtimeSet[cart.ShowtimeID] = struct{}{}
 		if len(showtimeSet) > 1 {
 			return nil, NewErr(ErrInput, nil, "reservation can only created on cart with same showtime")
 		}
-		totalPrice += cart.Price
+		totalPrice, err = totalPrice.Add(NewMoney(cart.Price, cart.Currency))
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = s.profile.CheckAge(ctx, input.UserID, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+
+	ticketTypes, err := s.repo.TicketType.Find(ctx, TicketTypeFilter{})
+	if err != nil {
+		return nil, err
+	}
+	ticketTypeMap := map[int64]TicketType{}
+	for _, ticketType := range ticketTypes {
+		ticketTypeMap[ticketType.ID] = ticketType
+	}
+	chosen := make([]TicketType, 0, len(carts))
+	for _, cart := range carts {
+		if ticketType, ok := ticketTypeMap[cart.TicketTypeID]; ok {
+			chosen = append(chosen, ticketType)
+		}
+	}
+	err = ValidateTicketTypeEligibility(chosen, ticketTypes)
+	if err != nil {
+		return nil, err
 	}
 
 	newReservation, err := NewReservation(input)
 	if err != nil {
 		return nil, err
 	}
-	newReservation.TotalPrice = totalPrice
+	newReservation.TotalPrice = totalPrice.Amount
+	newReservation.Currency = totalPrice.Currency
+
+	err = s.applyFeeAndTax(ctx, newReservation, showtimeID, int64(len(carts)))
+	if err != nil {
+		return nil, err
+	}
 
 	ID, err := s.repo.Reservation.Create(ctx, newReservation)
 	if err != nil {
@@ -65,6 +116,8 @@ func (s *ReservationService) Create(ctx context.Context, input ReservationInput)
 			ShowtimeID:    cart.ShowtimeID,
 			SeatID:        cart.SeatID,
 			TotalPrice:    cart.Price,
+			TicketTypeID:  cart.TicketTypeID,
+			Pricing:       cart.Pricing,
 		})
 		if err != nil {
 			return nil, err
@@ -83,18 +136,39 @@ func (s *ReservationService) Create(ctx context.Context, input ReservationInput)
 	return reservation, nil
 }
 
-func (s *ReservationService) Pay(ctx context.Context, userID, ID int64) (*Reservation, error) {
+// StartPay record the pending gateway charge of unpaid reservation, see WalletService.StartPay
+func (s *ReservationService) StartPay(ctx context.Context, userID, ID int64, input PayReservationInput) (*Payment, error) {
+	reservation, err := s.findUnpaid(ctx, userID, ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.wallet.StartPay(ctx, reservation, input)
+}
+
+// Pay mark the reservation paid with the gateway charge of StartPay
+func (s *ReservationService) Pay(ctx context.Context, userID, ID int64, input PayReservationInput, charged *PaymentReceipt) (*Reservation, error) {
 
-	old, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{
-		IDs:      []int64{ID},
-		UserIDs:  []int64{userID},
-		Statuses: []string{string(ReservationUnpaid)},
-	})
+	err := s.repo.Reservation.LockUnpaid(ctx, ID)
 	if err != nil {
 		return nil, err
 	}
-	if old == nil {
-		return nil, NewErr(ErrInput, nil, "reservation not found")
+
+	old, err := s.findUnpaid(ctx, userID, ID)
+	if err != nil {
+		return nil, err
+	}
+
+	sale := SaleJournal(old)
+	if len(sale.Entries) > 0 {
+		_, err = s.repo.Ledger.CreateJournal(ctx, sale)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	receipt, err := s.wallet.Pay(ctx, old, input, charged)
+	if err != nil {
+		return nil, err
 	}
 
 	err = s.repo.Reservation.UpdateByID(ctx, ID, ReservationInput{
@@ -106,15 +180,47 @@ func (s *ReservationService) Pay(ctx context.Context, userID, ID int64) (*Reserv
 		return nil, err
 	}
 
+	err = s.repo.Reservation.SetPayment(ctx, ID, input.WalletAmount, old.TotalPrice-input.WalletAmount, receipt.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.Reservation.IssueReceipt(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+
 	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{ID}})
 	if err != nil {
 		return nil, err
 	}
 
+	err = s.point.Earn(ctx, reservation)
+	if err != nil {
+		return nil, err
+	}
+
 	return reservation, nil
 }
 
-func (s *ReservationService) Cancel(ctx context.Context, userID, ID int64) (*Reservation, error) {
+func (s *ReservationService) findUnpaid(ctx context.Context, userID, ID int64) (*Reservation, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{
+		IDs:      []int64{ID},
+		UserIDs:  []int64{userID},
+		Statuses: []string{string(ReservationUnpaid)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if reservation == nil {
+		return nil, NewErr(ErrInput, nil, "reservation not found")
+	}
+	return reservation, nil
+}
+
+// Cancel give back the money of paid reservation, the gateway part is returned as pending refund
+// to be sent after the transaction is committed
+func (s *ReservationService) Cancel(ctx context.Context, userID, ID int64) (*Reservation, *Payment, error) {
 
 	old, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{
 		IDs:       []int64{ID},
@@ -122,7 +228,10 @@ func (s *ReservationService) Cancel(ctx context.Context, userID, ID int64) (*Res
 		WithItems: true,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if old.Status == ReservationCancelled {
+		return nil, nil, NewErr(ErrInput, nil, "reservation already cancelled")
 	}
 
 	var minTime time.Time
@@ -138,7 +247,7 @@ func (s *ReservationService) Cancel(ctx context.Context, userID, ID int64) (*Res
 	diff := minTime.Sub(time.Now())
 	minimumTimeCancel := 6 * time.Hour
 	if diff < minimumTimeCancel {
-		return nil, NewErr(ErrInput, nil, "cannot cancel reservation below 6 hour showtime")
+		return nil, nil, NewErr(ErrInput, nil, "cannot cancel reservation below 6 hour showtime")
 	}
 
 	err = s.repo.Reservation.UpdateByID(ctx, ID, ReservationInput{
@@ -147,19 +256,58 @@ func (s *ReservationService) Cancel(ctx context.Context, userID, ID int64) (*Res
 		TotalPrice: old.TotalPrice,
 	})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	// discount is kept as record, only the promo code usage is given back
+	err = s.repo.PromoCode.ReleaseUsage(ctx, ID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = s.point.Reverse(ctx, old)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var refund *Payment
+	if old.Status == ReservationPaid {
+		refund, err = s.wallet.Refund(ctx, old)
+		if err != nil {
+			return nil, nil, err
+		}
+		reversal := SaleReversalJournal(old)
+		if len(reversal.Entries) > 0 {
+			_, err = s.repo.Ledger.CreateJournal(ctx, reversal)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
 	}
 
 	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{ID}})
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
-	return reservation, nil
+	return reservation, refund, nil
 }
 
 func (s *ReservationService) UserDeleteByID(ctx context.Context, userID, ID int64) error {
-	_, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{ID}, UserIDs: []int64{userID}})
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{ID}, UserIDs: []int64{userID}})
+	if err != nil {
+		return err
+	}
+	if reservation.Status == ReservationPaid {
+		return NewErr(ErrInput, nil, "paid reservation cannot be deleted, cancel it instead")
+	}
+
+	err = s.repo.PromoCode.ReleaseUsage(ctx, ID)
+	if err != nil {
+		return err
+	}
+
+	err = s.point.Reverse(ctx, reservation)
 	if err != nil {
 		return err
 	}
@@ -175,6 +323,26 @@ func (s *ReservationService) UserGetByID(ctx context.Context, userID, ID int64)
 	return s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{ID}, UserIDs: []int64{userID}, WithItems: true})
 }
 
+// Receipt of paid reservation of the user, cancelled reservation keep its receipt as record
+func (s *ReservationService) Receipt(ctx context.Context, userID, ID int64) (*Receipt, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{ID}, UserIDs: []int64{userID}, WithItems: true})
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	cinema := &Cinema{}
+	if reservation.CinemaID > 0 {
+		cinema, err = s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{reservation.CinemaID}})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return NewReceipt(reservation, user, cinema)
+}
+
 func (s *ReservationService) Pagination(ctx context.Context, filter ReservationFilter, page PaginateInput) (*Paginate[Reservation], error) {
 	err := filter.Validate()
 	if err != nil {
@@ -182,3 +350,31 @@ func (s *ReservationService) Pagination(ctx context.Context, filter ReservationF
 	}
 	return s.repo.Reservation.Pagination(ctx, filter, page)
 }
+
+// applyFeeAndTax charge the convenience fees and snapshot the tax rate of the cinema the showtime is in
+func (s *ReservationService) applyFeeAndTax(ctx context.Context, reservation *Reservation, showtimeID, tickets int64) error {
+	showtime, err := s.repo.Showtime.FindOne(ctx, ShowtimeFilter{IDs: []int64{showtimeID}})
+	if err != nil {
+		return err
+	}
+	room, err := s.repo.Room.FindOne(ctx, RoomFilter{IDs: []int64{showtime.RoomID}})
+	if err != nil {
+		return err
+	}
+	if room.CinemaID > 0 {
+		cinema, err := s.repo.Cinema.FindOne(ctx, CinemaFilter{IDs: []int64{room.CinemaID}})
+		if err != nil {
+			return err
+		}
+		reservation.CinemaID = cinema.ID
+		reservation.TaxRate = cinema.TaxRate
+	}
+
+	active := true
+	fees, err := s.repo.Fee.Find(ctx, FeeFilter{IsActive: &active})
+	if err != nil {
+		return err
+	}
+	reservation.FeeItems, reservation.Fees, err = ApplyFees(fees, reservation.CinemaID, reservation.Currency, tickets)
+	return err
+}
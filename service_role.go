@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"slices"
+)
+
+func NewRoleService(config *Config, repo *RepositoryRegistry) *RoleService {
+	return &RoleService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type RoleService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *RoleService) Permissions(ctx context.Context) ([]PermissionInfo, error) {
+	return s.repo.Role.FindPermissions(ctx)
+}
+
+// PermissionsOfRole is the permission granted to the role name carried by the token
+func (s *RoleService) PermissionsOfRole(ctx context.Context, name string) ([]string, error) {
+	return s.repo.Role.FindPermissionsByRoleName(ctx, name)
+}
+
+func (s *RoleService) Create(ctx context.Context, input RoleInput) (*Role, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = s.validatePermissions(ctx, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	ID, err := s.repo.Role.Create(ctx, input.Name)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Role.SetPermissions(ctx, ID, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.FindRoleOne(ctx, RoleFilter{IDs: []int64{ID}})
+}
+
+func (s *RoleService) SetPermissions(ctx context.Context, ID int64, input RolePermissionInput) (*Role, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	role, err := s.repo.User.FindRoleOne(ctx, RoleFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == UserAdmin {
+		return nil, NewErr(ErrInput, nil, "admin role always has every permission")
+	}
+	err = s.validatePermissions(ctx, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Role.SetPermissions(ctx, ID, input.Permissions)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.FindRoleOne(ctx, RoleFilter{IDs: []int64{ID}})
+}
+
+// DeleteByID remove a role nobody has, users would be deleted with the role otherwise
+func (s *RoleService) DeleteByID(ctx context.Context, ID int64) (*Role, error) {
+	role, err := s.repo.User.FindRoleOne(ctx, RoleFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+	if isBuiltinRole(role.Name) {
+		return nil, NewErr(ErrInput, nil, "role %s cannot be deleted", role.Name)
+	}
+	users, err := s.repo.Role.CountUsers(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	if users > 0 {
+		return nil, NewErr(ErrInput, nil, "role is assigned to %d users, change their role first", users)
+	}
+	err = s.repo.Role.DeleteByID(ctx, ID)
+	if err != nil {
+		return nil, err
+	}
+	return role, nil
+}
+
+func (s *RoleService) validatePermissions(ctx context.Context, permissions []string) error {
+	existing, err := s.repo.Role.FindPermissions(ctx)
+	if err != nil {
+		return err
+	}
+	for i, permission := range permissions {
+		found := slices.ContainsFunc(existing, func(p PermissionInfo) bool { return p.Name == permission })
+		if !found {
+			return NewErr(ErrInput, nil, "permission index %d = %s invalid", i, permission)
+		}
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package main
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 func NewRoomService(config *Config, repo *RepositoryRegistry) *RoomService {
 	return &RoomService{
@@ -108,3 +111,52 @@ func (s *RoomService) SetSeats(ctx context.Context, roomID int64, input []SeatIn
 func (r *RoomService) ListSeats(ctx context.Context, roomID int64) ([]Seat, error) {
 	return r.repo.Room.FilterSeats(ctx, SeatFilter{RoomIDs: []int64{roomID}})
 }
+
+// GetSchedule list showtimes and blackouts of the room within the time range
+func (s *RoomService) GetSchedule(ctx context.Context, roomID int64, input RoomScheduleInput) (*RoomSchedule, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	room, err := s.repo.Room.FindOne(ctx, RoomFilter{IDs: []int64{roomID}})
+	if err != nil {
+		return nil, err
+	}
+
+	// showtime started a day before still may run into the range
+	cur, err := s.repo.Showtime.Find(ctx, ShowtimeFilter{
+		RoomIDs: []int64{roomID},
+		After:   input.From.Add(-24 * time.Hour),
+	})
+	if err != nil {
+		return nil, err
+	}
+	showtimes := []Showtime{}
+	for _, showtime := range cur {
+		if isTimeOverlapping(showtime.StartAt, showtime.EndAt, input.From, input.To) {
+			showtimes = append(showtimes, showtime)
+		}
+	}
+
+	blackouts, err := s.repo.Blackout.Find(ctx, BlackoutFilter{
+		AffectRoomIDs: []int64{roomID},
+		After:         input.From,
+		Before:        input.To,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if blackouts == nil {
+		blackouts = []Blackout{}
+	}
+
+	schedule := RoomSchedule{
+		Room:      *room,
+		From:      input.From,
+		To:        input.To,
+		Showtimes: showtimes,
+		Blackouts: blackouts,
+	}
+	return &schedule, nil
+}
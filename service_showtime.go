@@ -6,14 +6,16 @@ import (
 
 func NewShowtimeService(config *Config, repo *RepositoryRegistry) *ShowtimeService {
 	return &ShowtimeService{
-		config: config,
-		repo:   repo,
+		config:   config,
+		repo:     repo,
+		blackout: NewBlackoutService(config, repo),
 	}
 }
 
 type ShowtimeService struct {
-	config *Config
-	repo   *RepositoryRegistry
+	config   *Config
+	repo     *RepositoryRegistry
+	blackout *BlackoutService
 }
 
 func (s *ShowtimeService) Create(ctx context.Context, input ShowtimeInput) (*Showtime, error) {
@@ -28,6 +30,9 @@ func (s *ShowtimeService) Create(ctx context.Context, input ShowtimeInput) (*Sho
 	if err != nil {
 		return nil, err
 	}
+	if input.AudioLanguage == "" {
+		input.AudioLanguage = movie.OriginalLanguage
+	}
 
 	cur, err := s.repo.Showtime.Find(ctx, ShowtimeFilter{
 		RoomIDs: []int64{input.RoomID},
@@ -43,6 +48,11 @@ func (s *ShowtimeService) Create(ctx context.Context, input ShowtimeInput) (*Sho
 		}
 	}
 
+	err = s.blackout.ValidateRoomAvailable(ctx, input.RoomID, input.StartAt, input.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
 	newShowtime, err := NewShowtime(input)
 	if err != nil {
 		return nil, err
@@ -77,6 +87,9 @@ func (s *ShowtimeService) UpdateByID(ctx context.Context, ID int64, input Showti
 	if err != nil {
 		return nil, err
 	}
+	if input.AudioLanguage == "" {
+		input.AudioLanguage = movie.OriginalLanguage
+	}
 
 	cur, err := s.repo.Showtime.Find(ctx, ShowtimeFilter{
 		RoomIDs: []int64{input.RoomID},
@@ -95,6 +108,11 @@ func (s *ShowtimeService) UpdateByID(ctx context.Context, ID int64, input Showti
 		}
 	}
 
+	err = s.blackout.ValidateRoomAvailable(ctx, input.RoomID, input.StartAt, input.EndAt)
+	if err != nil {
+		return nil, err
+	}
+
 	err = s.repo.Showtime.UpdateByID(ctx, ID, input)
 	if err != nil {
 		return nil, err
@@ -128,6 +146,26 @@ func (s *ShowtimeService) Pagination(ctx context.Context, filter ShowtimeFilter,
 	return s.repo.Showtime.Pagination(ctx, filter, page)
 }
 
+func (s *ShowtimeService) ListFormatSurcharges(ctx context.Context) ([]FormatSurcharge, error) {
+	return s.repo.Showtime.FindFormatSurcharges(ctx)
+}
+
+func (s *ShowtimeService) SetFormatSurcharge(ctx context.Context, format ShowtimeFormat, input FormatSurchargeInput) ([]FormatSurcharge, error) {
+	err := format.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Showtime.SetFormatSurcharge(ctx, format, input)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Showtime.FindFormatSurcharges(ctx)
+}
+
 func (s *ShowtimeService) GetShowtimeSeats(ctx context.Context, showtimeID int64) ([]Seat, error) {
 	return s.repo.Showtime.GetShowtimeSeats(ctx, showtimeID)
 }
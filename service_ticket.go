@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"slices"
+	"time"
+)
+
+func NewTicketService(config *Config, repo *RepositoryRegistry) *TicketService {
+	return &TicketService{
+		config:      config,
+		repo:        repo,
+		reservation: NewReservationService(config, repo),
+	}
+}
+
+type TicketService struct {
+	config      *Config
+	repo        *RepositoryRegistry
+	reservation *ReservationService
+}
+
+func (s *TicketService) List(ctx context.Context, userID, reservationID int64) ([]Ticket, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{reservationID}, UserIDs: []int64{userID}, WithItems: true})
+	if err != nil {
+		return nil, err
+	}
+	return NewTickets(s.config.TicketSecret, reservation)
+}
+
+// QRCode render the ticket of one reserved seat as png or svg
+func (s *TicketService) QRCode(ctx context.Context, userID, reservationID, itemID int64, format TicketQRFormat) ([]byte, error) {
+	tickets, err := s.List(ctx, userID, reservationID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ticket := range tickets {
+		if ticket.ReservationItemID != itemID {
+			continue
+		}
+		switch format {
+		case TicketQRPNG, "":
+			return TicketQRCodePNG(ticket.Token, 512)
+		case TicketQRSVG:
+			return TicketQRCodeSVG(ticket.Token)
+		default:
+			return nil, NewErr(ErrInput, nil, "qr code format %s is not supported", format)
+		}
+	}
+	return nil, NewErr(ErrNotFound, nil, "ticket not found")
+}
+
+// UserPDF render printable tickets of the reservation owned by the user
+func (s *TicketService) UserPDF(ctx context.Context, userID, reservationID int64) ([]byte, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{reservationID}, UserIDs: []int64{userID}, WithItems: true})
+	if err != nil {
+		return nil, err
+	}
+	return s.pdf(ctx, reservation, false)
+}
+
+// AdminPDF reprint the tickets of any reservation, e.g. at the box office
+func (s *TicketService) AdminPDF(ctx context.Context, reservationID int64) ([]byte, error) {
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{reservationID}, WithItems: true})
+	if err != nil {
+		return nil, err
+	}
+	return s.pdf(ctx, reservation, true)
+}
+
+func (s *TicketService) pdf(ctx context.Context, reservation *Reservation, reprint bool) ([]byte, error) {
+	tickets, err := NewTickets(s.config.TicketSecret, reservation)
+	if err != nil {
+		return nil, err
+	}
+	receipt, err := s.reservation.Receipt(ctx, reservation.UserID, reservation.ID)
+	if err != nil {
+		return nil, err
+	}
+	return RenderTicketsPDF(tickets, receipt, reprint)
+}
+
+// Verify check the token is signed by us and the ticket still admits, i.e. the reservation is paid
+// and the seat is still part of it
+func (s *TicketService) Verify(ctx context.Context, token string) (*Ticket, error) {
+	claims, err := ParseTicketToken(s.config.TicketSecret, token)
+	if err != nil {
+		return nil, err
+	}
+	reservation, err := s.repo.Reservation.FindOne(ctx, ReservationFilter{IDs: []int64{claims.ReservationID}, WithItems: true})
+	if err != nil {
+		return nil, err
+	}
+	if reservation.Status != ReservationPaid {
+		return nil, NewErr(ErrInput, nil, "ticket is void, reservation is %s", reservation.Status)
+	}
+	for _, item := range reservation.Items {
+		if item.ID != claims.ReservationItemID {
+			continue
+		}
+		if item.ShowtimeID != claims.ShowtimeID || item.SeatID != claims.SeatID {
+			return nil, NewErr(ErrInput, nil, "ticket does not match the reservation")
+		}
+		ticket := Ticket{
+			ReservationID:     reservation.ID,
+			ReservationItemID: item.ID,
+			ShowtimeID:        item.ShowtimeID,
+			SeatID:            item.SeatID,
+			Movie:             item.Movie,
+			Room:              item.Room,
+			Seat:              item.Seat,
+			ShowtimeStart:     item.ShowtimeStart,
+			ShowtimeEnd:       item.ShowtimeEnd,
+			Token:             token,
+		}
+		return &ticket, nil
+	}
+	return nil, NewErr(ErrInput, nil, "ticket does not match the reservation")
+}
+
+// CheckIn admit the ticket holder at the door of the showtime, a ticket is admitted only once
+func (s *TicketService) CheckIn(ctx context.Context, staffID int64, input CheckInInput) (*Admission, error) {
+	admission, first, err := s.admit(ctx, staffID, input)
+	if err != nil {
+		return nil, err
+	}
+	if first != nil {
+		return nil, ErrAlreadyAdmitted(first)
+	}
+	return admission, nil
+}
+
+// SyncCheckIn admit scans collected by scanner while offline, the earliest scan of a ticket wins
+// and one rejected scan does not stop the others
+func (s *TicketService) SyncCheckIn(ctx context.Context, staffID int64, input CheckInSyncInput) ([]CheckInResult, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	scans := slices.Clone(input.Scans)
+	now := time.Now()
+	for i := range scans {
+		if scans[i].ScannedAt.IsZero() || scans[i].ScannedAt.After(now) {
+			scans[i].ScannedAt = now
+		}
+	}
+	slices.SortStableFunc(scans, func(a, b CheckInInput) int {
+		return a.ScannedAt.Compare(b.ScannedAt)
+	})
+
+	results := make([]CheckInResult, 0, len(scans))
+	for _, scan := range scans {
+		result := CheckInResult{Token: scan.Token}
+		admission, first, err := s.admit(ctx, staffID, scan)
+		switch {
+		case ErrIs(err, ErrInput) || ErrIs(err, ErrNotFound):
+			result.Status = CheckInRejected
+			result.Reason = err.Error()
+		case err != nil:
+			return nil, err
+		case first != nil:
+			result.Status = CheckInDuplicate
+			result.Reason = ErrAlreadyAdmitted(first).Error()
+			result.FirstScannedAt = &first.ScannedAt
+		default:
+			result.Status = CheckInAdmitted
+			result.Admission = admission
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func (s *TicketService) Attendance(ctx context.Context, showtimeID int64) (*Attendance, error) {
+	showtime, err := s.repo.Showtime.FindOne(ctx, ShowtimeFilter{IDs: []int64{showtimeID}})
+	if err != nil {
+		return nil, err
+	}
+	sold, admitted, err := s.repo.Admission.Attendance(ctx, showtimeID)
+	if err != nil {
+		return nil, err
+	}
+	return NewAttendance(showtime, sold, admitted, time.Now()), nil
+}
+
+// admit record the admission of valid ticket, the first admission is returned instead when already admitted
+func (s *TicketService) admit(ctx context.Context, staffID int64, input CheckInInput) (admission, first *Admission, err error) {
+	err = input.Validate()
+	if err != nil {
+		return nil, nil, err
+	}
+	ticket, err := s.Verify(ctx, input.Token)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ticket.ShowtimeID != input.ShowtimeID {
+		return nil, nil, NewErr(ErrInput, nil, "ticket is for another showtime, %s at %s", ticket.Movie, ticket.ShowtimeStart.Format(time.DateTime))
+	}
+	if input.RoomID > 0 {
+		showtime, err := s.repo.Showtime.FindOne(ctx, ShowtimeFilter{IDs: []int64{ticket.ShowtimeID}})
+		if err != nil {
+			return nil, nil, err
+		}
+		if showtime.RoomID != input.RoomID {
+			return nil, nil, NewErr(ErrInput, nil, "ticket is for another room, %s", ticket.Room)
+		}
+	}
+
+	newAdmission := NewAdmission(ticket, input, staffID)
+	ID, err := s.repo.Admission.Create(ctx, newAdmission)
+	if err != nil {
+		return nil, nil, err
+	}
+	if ID == 0 {
+		first, err = s.repo.Admission.FindOne(ctx, AdmissionFilter{ReservationItemIDs: []int64{ticket.ReservationItemID}})
+		if err != nil {
+			return nil, nil, err
+		}
+		return nil, first, nil
+	}
+
+	admission, err = s.repo.Admission.FindOne(ctx, AdmissionFilter{ReservationItemIDs: []int64{ticket.ReservationItemID}})
+	if err != nil {
+		return nil, nil, err
+	}
+	admission.Movie, admission.Room, admission.Seat = ticket.Movie, ticket.Room, ticket.Seat
+	return admission, nil, nil
+}
@@ -0,0 +1,125 @@
+package main
+
+import "context"
+
+func NewTicketTypeService(config *Config, repo *RepositoryRegistry) *TicketTypeService {
+	return &TicketTypeService{
+		config: config,
+		repo:   repo,
+	}
+}
+
+type TicketTypeService struct {
+	config *Config
+	repo   *RepositoryRegistry
+}
+
+func (s *TicketTypeService) Create(ctx context.Context, input TicketTypeInput) (*TicketType, error) {
+	newTicketType, err := NewTicketType(input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.prepare(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := s.repo.TicketType.Create(ctx, newTicketType)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.TicketType.FindOne(ctx, TicketTypeFilter{IDs: []int64{ID}})
+}
+
+func (s *TicketTypeService) UpdateByID(ctx context.Context, ID int64, input TicketTypeInput) (*TicketType, error) {
+	old, err := s.repo.TicketType.FindOne(ctx, TicketTypeFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+
+	err = input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if old.IsDefault && !input.IsDefault {
+		return nil, NewErr(ErrInput, nil, "set another ticket type as default instead")
+	}
+	if input.RequireTicketTypeID == ID {
+		return nil, NewErr(ErrInput, nil, "ticket type cannot require itself")
+	}
+
+	err = s.prepare(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.TicketType.UpdateByID(ctx, ID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.TicketType.FindOne(ctx, TicketTypeFilter{IDs: []int64{ID}})
+}
+
+func (s *TicketTypeService) GetByID(ctx context.Context, ID int64) (*TicketType, error) {
+	return s.repo.TicketType.FindOne(ctx, TicketTypeFilter{IDs: []int64{ID}})
+}
+
+func (s *TicketTypeService) DeleteByID(ctx context.Context, ID int64) error {
+	ticketType, err := s.repo.TicketType.FindOne(ctx, TicketTypeFilter{IDs: []int64{ID}})
+	if err != nil {
+		return err
+	}
+	if ticketType.IsDefault {
+		return NewErr(ErrInput, nil, "default ticket type cannot be deleted")
+	}
+	return s.repo.TicketType.DeleteByID(ctx, ID)
+}
+
+func (s *TicketTypeService) List(ctx context.Context, filter TicketTypeFilter) ([]TicketType, error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	ticketTypes, err := s.repo.TicketType.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if ticketTypes == nil {
+		ticketTypes = []TicketType{}
+	}
+	return ticketTypes, nil
+}
+
+// Resolve return the chosen ticket type, or the default one when not chosen
+func (s *TicketTypeService) Resolve(ctx context.Context, ID int64) (*TicketType, error) {
+	filter := TicketTypeFilter{IDs: []int64{ID}}
+	if ID <= 0 {
+		isDefault := true
+		filter = TicketTypeFilter{IsDefault: &isDefault}
+	}
+	ticketType, err := s.repo.TicketType.FindOne(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	if !ticketType.IsActive {
+		return nil, NewErr(ErrInput, nil, "%s ticket is not available", ticketType.Name)
+	}
+	return ticketType, nil
+}
+
+// prepare check the required ticket type and move the default flag
+func (s *TicketTypeService) prepare(ctx context.Context, input TicketTypeInput) error {
+	if input.RequireTicketTypeID > 0 {
+		_, err := s.repo.TicketType.FindOne(ctx, TicketTypeFilter{IDs: []int64{input.RequireTicketTypeID}})
+		if err != nil {
+			return err
+		}
+	}
+	if input.IsDefault {
+		return s.repo.TicketType.ClearDefault(ctx)
+	}
+	return nil
+}
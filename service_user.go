@@ -2,18 +2,23 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"time"
 )
 
 func NewUserService(config *Config, repo *RepositoryRegistry) *UserService {
 	return &UserService{
 		config: config,
 		repo:   repo,
+		mfa:    NewMFAService(config, repo),
 	}
 }
 
 type UserService struct {
 	config *Config
 	repo   *RepositoryRegistry
+	mfa    *MFAService
 }
 
 func (s *UserService) Register(ctx context.Context, input UserInput) (*User, error) {
@@ -42,31 +47,450 @@ func (s *UserService) Register(ctx context.Context, input UserInput) (*User, err
 		return nil, err
 	}
 
+	err = s.sendEmailVerification(ctx, current)
+	if err != nil {
+		return nil, err
+	}
+
 	return current, nil
 }
 
-func (s *UserService) Login(ctx context.Context, input UserInput) (string, error) {
+// VerifyEmail confirm the email using the token sent on registration
+func (s *UserService) VerifyEmail(ctx context.Context, input VerifyEmailInput) (*User, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	usable := true
+	userToken, err := s.repo.UserToken.FindOne(ctx, UserTokenFilter{
+		Purposes:    []UserTokenPurpose{UserTokenEmailVerification},
+		TokenHashes: []string{HashToken(input.Token)},
+		IsUsable:    &usable,
+	})
+	if err != nil {
+		if ErrIs(err, ErrNotFound) {
+			return nil, NewErr(ErrInput, nil, "token invalid or expired")
+		}
+		return nil, err
+	}
+	err = s.repo.UserToken.Use(ctx, userToken.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.repo.User.VerifyEmailByID(ctx, userToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userToken.UserID}})
+}
+
+// ResendEmailVerification send a new verification token, limited so it cannot be used to spam the mailbox
+func (s *UserService) ResendEmailVerification(ctx context.Context, userID int64) error {
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return err
+	}
+	if user.IsEmailVerified() {
+		return NewErr(ErrInput, nil, "email already verified")
+	}
+
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour)
+	sent, err := s.repo.UserToken.Find(ctx, UserTokenFilter{
+		UserIDs:      []int64{userID},
+		Purposes:     []UserTokenPurpose{UserTokenEmailVerification},
+		CreatedAfter: &hourAgo,
+	})
+	if err != nil {
+		return err
+	}
+	if int64(len(sent)) >= s.config.EmailVerificationPerHour {
+		return NewErr(ErrInput, nil, "verification email sent too many times, try again later")
+	}
+	cooldown := time.Minute
+	if len(sent) > 0 && now.Sub(sent[0].CreatedAt) < cooldown {
+		wait := cooldown - now.Sub(sent[0].CreatedAt)
+		return NewErr(ErrInput, nil, "verification email just sent, try again in %d seconds", int64(wait.Seconds())+1)
+	}
+
+	return s.sendEmailVerification(ctx, user)
+}
+
+func (s *UserService) Login(ctx context.Context, input UserInput, userAgent string) (*AuthToken, error) {
 	e := NewErr(ErrInput, nil, "email or password invalid!")
 
 	user, err := s.repo.User.FindOne(ctx, UserFilter{Emails: []string{input.Email}})
 	if err != nil {
 		if ErrIs(err, ErrNotFound) {
-			return "", e
+			return nil, e
 		}
-		return "", err
+		return nil, err
 	}
 
 	err = user.VerifyPassword(input.Password)
 	if err != nil {
-		return "", e
+		return nil, e
 	}
 
-	token, err := CreateToken(s.config, user)
+	return s.signIn(ctx, user, userAgent)
+}
+
+// signIn start session of the authenticated user, or return mfa challenge when two-factor is enabled
+func (s *UserService) signIn(ctx context.Context, user *User, userAgent string) (*AuthToken, error) {
+	if user.IsDisabled() {
+		return nil, NewErr(ErrInput, nil, "account disabled, contact support")
+	}
+	if user.IsMFAEnabled() {
+		challenge, token := NewUserToken(user.ID, UserTokenMFAChallenge, mfaChallengeTTL)
+		_, err := s.repo.UserToken.Create(ctx, challenge)
+		if err != nil {
+			return nil, err
+		}
+		return &AuthToken{MFARequired: true, MFAToken: token}, nil
+	}
+
+	return s.startSession(ctx, user, false, userAgent)
+}
+
+// LoginMFA is the second step of login when two-factor is enabled
+func (s *UserService) LoginMFA(ctx context.Context, input MFALoginInput, userAgent string) (*AuthToken, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	usable := true
+	challenge, err := s.repo.UserToken.FindOne(ctx, UserTokenFilter{
+		Purposes:    []UserTokenPurpose{UserTokenMFAChallenge},
+		TokenHashes: []string{HashToken(input.MFAToken)},
+		IsUsable:    &usable,
+	})
+	if err != nil {
+		if ErrIs(err, ErrNotFound) {
+			return nil, NewErr(ErrInput, nil, "mfa token invalid or expired, login again")
+		}
+		return nil, err
+	}
+
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{challenge.UserID}})
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDisabled() {
+		return nil, NewErr(ErrInput, nil, "account disabled, contact support")
+	}
+	err = s.mfa.Verify(ctx, user, input.Code)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.UserToken.Use(ctx, challenge.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.startSession(ctx, user, true, userAgent)
+}
+
+// MFAChallengeEmail is the email of the user logging in with the mfa token, empty when the token is not usable
+func (s *UserService) MFAChallengeEmail(ctx context.Context, mfaToken string) (string, error) {
+	if mfaToken == "" {
+		return "", nil
+	}
+	usable := true
+	challenge, err := s.repo.UserToken.FindOne(ctx, UserTokenFilter{
+		Purposes:    []UserTokenPurpose{UserTokenMFAChallenge},
+		TokenHashes: []string{HashToken(mfaToken)},
+		IsUsable:    &usable,
+	})
+	if err != nil {
+		if ErrIs(err, ErrNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{challenge.UserID}})
 	if err != nil {
 		return "", err
 	}
+	return user.Email, nil
+}
+
+func (s *UserService) startSession(ctx context.Context, user *User, mfa bool, userAgent string) (*AuthToken, error) {
+	session, refreshToken := NewSession(user.ID, s.refreshTokenTTL(), userAgent)
+	session.MFA = mfa
+	ID, err := s.repo.Session.Create(ctx, session)
+	if err != nil {
+		return nil, err
+	}
+	session.ID = ID
+	return s.issueToken(user, session, refreshToken)
+}
+
+// Refresh exchange the refresh token with a new access token, the refresh token is rotated
+func (s *UserService) Refresh(ctx context.Context, input RefreshTokenInput) (*AuthToken, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	active := true
+	hash := HashToken(input.RefreshToken)
+	session, err := s.repo.Session.FindOne(ctx, SessionFilter{RefreshTokenHashes: []string{hash}, IsActive: &active})
+	if err != nil {
+		if ErrIs(err, ErrNotFound) {
+			return nil, NewErr(ErrInput, nil, "refresh token invalid or expired")
+		}
+		return nil, err
+	}
+
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{session.UserID}})
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDisabled() {
+		return nil, NewErr(ErrInput, nil, "account disabled, contact support")
+	}
+
+	rotated, refreshToken := NewSession(user.ID, s.refreshTokenTTL(), input.UserAgent)
+	err = s.repo.Session.Rotate(ctx, session.ID, hash, rotated)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.issueToken(user, session, refreshToken)
+}
+
+// RevokeReusedRefreshToken revoke the session when the refresh token presented was already rotated,
+// either the client or an attacker hold a stolen copy so both have to login again
+func (s *UserService) RevokeReusedRefreshToken(ctx context.Context, input RefreshTokenInput) (bool, error) {
+	err := input.Validate()
+	if err != nil {
+		return false, err
+	}
+
+	active := true
+	hash := HashToken(input.RefreshToken)
+	session, err := s.repo.Session.FindOne(ctx, SessionFilter{PreviousTokenHashes: []string{hash}, IsActive: &active})
+	if err != nil {
+		if ErrIs(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	err = s.repo.Session.RevokeByID(ctx, session.ID)
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Logout revoke the session of the access token, the token stop working immediately
+func (s *UserService) Logout(ctx context.Context, userID, sessionID int64) error {
+	session, err := s.repo.Session.FindOne(ctx, SessionFilter{IDs: []int64{sessionID}, UserIDs: []int64{userID}})
+	if err != nil {
+		return err
+	}
+	return s.repo.Session.RevokeByID(ctx, session.ID)
+}
+
+// LogoutAll revoke every session of the user and invalidate every access token issued
+func (s *UserService) LogoutAll(ctx context.Context, userID int64) error {
+	err := s.repo.Session.RevokeByUserID(ctx, userID, 0)
+	if err != nil {
+		return err
+	}
+	return s.repo.User.IncrementTokenVersionByID(ctx, userID)
+}
+
+// VerifySession reject access token of revoked session or issued before the user token version changed,
+// e.g. after role change or password reset
+func (s *UserService) VerifySession(ctx context.Context, userID, sessionID, tokenVersion int64) error {
+	e := NewErr(ErrInput, nil, "session expired, login again")
+	if sessionID <= 0 {
+		return e
+	}
+	valid, err := s.repo.Session.IsValid(ctx, sessionID, userID, tokenVersion)
+	if err != nil {
+		return err
+	}
+	if !valid {
+		return e
+	}
+	return nil
+}
+
+// ForgotPassword send single-use reset token to the email,
+// unknown email is not an error so the endpoint cannot be used to find registered users
+func (s *UserService) ForgotPassword(ctx context.Context, input ForgotPasswordInput) error {
+	err := input.Validate()
+	if err != nil {
+		return err
+	}
+
+	user, err := s.repo.User.FindOne(ctx, UserFilter{Emails: []string{input.Email}})
+	if err != nil {
+		if ErrIs(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	return s.sendPasswordReset(ctx, user, "Ignore this email if you did not ask for a password reset.")
+}
+
+// ResetPassword set new password using the reset token, every session of the user is revoked
+func (s *UserService) ResetPassword(ctx context.Context, input ResetPasswordInput) error {
+	err := input.Validate()
+	if err != nil {
+		return err
+	}
+
+	usable := true
+	userToken, err := s.repo.UserToken.FindOne(ctx, UserTokenFilter{
+		Purposes:    []UserTokenPurpose{UserTokenPasswordReset},
+		TokenHashes: []string{HashToken(input.Token)},
+		IsUsable:    &usable,
+	})
+	if err != nil {
+		if ErrIs(err, ErrNotFound) {
+			return NewErr(ErrInput, nil, "token invalid or expired")
+		}
+		return err
+	}
+	err = s.repo.UserToken.Use(ctx, userToken.ID)
+	if err != nil {
+		return err
+	}
 
-	return token, nil
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userToken.UserID}})
+	if err != nil {
+		return err
+	}
+	err = user.UpdatePassword(input.Password)
+	if err != nil {
+		return err
+	}
+	err = s.repo.User.UpdatePasswordByID(ctx, user.ID, user)
+	if err != nil {
+		return err
+	}
+	return s.repo.Session.RevokeByUserID(ctx, user.ID, 0)
+}
+
+// ChangePassword of logged in user, other sessions are revoked and current session get a new token
+func (s *UserService) ChangePassword(ctx context.Context, userID, sessionID int64, input ChangePasswordInput) (*AuthToken, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	err = user.VerifyPassword(input.CurrentPassword)
+	if err != nil {
+		return nil, NewErr(ErrInput, err, "current password invalid")
+	}
+	err = user.UpdatePassword(input.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.User.UpdatePasswordByID(ctx, user.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Session.RevokeByUserID(ctx, user.ID, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// token version changed, current access token is no longer valid
+	session, err := s.repo.Session.FindOne(ctx, SessionFilter{IDs: []int64{sessionID}, UserIDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	rotated, refreshToken := NewSession(user.ID, s.refreshTokenTTL(), session.UserAgent)
+	err = s.repo.Session.Rotate(ctx, session.ID, session.RefreshTokenHash, rotated)
+	if err != nil {
+		return nil, err
+	}
+	user, err = s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{userID}})
+	if err != nil {
+		return nil, err
+	}
+	return s.issueToken(user, session, refreshToken)
+}
+
+// sendPasswordReset send single-use reset token to the user, only the latest token can be used
+func (s *UserService) sendPasswordReset(ctx context.Context, user *User, note string) error {
+	err := s.repo.UserToken.Expire(ctx, user.ID, UserTokenPasswordReset)
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(s.config.PasswordResetMinutes) * time.Minute
+	userToken, token := NewUserToken(user.ID, UserTokenPasswordReset, ttl)
+	_, err = s.repo.UserToken.Create(ctx, userToken)
+	if err != nil {
+		return err
+	}
+
+	mailer, err := NewMailer(s.config)
+	if err != nil {
+		return err
+	}
+	return mailer.Send(ctx, Mail{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf(
+			"Use this token to reset your password, it expires in %d minutes:\n\n%s\n\n%s",
+			s.config.PasswordResetMinutes,
+			token,
+			note,
+		),
+	})
+}
+
+func (s *UserService) sendEmailVerification(ctx context.Context, user *User) error {
+	err := s.repo.UserToken.Expire(ctx, user.ID, UserTokenEmailVerification)
+	if err != nil {
+		return err
+	}
+	ttl := time.Duration(s.config.EmailVerificationHours) * time.Hour
+	userToken, token := NewUserToken(user.ID, UserTokenEmailVerification, ttl)
+	_, err = s.repo.UserToken.Create(ctx, userToken)
+	if err != nil {
+		return err
+	}
+
+	mailer, err := NewMailer(s.config)
+	if err != nil {
+		return err
+	}
+	return mailer.Send(ctx, Mail{
+		To:      user.Email,
+		Subject: "Verify your email",
+		Body: fmt.Sprintf(
+			"Use this token to verify your email, it expires in %d hours:\n\n%s",
+			s.config.EmailVerificationHours,
+			token,
+		),
+	})
+}
+
+func (s *UserService) issueToken(user *User, session *Session, refreshToken string) (*AuthToken, error) {
+	token, expiresAt, err := CreateToken(s.config, user, session)
+	if err != nil {
+		return nil, err
+	}
+	return &AuthToken{Token: token, ExpiresAt: expiresAt, RefreshToken: refreshToken}, nil
+}
+
+func (s *UserService) refreshTokenTTL() time.Duration {
+	return time.Duration(s.config.RefreshTokenDays) * 24 * time.Hour
 }
 
 func (s *UserService) GetByEmail(ctx context.Context, email string) (*User, error) {
@@ -87,6 +511,144 @@ func (s *UserService) ChangeRoleByID(ctx context.Context, ID int64, input UserIn
 	return user, nil
 }
 
+func (s *UserService) GetByID(ctx context.Context, ID int64) (*User, error) {
+	return s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{ID}})
+}
+
+func (s *UserService) Pagination(ctx context.Context, filter UserFilter, page PaginateInput) (*Paginate[User], error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.Paginate(ctx, filter, page)
+}
+
+// DisableByID block login of the user and revoke every session
+func (s *UserService) DisableByID(ctx context.Context, actorID, ID int64, input DisableUserInput) (*User, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	user, err := s.managedUser(ctx, actorID, ID)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsDisabled() {
+		return nil, NewErr(ErrInput, nil, "user already disabled")
+	}
+	err = s.repo.User.DisableByID(ctx, user.ID, input.Reason)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Session.RevokeByUserID(ctx, user.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{user.ID}})
+}
+
+func (s *UserService) EnableByID(ctx context.Context, actorID, ID int64) (*User, error) {
+	user, err := s.managedUser(ctx, actorID, ID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsDisabled() {
+		return nil, NewErr(ErrInput, nil, "user is not disabled")
+	}
+	err = s.repo.User.EnableByID(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{user.ID}})
+}
+
+// ForcePasswordReset replace the password with a random one and send reset token to the user,
+// the old password stop working and every session is revoked
+func (s *UserService) ForcePasswordReset(ctx context.Context, actorID, ID int64) (*User, error) {
+	user, err := s.managedUser(ctx, actorID, ID)
+	if err != nil {
+		return nil, err
+	}
+	err = user.UpdatePassword(randomToken(16))
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.User.UpdatePasswordByID(ctx, user.ID, user)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Session.RevokeByUserID(ctx, user.ID, 0)
+	if err != nil {
+		return nil, err
+	}
+	err = s.sendPasswordReset(ctx, user, "Your password was reset by an administrator, set a new one to login again.")
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{user.ID}})
+}
+
+// DeleteByID anonymize the user, carts and sessions are removed but past reservations are kept.
+// User with wallet balance or reservation still in progress cannot be deleted
+func (s *UserService) DeleteByID(ctx context.Context, actorID, ID int64) error {
+	user, err := s.managedUser(ctx, actorID, ID)
+	if err != nil {
+		return err
+	}
+
+	wallet, err := s.repo.Wallet.Get(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if wallet.Balance > 0 {
+		return NewErr(ErrInput, nil, "user still has wallet balance")
+	}
+	count, err := s.repo.Reservation.CountOpenByUserID(ctx, user.ID)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return NewErr(ErrInput, nil, "user still has %d unpaid or upcoming reservation", count)
+	}
+
+	return s.repo.User.AnonymizeByID(ctx, user.ID)
+}
+
+// managedUser is the target of admin user management, the actor cannot manage user
+// whose role has permission the actor lack, and admin cannot be managed until the role is changed
+func (s *UserService) managedUser(ctx context.Context, actorID, ID int64) (*User, error) {
+	if actorID == ID {
+		return nil, NewErr(ErrInput, nil, "cannot manage own account")
+	}
+	user, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{ID}})
+	if err != nil {
+		return nil, err
+	}
+	if user.Role == UserAdmin {
+		return nil, NewErr(ErrInput, nil, "admin cannot be managed, change the role first")
+	}
+
+	// role of the actor is read again, the one in the token may be outdated
+	actor, err := s.repo.User.FindOne(ctx, UserFilter{IDs: []int64{actorID}})
+	if err != nil {
+		return nil, err
+	}
+	actorPermissions, err := s.repo.Role.FindPermissionsByRoleName(ctx, actor.Role)
+	if err != nil {
+		return nil, err
+	}
+	permissions, err := s.repo.Role.FindPermissionsByRoleName(ctx, user.Role)
+	if err != nil {
+		return nil, err
+	}
+	for _, permission := range permissions {
+		if !slices.Contains(actorPermissions, permission) {
+			return nil, NewErr(ErrInput, nil, "cannot manage user with %s permission", permission)
+		}
+	}
+	return user, nil
+}
+
 func (s *UserService) GetRoleByID(ctx context.Context, ID int64) (*Role, error) {
 	return s.repo.User.FindRoleOne(ctx, RoleFilter{IDs: []int64{ID}})
 }
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+func NewWalletService(config *Config, repo *RepositoryRegistry) *WalletService {
+	return &WalletService{
+		config:  config,
+		repo:    repo,
+		payment: NewPaymentService(config, repo),
+	}
+}
+
+type WalletService struct {
+	config  *Config
+	repo    *RepositoryRegistry
+	payment *PaymentService
+}
+
+func (s *WalletService) Summary(ctx context.Context, userID int64, page PaginateInput) (*WalletSummary, error) {
+	wallet, err := s.repo.Wallet.Get(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := s.repo.Ledger.Pagination(ctx, LedgerEntryFilter{AccountCodes: []string{WalletAccount(userID)}}, page)
+	if err != nil {
+		return nil, err
+	}
+	return &WalletSummary{Wallet: *wallet, Entries: entries}, nil
+}
+
+// TopUp move the whole gift card balance to the wallet of the user
+func (s *WalletService) TopUp(ctx context.Context, userID int64, input WalletTopUpInput) (*Wallet, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	giftCard, err := s.repo.Wallet.RedeemGiftCard(ctx, input.Code, userID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.repo.Wallet.Credit(ctx, userID, giftCard.Balance)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := NewLedgerJournalInput(
+		JournalWalletTopUp,
+		fmt.Sprintf("wallet top up from gift card #%d", giftCard.ID),
+		LedgerEntryInput{AccountCode: GiftCardAccount(giftCard.ID), Amount: giftCard.Balance},
+		LedgerEntryInput{AccountCode: WalletAccount(userID), Amount: -giftCard.Balance},
+	)
+	journal.GiftCardID = giftCard.ID
+	_, err = s.repo.Ledger.CreateJournal(ctx, journal)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Wallet.Get(ctx, userID)
+}
+
+// IssueGiftCard create gift card by admin, e.g. for compensation or campaign
+func (s *WalletService) IssueGiftCard(ctx context.Context, input GiftCardInput) (*GiftCard, error) {
+	newGiftCard, err := NewGiftCard(input, GiftCardSourceAdmin, 0)
+	if err != nil {
+		return nil, err
+	}
+	ID, err := s.repo.Wallet.CreateGiftCard(ctx, newGiftCard)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := NewLedgerJournalInput(
+		JournalGiftCardIssue,
+		fmt.Sprintf("gift card #%d issued", ID),
+		LedgerEntryInput{AccountCode: AccountGiftCardGrant, Amount: newGiftCard.InitialAmount},
+		LedgerEntryInput{AccountCode: GiftCardAccount(ID), Amount: -newGiftCard.InitialAmount},
+	)
+	journal.GiftCardID = ID
+	_, err = s.repo.Ledger.CreateJournal(ctx, journal)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Wallet.FindOneGiftCard(ctx, GiftCardFilter{IDs: []int64{ID}})
+}
+
+// StartGiftCardPurchase record the pending charge of gift card, the gift card is only created once paid
+func (s *WalletService) StartGiftCardPurchase(ctx context.Context, userID int64, input GiftCardInput) (*Payment, error) {
+	err := input.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.payment.Start(ctx, NewPayment(PaymentKindCharge, userID, 0, input.Amount, "gift card purchase"))
+}
+
+// PurchaseGiftCard create the gift card paid by the gateway charge of StartGiftCardPurchase
+func (s *WalletService) PurchaseGiftCard(ctx context.Context, userID int64, input GiftCardInput, receipt *PaymentReceipt) (*GiftCard, error) {
+	newGiftCard, err := NewGiftCard(input, GiftCardSourcePurchase, userID)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.Amount != newGiftCard.InitialAmount {
+		return nil, NewErr(ErrInternal, nil, "charged %d for gift card of %d", receipt.Amount, newGiftCard.InitialAmount)
+	}
+
+	ID, err := s.repo.Wallet.CreateGiftCard(ctx, newGiftCard)
+	if err != nil {
+		return nil, err
+	}
+
+	journal := NewLedgerJournalInput(
+		JournalGiftCardPurchase,
+		fmt.Sprintf("gift card #%d purchased", ID),
+		LedgerEntryInput{AccountCode: AccountGatewayCash, Amount: receipt.Amount},
+		LedgerEntryInput{AccountCode: GiftCardAccount(ID), Amount: -receipt.Amount},
+	)
+	journal.GiftCardID = ID
+	journal.Reference = receipt.Reference
+	_, err = s.repo.Ledger.CreateJournal(ctx, journal)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.repo.Wallet.FindOneGiftCard(ctx, GiftCardFilter{IDs: []int64{ID}})
+}
+
+func (s *WalletService) PaginationGiftCard(ctx context.Context, filter GiftCardFilter, page PaginateInput) (*Paginate[GiftCard], error) {
+	err := filter.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return s.repo.Wallet.PaginationGiftCard(ctx, filter, page)
+}
+
+// StartPay check the reservation can be paid and record the pending gateway charge of the part not paid from wallet,
+// the payment is nil when wallet or discount cover the whole price
+func (s *WalletService) StartPay(ctx context.Context, reservation *Reservation, input PayReservationInput) (*Payment, error) {
+	err := input.Validate(reservation.TotalPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	walletAmount := input.WalletAmount
+	if walletAmount > 0 && reservation.Currency != DefaultCurrency {
+		return nil, NewErr(ErrInput, nil, "wallet can only pay reservation in %s", DefaultCurrency)
+	}
+	// checked again by the debit, this only avoid charging the gateway for payment that will fail
+	if walletAmount > 0 {
+		wallet, err := s.repo.Wallet.Get(ctx, reservation.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if wallet.Balance < walletAmount {
+			return nil, NewErr(ErrInput, nil, "insufficient wallet balance")
+		}
+	}
+
+	gatewayAmount := reservation.TotalPrice - walletAmount
+	if gatewayAmount == 0 {
+		return nil, nil
+	}
+	return s.payment.Start(ctx, NewPayment(
+		PaymentKindCharge,
+		reservation.UserID,
+		reservation.ID,
+		gatewayAmount,
+		fmt.Sprintf("reservation #%d", reservation.ID),
+	))
+}
+
+// Pay settle the reservation price from wallet first then the gateway charge of StartPay,
+// the collected money settle the receivable of the sale
+func (s *WalletService) Pay(ctx context.Context, reservation *Reservation, input PayReservationInput, charged *PaymentReceipt) (*PaymentReceipt, error) {
+	err := input.Validate(reservation.TotalPrice)
+	if err != nil {
+		return nil, err
+	}
+
+	walletAmount := input.WalletAmount
+	gatewayAmount := reservation.TotalPrice - walletAmount
+	if charged.Amount != gatewayAmount {
+		return nil, NewErr(ErrInput, nil, "reservation price changed during payment, try again")
+	}
+	if walletAmount > 0 {
+		err = s.repo.Wallet.Debit(ctx, reservation.UserID, walletAmount)
+		if err != nil {
+			return nil, err
+		}
+	}
+	receipt := &PaymentReceipt{Reference: charged.Reference, Amount: reservation.TotalPrice}
+
+	// nothing to record for reservation fully covered by discount
+	if reservation.TotalPrice == 0 {
+		return receipt, nil
+	}
+
+	journal := NewLedgerJournalInput(
+		JournalPayment,
+		fmt.Sprintf("payment of reservation #%d", reservation.ID),
+		LedgerEntryInput{AccountCode: WalletAccount(reservation.UserID), Amount: walletAmount},
+		LedgerEntryInput{AccountCode: AccountGatewayCash, Amount: gatewayAmount},
+		LedgerEntryInput{AccountCode: AccountReceivable, Amount: -reservation.TotalPrice},
+	)
+	journal.ReservationID = reservation.ID
+	journal.Reference = receipt.Reference
+	_, err = s.repo.Ledger.CreateJournal(ctx, journal)
+	if err != nil {
+		return nil, err
+	}
+	return receipt, nil
+}
+
+// Refund give back the money of paid reservation the same way it was paid,
+// the gateway part is returned as pending refund to be sent once the cancellation is committed
+func (s *WalletService) Refund(ctx context.Context, reservation *Reservation) (*Payment, error) {
+	if reservation.PaidWallet > 0 {
+		err := s.repo.Wallet.Credit(ctx, reservation.UserID, reservation.PaidWallet)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var payment *Payment
+	if reservation.PaidGateway > 0 {
+		refund := NewPayment(
+			PaymentKindRefund,
+			reservation.UserID,
+			reservation.ID,
+			reservation.PaidGateway,
+			fmt.Sprintf("refund of reservation #%d", reservation.ID),
+		)
+		refund.Reference = reservation.PaymentReference
+		var err error
+		payment, err = s.payment.Start(ctx, refund)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	journal := NewLedgerJournalInput(
+		JournalRefund,
+		fmt.Sprintf("refund of reservation #%d", reservation.ID),
+		LedgerEntryInput{AccountCode: AccountReceivable, Amount: reservation.PaidWallet + reservation.PaidGateway},
+		LedgerEntryInput{AccountCode: WalletAccount(reservation.UserID), Amount: -reservation.PaidWallet},
+		LedgerEntryInput{AccountCode: AccountGatewayCash, Amount: -reservation.PaidGateway},
+	)
+	if len(journal.Entries) == 0 {
+		return payment, nil
+	}
+	journal.ReservationID = reservation.ID
+	journal.Reference = reservation.PaymentReference
+	_, err := s.repo.Ledger.CreateJournal(ctx, journal)
+	if err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
@@ -23,6 +23,7 @@ const (
 	ErrInternal ErrType = "ErrorInternal"
 	ErrInput    ErrType = "ErrorInput"
 	ErrNotFound ErrType = "ErrorNotFound"
+	ErrTooMany  ErrType = "ErrorTooManyRequests"
 )
 
 type Err struct {
@@ -125,6 +126,8 @@ func NewAPIErr(c echo.Context, err error) error {
 			code = http.StatusBadRequest
 		case ErrNotFound:
 			code = http.StatusNotFound
+		case ErrTooMany:
+			code = http.StatusTooManyRequests
 		case ErrInternal:
 			code = http.StatusInternalServerError
 			logInfo["stacktrace"] = e.Stacktrace
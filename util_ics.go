@@ -0,0 +1,59 @@
+package main
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const icsTimeLayout = "20060102T150405Z"
+
+var icsEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// RenderCalendar write the events as iCalendar (RFC 5545) document
+func RenderCalendar(name string, events []CalendarEvent) []byte {
+	var buf bytes.Buffer
+	writeICSLine(&buf, "BEGIN:VCALENDAR")
+	writeICSLine(&buf, "VERSION:2.0")
+	writeICSLine(&buf, "PRODID:-//"+calendarDomain+"//EN")
+	writeICSLine(&buf, "CALSCALE:GREGORIAN")
+	writeICSLine(&buf, "METHOD:PUBLISH")
+	writeICSLine(&buf, "X-WR-CALNAME:"+icsEscaper.Replace(name))
+	for _, event := range events {
+		writeICSLine(&buf, "BEGIN:VEVENT")
+		writeICSLine(&buf, "UID:"+event.UID)
+		writeICSLine(&buf, "DTSTAMP:"+event.Stamp.UTC().Format(icsTimeLayout))
+		writeICSLine(&buf, "DTSTART:"+event.Start.UTC().Format(icsTimeLayout))
+		writeICSLine(&buf, "DTEND:"+event.End.UTC().Format(icsTimeLayout))
+		writeICSLine(&buf, "SEQUENCE:"+strconv.FormatInt(event.Sequence, 10))
+		writeICSLine(&buf, "STATUS:"+string(event.Status))
+		writeICSLine(&buf, "SUMMARY:"+icsEscaper.Replace(event.Summary))
+		if event.Description != "" {
+			writeICSLine(&buf, "DESCRIPTION:"+icsEscaper.Replace(event.Description))
+		}
+		if event.Location != "" {
+			writeICSLine(&buf, "LOCATION:"+icsEscaper.Replace(event.Location))
+		}
+		writeICSLine(&buf, "END:VEVENT")
+	}
+	writeICSLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writeICSLine fold line longer than 75 octets without splitting utf-8 character
+func writeICSLine(buf *bytes.Buffer, line string) {
+	limit := 75
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = 74 // the leading space count
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
@@ -1,31 +1,199 @@
 package main
 
 import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
 type JwtCustomClaims struct {
-	ID    int64  `json:"id"`
-	Email string `json:"email"`
-	Role  string `json:"role"`
+	ID           int64  `json:"id"`
+	Email        string `json:"email"`
+	Role         string `json:"role"`
+	SessionID    int64  `json:"sid"`
+	TokenVersion int64  `json:"ver"`
+	MFA          bool   `json:"mfa,omitempty"` // session started with second factor
 	jwt.RegisteredClaims
 }
 
-func CreateToken(c *Config, user *User) (string, error) {
+func CreateToken(c *Config, user *User, session *Session) (string, time.Time, error) {
+	expiresAt := time.Now().Add(time.Duration(c.AccessTokenMinutes) * time.Minute)
 	claims := &JwtCustomClaims{
-		ID:    user.ID,
-		Email: user.Email,
-		Role:  user.Role,
+		ID:           user.ID,
+		Email:        user.Email,
+		Role:         user.Role,
+		SessionID:    session.ID,
+		TokenVersion: user.TokenVersion,
+		MFA:          session.MFA,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 72)),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
 		},
 	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	t, err := token.SignedString([]byte(c.JWTSecret))
+	t, err := c.JWTKeys.Sign(claims)
 	if err != nil {
-		return "", NewErr(ErrInput, err, "failed to create token, try again later")
+		return "", time.Time{}, NewErr(ErrInput, err, "failed to create token, try again later")
 	}
-	return t, nil
+	return t, expiresAt, nil
+}
+
+// JWK is public part of a signing key, see RFC 7517
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	Curve     string `json:"crv,omitempty"` // OKP
+	X         string `json:"x,omitempty"`   // OKP
+	N         string `json:"n,omitempty"`   // RSA
+	E         string `json:"e,omitempty"`   // RSA
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWTKey is an asymmetric signing key, the id is the RFC 7638 thumbprint so it is stable across restart
+type JWTKey struct {
+	ID     string
+	Method jwt.SigningMethod
+	Signer crypto.Signer
+}
+
+func NewJWTKey(signer crypto.Signer) (*JWTKey, error) {
+	key := JWTKey{Signer: signer}
+	switch signer.Public().(type) {
+	case *rsa.PublicKey:
+		key.Method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		key.Method = jwt.SigningMethodEdDSA
+	default:
+		return nil, fmt.Errorf("jwt key type %T not supported, use RSA or Ed25519", signer)
+	}
+
+	// member order of the thumbprint input is lexicographic
+	jwk := key.JWK()
+	var thumbprint []byte
+	switch jwk.KeyType {
+	case "RSA":
+		thumbprint, _ = json.Marshal(struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.KeyType, jwk.N})
+	case "OKP":
+		thumbprint, _ = json.Marshal(struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Curve, jwk.KeyType, jwk.X})
+	}
+	sum := sha256.Sum256(thumbprint)
+	key.ID = base64.RawURLEncoding.EncodeToString(sum[:])
+	return &key, nil
+}
+
+func (k *JWTKey) JWK() JWK {
+	jwk := JWK{KeyID: k.ID, Use: "sig", Algorithm: k.Method.Alg()}
+	switch public := k.Signer.Public().(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
+
+// JWTKeySet sign with the first key and verify with any of the keys,
+// so a new key can be added in front while token of the old key is still valid.
+// Without keys, token is signed with the HS256 secret
+type JWTKeySet struct {
+	secret []byte
+	keys   []JWTKey
+}
+
+func NewJWTKeySet(secret string, signers ...crypto.Signer) (*JWTKeySet, error) {
+	set := JWTKeySet{secret: []byte(secret)}
+	for _, signer := range signers {
+		key, err := NewJWTKey(signer)
+		if err != nil {
+			return nil, err
+		}
+		set.keys = append(set.keys, *key)
+	}
+	return &set, nil
+}
+
+// LoadJWTKeySet read PEM encoded private keys, PKCS#1 or PKCS#8 RSA and PKCS#8 Ed25519
+func LoadJWTKeySet(secret string, files []string) (*JWTKeySet, error) {
+	signers := make([]crypto.Signer, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read jwt key %s: %w", file, err)
+		}
+		if key, err := jwt.ParseRSAPrivateKeyFromPEM(data); err == nil {
+			signers = append(signers, key)
+			continue
+		}
+		key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("jwt key %s is not a RSA or Ed25519 private key", file)
+		}
+		signers = append(signers, key.(crypto.Signer))
+	}
+	return NewJWTKeySet(secret, signers...)
+}
+
+func (s *JWTKeySet) Sign(claims jwt.Claims) (string, error) {
+	if len(s.keys) == 0 {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secret)
+	}
+	key := s.keys[0]
+	token := jwt.NewWithClaims(key.Method, claims)
+	token.Header["kid"] = key.ID
+	return token.SignedString(key.Signer)
+}
+
+// Keyfunc pick the verification key by kid, the algorithm must be the one of the key
+// so a token cannot be forged using the public key as HMAC secret
+func (s *JWTKeySet) Keyfunc(token *jwt.Token) (any, error) {
+	if len(s.keys) == 0 {
+		if token.Method != jwt.SigningMethodHS256 {
+			return nil, fmt.Errorf("unexpected jwt signing method %v", token.Header["alg"])
+		}
+		return s.secret, nil
+	}
+	kid, _ := token.Header["kid"].(string)
+	for _, key := range s.keys {
+		if key.ID != kid {
+			continue
+		}
+		if token.Method.Alg() != key.Method.Alg() {
+			return nil, fmt.Errorf("unexpected jwt signing method %v", token.Header["alg"])
+		}
+		return key.Signer.Public(), nil
+	}
+	return nil, fmt.Errorf("unknown jwt key id %v", token.Header["kid"])
+}
+
+// JWKS publish the public keys, empty when signed with the HS256 secret
+func (s *JWTKeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(s.keys))}
+	for _, key := range s.keys {
+		jwks.Keys = append(jwks.Keys, key.JWK())
+	}
+	return jwks
 }
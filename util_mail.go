@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type Mail struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Message is the mail in RFC 5322 format
+func (m Mail) Message(from string) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", m.To)
+	fmt.Fprintf(&b, "Subject: %s\r\n", m.Subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(m.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
+
+// Mailer deliver mail to the user, e.g. password reset token
+type Mailer interface {
+	Send(ctx context.Context, mail Mail) error
+}
+
+func NewMailer(config *Config) (Mailer, error) {
+	switch config.Mailer {
+	case "", "log":
+		return &LogMailer{}, nil
+	case "file":
+		return &FileMailer{dir: config.MailDir, from: config.MailFrom}, nil
+	case "smtp":
+		return &SMTPMailer{addr: config.SMTPAddr, username: config.SMTPUsername, password: config.SMTPPassword, from: config.MailFrom}, nil
+	}
+	return nil, fmt.Errorf("mailer %s is not supported", config.Mailer)
+}
+
+// LogMailer print the mail to the server log, used in development
+type LogMailer struct{}
+
+func (m *LogMailer) Send(_ context.Context, mail Mail) error {
+	log.Printf("mail to %s, subject %q\n%s", mail.To, mail.Subject, mail.Body)
+	return nil
+}
+
+// FileMailer write every mail as .eml file in the directory, used in development and test
+type FileMailer struct {
+	dir  string
+	from string
+}
+
+func (m *FileMailer) Send(_ context.Context, mail Mail) error {
+	err := os.MkdirAll(m.dir, 0o755)
+	if err != nil {
+		return NewErr(ErrInternal, err, "failed to send mail")
+	}
+	name := fmt.Sprintf("%d-%s.eml", time.Now().UnixNano(), strings.ReplaceAll(mail.To, string(filepath.Separator), "_"))
+	err = os.WriteFile(filepath.Join(m.dir, name), mail.Message(m.from), 0o644)
+	if err != nil {
+		return NewErr(ErrInternal, err, "failed to send mail")
+	}
+	return nil
+}
+
+type SMTPMailer struct {
+	addr     string
+	username string
+	password string
+	from     string
+}
+
+func (m *SMTPMailer) Send(_ context.Context, mail Mail) error {
+	var auth smtp.Auth
+	if m.username != "" {
+		host, _, _ := net.SplitHostPort(m.addr)
+		auth = smtp.PlainAuth("", m.username, m.password, host)
+	}
+	err := smtp.SendMail(m.addr, auth, m.from, []string{mail.To}, mail.Message(m.from))
+	if err != nil {
+		return NewErr(ErrInternal, err, "failed to send mail")
+	}
+	return nil
+}
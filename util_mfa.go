@@ -0,0 +1,56 @@
+package main
+
+import (
+	"crypto/subtle"
+	"encoding/base64"
+	"time"
+
+	"github.com/pquerna/otp/totp"
+	"github.com/skip2/go-qrcode"
+)
+
+const (
+	mfaIssuer     = "Movie Reservation System"
+	mfaPeriod     = 30 // seconds of a TOTP time step
+	mfaSkewSteps  = 1  // accepted steps before and after now, for clock drift of the phone
+	mfaBackupSize = 10 // backup codes given on enrollment
+
+	mfaChallengeTTL = 5 * time.Minute // time to enter the code after password is verified
+)
+
+// GenerateMFASecret create TOTP secret and the otpauth:// provisioning URI for authenticator app
+func GenerateMFASecret(email string) (secret string, uri string, err error) {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: mfaIssuer, AccountName: email, Period: mfaPeriod})
+	if err != nil {
+		return "", "", NewErr(ErrInternal, err, "failed to create two-factor secret")
+	}
+	return key.Secret(), key.URL(), nil
+}
+
+// VerifyTOTP return time step of the matching code, a step not after lastStep is rejected
+// so an observed code cannot be used again
+func VerifyTOTP(secret, code string, lastStep int64, now time.Time) (int64, bool) {
+	current := now.Unix() / mfaPeriod
+	for step := current - mfaSkewSteps; step <= current+mfaSkewSteps; step++ {
+		if step <= lastStep {
+			continue
+		}
+		expected, err := totp.GenerateCode(secret, time.Unix(step*mfaPeriod, 0))
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step, true
+		}
+	}
+	return 0, false
+}
+
+// MFAQRCodeDataURI is the provisioning URI as PNG data URI to scan from the enrollment page
+func MFAQRCodeDataURI(uri string) (string, error) {
+	png, err := qrcode.Encode(uri, qrcode.Medium, 256)
+	if err != nil {
+		return "", NewErr(ErrInternal, err, "failed to create qr code")
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCProvider is an OpenID Connect identity provider, the discovery document is fetched on first use
+type OIDCProvider struct {
+	Name         string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string // callback registered at the provider, the frontend or /api/oidc/{provider}/callback
+	Scopes       []string
+
+	mu       sync.Mutex
+	provider *oidc.Provider
+}
+
+// NewOIDCProviders read OIDC_PROVIDERS, a comma separated list of names,
+// each configured by OIDC_<NAME>_ISSUER, OIDC_<NAME>_CLIENT_ID, OIDC_<NAME>_CLIENT_SECRET,
+// OIDC_<NAME>_REDIRECT_URL and optional OIDC_<NAME>_SCOPES
+func NewOIDCProviders() (map[string]*OIDCProvider, error) {
+	providers := map[string]*OIDCProvider{}
+	for _, name := range strings.Split(os.Getenv("OIDC_PROVIDERS"), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		prefix := fmt.Sprintf("OIDC_%s_", strings.ToUpper(name))
+		provider := OIDCProvider{
+			Name:         name,
+			Issuer:       os.Getenv(prefix + "ISSUER"),
+			ClientID:     os.Getenv(prefix + "CLIENT_ID"),
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			RedirectURL:  os.Getenv(prefix + "REDIRECT_URL"),
+			Scopes:       []string{oidc.ScopeOpenID, "email", "profile"},
+		}
+		if value := os.Getenv(prefix + "SCOPES"); value != "" {
+			provider.Scopes = strings.Fields(strings.ReplaceAll(value, ",", " "))
+		}
+		if provider.Issuer == "" || provider.ClientID == "" || provider.RedirectURL == "" {
+			return nil, fmt.Errorf("oidc provider %s need %sISSUER, %sCLIENT_ID and %sREDIRECT_URL", name, prefix, prefix, prefix)
+		}
+		providers[name] = &provider
+	}
+	return providers, nil
+}
+
+// AuthCodeURL is where the user sign in, the code is bound to the verifier by PKCE S256 challenge
+func (p *OIDCProvider) AuthCodeURL(ctx context.Context, state, nonce, verifier string) (string, error) {
+	conf, _, err := p.config(ctx)
+	if err != nil {
+		return "", err
+	}
+	return conf.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(verifier)), nil
+}
+
+// Exchange the authorization code and verify the id token, the nonce must be the one of the authorization
+func (p *OIDCProvider) Exchange(ctx context.Context, code, verifier, nonce string) (*OIDCIdentity, error) {
+	conf, provider, err := p.config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := conf.Exchange(ctx, code, oauth2.VerifierOption(verifier))
+	if err != nil {
+		return nil, NewErr(ErrInput, err, "failed to sign in with %s, try again", p.Name)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok {
+		return nil, NewErr(ErrInput, nil, "%s did not return id token", p.Name)
+	}
+	idToken, err := provider.Verifier(&oidc.Config{ClientID: p.ClientID}).Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, NewErr(ErrInput, err, "id token of %s invalid", p.Name)
+	}
+	if idToken.Nonce != nonce {
+		return nil, NewErr(ErrInput, nil, "id token of %s invalid", p.Name)
+	}
+
+	var claims struct {
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}
+	err = idToken.Claims(&claims)
+	if err != nil {
+		return nil, NewErr(ErrInput, err, "id token of %s invalid", p.Name)
+	}
+	return &OIDCIdentity{
+		Provider:      p.Name,
+		Subject:       idToken.Subject,
+		Email:         normalizeLoginEmail(claims.Email),
+		EmailVerified: claims.EmailVerified,
+	}, nil
+}
+
+func (p *OIDCProvider) config(ctx context.Context) (*oauth2.Config, *oidc.Provider, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.provider == nil {
+		provider, err := oidc.NewProvider(ctx, p.Issuer)
+		if err != nil {
+			return nil, nil, NewErr(ErrInternal, err, "%s is not available, try again later", p.Name)
+		}
+		p.provider = provider
+	}
+	conf := oauth2.Config{
+		ClientID:     p.ClientID,
+		ClientSecret: p.ClientSecret,
+		RedirectURL:  p.RedirectURL,
+		Endpoint:     p.provider.Endpoint(),
+		Scopes:       p.Scopes,
+	}
+	return &conf, p.provider, nil
+}
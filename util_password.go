@@ -1,6 +1,9 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -19,3 +22,9 @@ func VerifyPassword(password, hashed string) error {
 	}
 	return nil
 }
+
+// HashToken of random token sent to the user, only the hash is stored so a leaked database cannot be used
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+)
+
+type PaymentCharge struct {
+	UserID         int64
+	Amount         int64
+	Description    string
+	IdempotencyKey string // same key return the first result instead of charging again
+}
+
+type PaymentRefund struct {
+	Reference      string
+	Amount         int64
+	IdempotencyKey string
+}
+
+type PaymentReceipt struct {
+	Reference string
+	Amount    int64
+}
+
+// PaymentGateway collect money outside the system, e.g. card or bank transfer
+type PaymentGateway interface {
+	Charge(ctx context.Context, charge PaymentCharge) (*PaymentReceipt, error)
+	Refund(ctx context.Context, refund PaymentRefund) error
+}
+
+func NewPaymentGateway(name string) (PaymentGateway, error) {
+	switch name {
+	case "", "offline":
+		return offlinePaymentGateway, nil
+	}
+	return nil, fmt.Errorf("payment gateway %s is not supported", name)
+}
+
+var offlinePaymentGateway = &OfflinePaymentGateway{receipts: map[string]PaymentReceipt{}}
+
+// OfflinePaymentGateway approve every charge, used in development and for payment at the counter
+type OfflinePaymentGateway struct {
+	mu       sync.Mutex
+	receipts map[string]PaymentReceipt // by idempotency key
+}
+
+func (g *OfflinePaymentGateway) Charge(_ context.Context, charge PaymentCharge) (*PaymentReceipt, error) {
+	if charge.Amount <= 0 {
+		return nil, NewErr(ErrInput, nil, "charge amount minimum is 1")
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if receipt, ok := g.receipts[charge.IdempotencyKey]; ok && charge.IdempotencyKey != "" {
+		return &receipt, nil
+	}
+	receipt := PaymentReceipt{Reference: "offline-" + randomToken(8), Amount: charge.Amount}
+	if charge.IdempotencyKey != "" {
+		g.receipts[charge.IdempotencyKey] = receipt
+	}
+	return &receipt, nil
+}
+
+func (g *OfflinePaymentGateway) Refund(_ context.Context, refund PaymentRefund) error {
+	if refund.Amount <= 0 {
+		return NewErr(ErrInput, nil, "refund amount minimum is 1")
+	}
+	return nil
+}
+
+// randomToken is a hex string of n secure random bytes
+func randomToken(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
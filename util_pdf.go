@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/jung-kurt/gofpdf"
+)
+
+const (
+	pdfMargin       = 15.0
+	pdfTicketHeight = 60.0
+	pdfQRSize       = 50.0
+)
+
+// RenderTicketsPDF lay out one bordered ticket per seat with its qr code on A4 page,
+// followed by the booking confirmation of the receipt, reprint is marked on every ticket
+func RenderTicketsPDF(tickets []Ticket, receipt *Receipt, reprint bool) ([]byte, error) {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.SetTitle(fmt.Sprintf("Tickets of reservation #%d", receipt.ReservationID), true)
+	pdf.SetCreator("movie-reservation-system", true)
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+
+	pageWidth, pageHeight := pdf.GetPageSize()
+	width := pageWidth - 2*pdfMargin
+
+	pdf.AddPage()
+	pdf.SetFont("Helvetica", "B", 16)
+	pdf.CellFormat(width, 10, tr(fmt.Sprintf("Reservation #%d", receipt.ReservationID)), "", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.CellFormat(width, 6, tr(fmt.Sprintf("%s - %d ticket(s)", receipt.Customer, len(tickets))), "", 1, "L", false, 0, "")
+	pdf.Ln(4)
+
+	for i, ticket := range tickets {
+		if pdf.GetY()+pdfTicketHeight > pageHeight-pdfMargin {
+			pdf.AddPage()
+		}
+		x, y := pdf.GetX(), pdf.GetY()
+		pdf.SetDrawColor(120, 120, 120)
+		pdf.Rect(x, y, width, pdfTicketHeight, "D")
+
+		png, err := TicketQRCodePNG(ticket.Token, 512)
+		if err != nil {
+			return nil, err
+		}
+		name := fmt.Sprintf("qr-%d", ticket.ReservationItemID)
+		pdf.RegisterImageOptionsReader(name, gofpdf.ImageOptions{ImageType: "PNG"}, bytes.NewReader(png))
+		pdf.ImageOptions(name, x+width-pdfQRSize-5, y+5, pdfQRSize, pdfQRSize, false, gofpdf.ImageOptions{ImageType: "PNG"}, 0, "")
+
+		textWidth := width - pdfQRSize - 15
+		pdf.SetXY(x+5, y+5)
+		pdf.SetFont("Helvetica", "B", 14)
+		pdf.CellFormat(textWidth, 8, tr(ticket.Movie), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 11)
+		pdf.CellFormat(textWidth, 7, tr(fmt.Sprintf("Room: %s", ticket.Room)), "", 2, "L", false, 0, "")
+		pdf.CellFormat(textWidth, 7, tr(fmt.Sprintf("Seat: %s", ticket.Seat)), "", 2, "L", false, 0, "")
+		pdf.CellFormat(textWidth, 7, ticket.ShowtimeStart.Format("Mon, 02 Jan 2006 15:04 MST"), "", 2, "L", false, 0, "")
+		pdf.SetFont("Helvetica", "", 8)
+		pdf.SetTextColor(100, 100, 100)
+		pdf.CellFormat(textWidth, 6, fmt.Sprintf("Ticket %d of %d, #%d", i+1, len(tickets), ticket.ReservationItemID), "", 2, "L", false, 0, "")
+		if reprint {
+			pdf.SetFont("Helvetica", "B", 8)
+			pdf.CellFormat(textWidth, 6, fmt.Sprintf("REPRINT %s", time.Now().Format(time.DateTime)), "", 2, "L", false, 0, "")
+		}
+		pdf.SetTextColor(0, 0, 0)
+
+		pdf.SetXY(x, y+pdfTicketHeight+5)
+	}
+
+	lines := receiptSummary(receipt)
+	if pdf.GetY()+float64(len(lines)+2)*6 > pageHeight-pdfMargin {
+		pdf.AddPage()
+	}
+	pdf.Ln(2)
+	pdf.SetFont("Helvetica", "B", 12)
+	pdf.CellFormat(width, 8, tr(fmt.Sprintf("Booking confirmation %s", receipt.Number)), "B", 1, "L", false, 0, "")
+	pdf.SetFont("Helvetica", "", 10)
+	for _, line := range lines {
+		pdf.CellFormat(width/2, 6, tr(line[0]), "", 0, "L", false, 0, "")
+		pdf.CellFormat(width/2, 6, tr(line[1]), "", 1, "R", false, 0, "")
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, NewErr(ErrInternal, err, "failed to render pdf")
+	}
+	return buf.Bytes(), nil
+}
+
+// receiptSummary is the label and amount rows of the receipt
+func receiptSummary(receipt *Receipt) [][2]string {
+	money := func(amount int64) string { return NewMoney(amount, receipt.Currency).String() }
+	lines := [][2]string{
+		{"Issued", receipt.IssuedAt.Format(time.DateTime)},
+		{"Subtotal", money(receipt.Subtotal)},
+	}
+	if receipt.Discount > 0 {
+		lines = append(lines, [2]string{fmt.Sprintf("Discount %s", receipt.PromoCode), "-" + money(receipt.Discount)})
+	}
+	if receipt.PointsDiscount > 0 {
+		lines = append(lines, [2]string{"Points", "-" + money(receipt.PointsDiscount)})
+	}
+	for _, fee := range receipt.Fees {
+		lines = append(lines, [2]string{fee.Name, money(fee.Total)})
+	}
+	lines = append(lines,
+		[2]string{fmt.Sprintf("Tax %s", receipt.TaxPercent()), money(receipt.Tax)},
+		[2]string{"Total", money(receipt.Total)},
+	)
+	return lines
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// PermissionCache keep the permissions of each role for a while so permission check does not query on every request.
+// Change on this instance invalidate it, other instances see the change after the ttl
+type PermissionCache struct {
+	ttl time.Duration
+
+	mu    sync.RWMutex
+	roles map[string]cachedPermissions
+}
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+func NewPermissionCache(ttl time.Duration) *PermissionCache {
+	return &PermissionCache{
+		ttl:   ttl,
+		roles: map[string]cachedPermissions{},
+	}
+}
+
+// Get the permissions of the role, false when not cached or expired
+func (c *PermissionCache) Get(role string) ([]string, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	cached, ok := c.roles[role]
+	if !ok || time.Now().After(cached.expiresAt) {
+		return nil, false
+	}
+	return cached.permissions, true
+}
+
+func (c *PermissionCache) Set(role string, permissions []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.roles[role] = cachedPermissions{
+		permissions: slices.Clone(permissions),
+		expiresAt:   time.Now().Add(c.ttl),
+	}
+}
+
+func (c *PermissionCache) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	clear(c.roles)
+}
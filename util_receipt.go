@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	htmlTemplate "html/template"
+	"text/template"
+	"time"
+)
+
+var receiptFuncs = map[string]any{
+	"date":  func(t time.Time) string { return t.Format(time.DateTime) },
+	"money": func(currency Currency, amount int64) string { return NewMoney(amount, currency).String() },
+}
+
+var receiptTextTemplate = template.Must(template.New("receipt").Funcs(receiptFuncs).Parse(
+	`RECEIPT {{.Number}}
+Issued    : {{date .IssuedAt}}
+Customer  : {{.Customer}}
+{{- if .Cinema}}
+Cinema    : {{.Cinema}}{{if .CinemaAddress}}, {{.CinemaAddress}}{{end}}
+{{- end}}
+Reservation #{{.ReservationID}} ({{.Status}})
+
+{{range .Lines -}}
+{{.Description}}{{if .TicketType}} [{{.TicketType}}]{{end}}  {{money $.Currency .Amount}}
+{{end}}
+Subtotal  : {{money $.Currency .Subtotal}}
+{{- if .Discount}}
+Discount  : -{{money $.Currency .Discount}}{{if .PromoCode}} ({{.PromoCode}}){{end}}
+{{- end}}
+{{- if .PointsDiscount}}
+Points    : -{{money $.Currency .PointsDiscount}}
+{{- end}}
+{{- range .Fees}}
+{{.Name}} : {{money $.Currency .Total}}{{if gt .Quantity 1}} ({{.Quantity}} x {{money $.Currency .Amount}}){{end}}
+{{- end}}
+Tax {{.TaxPercent}} : {{money $.Currency .Tax}}
+Total     : {{money $.Currency .Total}}
+{{- if .PaidWallet}}
+Paid by wallet  : {{money $.Currency .PaidWallet}}
+{{- end}}
+{{- if .PaidGateway}}
+Paid by gateway : {{money $.Currency .PaidGateway}}{{if .PaymentReference}} ({{.PaymentReference}}){{end}}
+{{- end}}
+`))
+
+var receiptHTMLTemplate = htmlTemplate.Must(htmlTemplate.New("receipt").Funcs(receiptFuncs).Parse(
+	`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Receipt {{.Number}}</title></head>
+<body>
+<h1>Receipt {{.Number}}</h1>
+<p>
+Issued: {{date .IssuedAt}}<br>
+Customer: {{.Customer}}<br>
+{{if .Cinema}}Cinema: {{.Cinema}}{{if .CinemaAddress}}, {{.CinemaAddress}}{{end}}<br>{{end}}
+Reservation #{{.ReservationID}} ({{.Status}})
+</p>
+<table>
+{{range .Lines}}<tr><td>{{.Description}}{{if .TicketType}} [{{.TicketType}}]{{end}}</td><td align="right">{{money $.Currency .Amount}}</td></tr>
+{{end}}<tr><td>Subtotal</td><td align="right">{{money $.Currency .Subtotal}}</td></tr>
+{{if .Discount}}<tr><td>Discount{{if .PromoCode}} ({{.PromoCode}}){{end}}</td><td align="right">-{{money $.Currency .Discount}}</td></tr>
+{{end}}{{if .PointsDiscount}}<tr><td>Points</td><td align="right">-{{money $.Currency .PointsDiscount}}</td></tr>
+{{end}}{{range .Fees}}<tr><td>{{.Name}}{{if gt .Quantity 1}} ({{.Quantity}} x {{money $.Currency .Amount}}){{end}}</td><td align="right">{{money $.Currency .Total}}</td></tr>
+{{end}}<tr><td>Tax {{.TaxPercent}}</td><td align="right">{{money $.Currency .Tax}}</td></tr>
+<tr><th align="left">Total</th><th align="right">{{money $.Currency .Total}}</th></tr>
+{{if .PaidWallet}}<tr><td>Paid by wallet</td><td align="right">{{money $.Currency .PaidWallet}}</td></tr>
+{{end}}{{if .PaidGateway}}<tr><td>Paid by gateway{{if .PaymentReference}} ({{.PaymentReference}}){{end}}</td><td align="right">{{money $.Currency .PaidGateway}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+// RenderReceipt render the receipt as plain text or html, e.g. to be sent by email
+func RenderReceipt(receipt *Receipt, format ReceiptFormat) (string, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case ReceiptText:
+		err = receiptTextTemplate.Execute(&buf, receipt)
+	case ReceiptHTML:
+		err = receiptHTMLTemplate.Execute(&buf, receipt)
+	default:
+		return "", NewErr(ErrInput, nil, "receipt format %s is not supported", format)
+	}
+	if err != nil {
+		return "", NewErr(ErrInternal, err, "failed to render receipt")
+	}
+	return buf.String(), nil
+}
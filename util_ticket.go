@@ -0,0 +1,90 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/skip2/go-qrcode"
+)
+
+const ticketTokenPrefix = "T1"
+
+// SignTicketToken encode the claims as T1.<payload>.<hmac-sha256 signature>, both base64url
+func SignTicketToken(secret string, claims TicketClaims) string {
+	payload, _ := json.Marshal(claims)
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return fmt.Sprintf("%s.%s.%s", ticketTokenPrefix, encoded, signTicketPayload(secret, encoded))
+}
+
+// ParseTicketToken verify the signature and return the claims, it does not check the reservation is still valid
+func ParseTicketToken(secret, token string) (*TicketClaims, error) {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 || parts[0] != ticketTokenPrefix {
+		return nil, NewErr(ErrInput, nil, "ticket token is malformed")
+	}
+	expected := signTicketPayload(secret, parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, NewErr(ErrInput, nil, "ticket token signature is invalid")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, NewErr(ErrInput, err, "ticket token is malformed")
+	}
+	var claims TicketClaims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return nil, NewErr(ErrInput, err, "ticket token is malformed")
+	}
+	return &claims, nil
+}
+
+func signTicketPayload(secret, payload string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(ticketTokenPrefix + "." + payload))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TicketQRCodePNG(token string, size int) ([]byte, error) {
+	png, err := qrcode.Encode(token, qrcode.Medium, size)
+	if err != nil {
+		return nil, NewErr(ErrInternal, err, "failed to generate qr code")
+	}
+	return png, nil
+}
+
+func TicketQRCodeDataURI(token string) (string, error) {
+	png, err := TicketQRCodePNG(token, 256)
+	if err != nil {
+		return "", err
+	}
+	return "data:image/png;base64," + base64.StdEncoding.EncodeToString(png), nil
+}
+
+// TicketQRCodeSVG draw every dark module of the qr code as 1x1 rect, scaled by the viewbox
+func TicketQRCodeSVG(token string) ([]byte, error) {
+	code, err := qrcode.New(token, qrcode.Medium)
+	if err != nil {
+		return nil, NewErr(ErrInternal, err, "failed to generate qr code")
+	}
+	bitmap := code.Bitmap()
+	size := len(bitmap)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="256" height="256" shape-rendering="crispEdges">`, size, size)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#fff"/>`, size, size)
+	buf.WriteString(`<path fill="#000" d="`)
+	for y, row := range bitmap {
+		for x, dark := range row {
+			if dark {
+				fmt.Fprintf(&buf, "M%d %dh1v1h-1z", x, y)
+			}
+		}
+	}
+	buf.WriteString(`"/></svg>`)
+	return buf.Bytes(), nil
+}